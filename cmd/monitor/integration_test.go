@@ -29,7 +29,7 @@ func TestMonitorServerStartupServesKeyRoutes(t *testing.T) {
 			C2CIntervalMinutes: 1,
 			ForexIntervalHours: 1,
 			TargetAmounts:      []float64{30},
-			Exchanges:          []string{domain.ExchangeBinance, domain.ExchangeGate, domain.ExchangeOKX},
+			Exchanges:          []string{domain.ExchangeBinance, domain.ExchangeGate, domain.ExchangeOKX, domain.ExchangeBybit, domain.ExchangeHTX, domain.ExchangeBitget},
 		},
 		Database: config.DatabaseConfig{DSN: "integration-test"},
 	}
@@ -39,6 +39,9 @@ func TestMonitorServerStartupServesKeyRoutes(t *testing.T) {
 		domain.ExchangeBinance: staticExchange{name: domain.ExchangeBinance, price: 7.08},
 		domain.ExchangeGate:    staticExchange{name: domain.ExchangeGate, price: 7.09},
 		domain.ExchangeOKX:     staticExchange{name: domain.ExchangeOKX, price: 7.07},
+		domain.ExchangeBybit:   staticExchange{name: domain.ExchangeBybit, price: 7.06},
+		domain.ExchangeHTX:     staticExchange{name: domain.ExchangeHTX, price: 7.05},
+		domain.ExchangeBitget:  staticExchange{name: domain.ExchangeBitget, price: 7.04},
 	}
 
 	svc := service.NewMonitorService(
@@ -92,11 +95,11 @@ func TestMonitorServerStartupServesKeyRoutes(t *testing.T) {
 	if metaResp.Version == "" {
 		t.Fatal("expected non-empty version from meta route")
 	}
-	if len(metaResp.SupportedExchanges) != 3 {
-		t.Fatalf("expected 3 supported exchanges from meta route, got %v", metaResp.SupportedExchanges)
+	if len(metaResp.SupportedExchanges) != 6 {
+		t.Fatalf("expected 6 supported exchanges from meta route, got %v", metaResp.SupportedExchanges)
 	}
-	if len(metaResp.HistoryKeys) != 3 {
-		t.Fatalf("expected 3 history keys from meta route, got %v", metaResp.HistoryKeys)
+	if len(metaResp.HistoryKeys) != 6 {
+		t.Fatalf("expected 6 history keys from meta route, got %v", metaResp.HistoryKeys)
 	}
 
 	var changelogResp struct {
@@ -118,8 +121,8 @@ func TestMonitorServerStartupServesKeyRoutes(t *testing.T) {
 	if err := getJSON(client, server.URL+"/api/config", &configResp); err != nil {
 		t.Fatalf("failed to read config route: %v", err)
 	}
-	if len(configResp.Exchanges) != 3 {
-		t.Fatalf("expected 3 exchanges, got %v", configResp.Exchanges)
+	if len(configResp.Exchanges) != 6 {
+		t.Fatalf("expected 6 exchanges, got %v", configResp.Exchanges)
 	}
 
 	waitFor(t, 3*time.Second, func() error {
@@ -157,7 +160,7 @@ func TestHistoryContractMatchesMetaExchangeMetadata(t *testing.T) {
 			C2CIntervalMinutes: 1,
 			ForexIntervalHours: 1,
 			TargetAmounts:      []float64{30},
-			Exchanges:          []string{domain.ExchangeBinance, domain.ExchangeGate, domain.ExchangeOKX},
+			Exchanges:          []string{domain.ExchangeBinance, domain.ExchangeGate, domain.ExchangeOKX, domain.ExchangeBybit, domain.ExchangeHTX, domain.ExchangeBitget},
 		},
 		Database: config.DatabaseConfig{DSN: "integration-test"},
 	}
@@ -167,6 +170,9 @@ func TestHistoryContractMatchesMetaExchangeMetadata(t *testing.T) {
 		domain.ExchangeBinance: staticExchange{name: domain.ExchangeBinance, price: 7.08},
 		domain.ExchangeGate:    staticExchange{name: domain.ExchangeGate, price: 7.09},
 		domain.ExchangeOKX:     staticExchange{name: domain.ExchangeOKX, price: 7.07},
+		domain.ExchangeBybit:   staticExchange{name: domain.ExchangeBybit, price: 7.06},
+		domain.ExchangeHTX:     staticExchange{name: domain.ExchangeHTX, price: 7.05},
+		domain.ExchangeBitget:  staticExchange{name: domain.ExchangeBitget, price: 7.04},
 	}
 
 	svc := service.NewMonitorService(
@@ -238,7 +244,7 @@ type staticExchange struct {
 	price float64
 }
 
-func (e staticExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64) ([]domain.PricePoint, error) {
+func (e staticExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
 	return []domain.PricePoint{
 		{
 			Exchange:        e.name,
@@ -280,6 +286,7 @@ type memoryRepository struct {
 	alertStates        map[string]*domain.AlertState
 	alertBenchmark     *domain.AlertBenchmark
 	benchmarkOverrides map[float64]*domain.AlertBenchmarkOverride
+	outbox             []*domain.OutboxAlert
 }
 
 func newMemoryRepository() *memoryRepository {
@@ -309,6 +316,14 @@ func (r *memoryRepository) GetPriceHistoryByGranularity(ctx context.Context, fil
 	return r.filteredPrices(filter), nil
 }
 
+func (r *memoryRepository) SaveRoundTripSpreads(ctx context.Context, spreads []*domain.RoundTripSpread) error {
+	return nil
+}
+
+func (r *memoryRepository) GetRoundTripSpreadHistory(ctx context.Context, filter domain.SpreadQueryFilter) ([]*domain.RoundTripSpread, error) {
+	return nil, nil
+}
+
 func (r *memoryRepository) SaveMerchant(ctx context.Context, merchant *domain.Merchant) error {
 	return nil
 }
@@ -349,15 +364,15 @@ func (r *memoryRepository) UpsertAlertState(ctx context.Context, state *domain.A
 	defer r.mu.Unlock()
 
 	copyState := *state
-	r.alertStates[alertStateKey(state.Exchange, state.Side, state.TargetAmount)] = &copyState
+	r.alertStates[alertStateKey(state.Exchange, state.Symbol, state.Fiat, state.Side, state.TargetAmount)] = &copyState
 	return nil
 }
 
-func (r *memoryRepository) DeleteAlertState(ctx context.Context, exchange, side string, amount float64) error {
+func (r *memoryRepository) DeleteAlertState(ctx context.Context, exchange, symbol, fiat, side string, amount float64, payMethod string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
-	delete(r.alertStates, alertStateKey(exchange, side, amount))
+	delete(r.alertStates, alertStateKey(exchange, symbol, fiat, side, amount))
 	return nil
 }
 
@@ -381,6 +396,86 @@ func (r *memoryRepository) GetAlertStates(ctx context.Context) ([]*domain.AlertS
 	return states, nil
 }
 
+func (r *memoryRepository) EnqueueAlert(ctx context.Context, alert *domain.OutboxAlert, record *domain.AlertRecord, state *domain.AlertState) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if state != nil {
+		copyState := *state
+		r.alertStates[alertStateKey(state.Exchange, state.Symbol, state.Fiat, state.Side, state.TargetAmount)] = &copyState
+	}
+	alert.ID = int64(len(r.outbox) + 1)
+	copyAlert := *alert
+	r.outbox = append(r.outbox, &copyAlert)
+	return nil
+}
+
+func (r *memoryRepository) GetDueAlerts(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxAlert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var due []*domain.OutboxAlert
+	for _, alert := range r.outbox {
+		if alert.Status == domain.AlertPending && !alert.NextAttemptAt.After(now) && len(due) < limit {
+			copyAlert := *alert
+			due = append(due, &copyAlert)
+		}
+	}
+	return due, nil
+}
+
+func (r *memoryRepository) ClaimAlert(ctx context.Context, id int64, now, until time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	alert := r.outbox[id-1]
+	if alert.Status != domain.AlertPending || alert.NextAttemptAt.After(now) {
+		return false, nil
+	}
+	alert.NextAttemptAt = until
+	return true, nil
+}
+
+func (r *memoryRepository) UpdateAlertDelivery(ctx context.Context, alert *domain.OutboxAlert) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	copyAlert := *alert
+	r.outbox[alert.ID-1] = &copyAlert
+	return nil
+}
+
+func (r *memoryRepository) GetAlertOutbox(ctx context.Context, filter domain.AlertOutboxFilter) ([]*domain.OutboxAlert, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var alerts []*domain.OutboxAlert
+	for i := len(r.outbox) - 1; i >= 0; i-- {
+		if filter.Status == "" || r.outbox[i].Status == filter.Status {
+			copyAlert := *r.outbox[i]
+			alerts = append(alerts, &copyAlert)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *memoryRepository) RequeueAlert(ctx context.Context, id int64, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if id < 1 || int(id) > len(r.outbox) || r.outbox[id-1].Status != domain.AlertDead {
+		return false, nil
+	}
+	r.outbox[id-1].Status = domain.AlertPending
+	r.outbox[id-1].Attempts = 0
+	r.outbox[id-1].NextAttemptAt = now
+	return true, nil
+}
+
+func (r *memoryRepository) GetAlertHistory(ctx context.Context, filter domain.AlertHistoryFilter) ([]*domain.AlertRecord, int64, error) {
+	return nil, 0, nil
+}
+
 func (r *memoryRepository) UpsertAlertBenchmark(ctx context.Context, benchmark *domain.AlertBenchmark) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -482,6 +577,9 @@ func matchesPriceFilter(point *domain.PricePoint, filter domain.PriceQueryFilter
 	if filter.TargetAmount != nil && point.TargetAmount != *filter.TargetAmount {
 		return false
 	}
+	if point.PayMethodFilter != filter.PayMethodFilter {
+		return false
+	}
 	if filter.Rank > 0 && point.Rank != filter.Rank {
 		return false
 	}
@@ -494,8 +592,8 @@ func matchesPriceFilter(point *domain.PricePoint, filter domain.PriceQueryFilter
 	return true
 }
 
-func alertStateKey(exchange, side string, amount float64) string {
-	return fmt.Sprintf("%s|%s|%.2f", exchange, side, amount)
+func alertStateKey(exchange, symbol, fiat, side string, amount float64) string {
+	return fmt.Sprintf("%s|%s|%s|%s|%.2f", exchange, symbol, fiat, side, amount)
 }
 
 func getJSON(client *http.Client, url string, target any) error {
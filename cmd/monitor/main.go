@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"c2c_monitor/config"
+	"c2c_monitor/internal/alertrender"
 	"c2c_monitor/internal/api"
 	"c2c_monitor/internal/appmeta"
 	"c2c_monitor/internal/domain"
@@ -56,24 +57,62 @@ func main() {
 		os.Exit(1)
 	}
 
-	exchanges := map[string]domain.IExchange{
-		domain.ExchangeBinance: exchange.NewBinanceAdapter(),
-		domain.ExchangeGate:    exchange.NewGateAdapter(),
-		domain.ExchangeOKX:     exchange.NewOKXAdapter(),
+	for _, custom := range cfg.Monitor.CustomExchanges {
+		if err := exchange.RegisterGenericJSON(genericJSONSpec(custom)); err != nil {
+			slog.Error("failed to register custom exchange", "event", "exchange_registry_invalid", "exchange", custom.Name, "error", err)
+			os.Exit(1)
+		}
 	}
 
-	var emailNotifier domain.INotifier
+	exchanges, err := exchange.NewRegisteredAdapters(exchangeTransportOptions(cfg.ExchangeHTTP))
+	if err != nil {
+		slog.Error("failed to build exchange adapters", "event", "exchange_registry_invalid", "error", err)
+		os.Exit(1)
+	}
+
+	var notifiers []domain.INotifier
 	if cfg.Notification.Email.Enabled {
-		emailNotifier = notifier.NewSMTPNotifier(
+		notifiers = append(notifiers, notifier.NewSMTPNotifier(
 			cfg.Notification.Email.SMTPHost,
 			cfg.Notification.Email.SMTPPort,
 			cfg.Notification.Email.Username,
 			cfg.Notification.Email.Password,
 			cfg.Notification.Email.From,
 			cfg.Notification.Email.To,
-		)
-	} else {
-		emailNotifier = notifier.NewDisabledNotifier()
+		))
+	}
+	if webhook := cfg.Notification.Webhook; webhook.Enabled {
+		notifiers = append(notifiers, notifier.NewWebhookNotifier(
+			webhook.URL,
+			webhook.Headers,
+			webhook.Secret,
+			webhook.SignatureHeader,
+			webhook.Timeout(),
+			webhook.MaxRetries,
+		))
+	}
+	var telegram *notifier.TelegramNotifier
+	if settings := cfg.Notification.Telegram; settings.Enabled {
+		telegram = notifier.NewTelegramNotifier(settings.APIBaseURL, settings.BotToken, settings.ChatIDs, settings.Timeout())
+		notifiers = append(notifiers, telegram)
+	}
+	if robot := cfg.Notification.DingTalk; robot.Enabled {
+		notifiers = append(notifiers, notifier.NewDingTalkNotifier(robot.WebhookURL, robot.Secret, robot.Timeout()))
+	}
+	if robot := cfg.Notification.WeCom; robot.Enabled {
+		notifiers = append(notifiers, notifier.NewWeComNotifier(robot.WebhookURL, robot.Timeout()))
+	}
+	if robot := cfg.Notification.Feishu; robot.Enabled {
+		notifiers = append(notifiers, notifier.NewFeishuNotifier(robot.WebhookURL, robot.Secret, robot.Timeout()))
+	}
+	var alertNotifier domain.INotifier
+	switch len(notifiers) {
+	case 0:
+		alertNotifier = notifier.NewDisabledNotifier()
+	case 1:
+		alertNotifier = notifiers[0]
+	default:
+		alertNotifier = notifier.NewMultiNotifier(notifiers...)
 	}
 
 	svc := service.NewMonitorService(
@@ -84,13 +123,41 @@ func main() {
 			forex.NewOpenERAdapter(),
 			forex.NewHexaRateAdapter(),
 		),
-		emailNotifier,
+		alertNotifier,
 	)
 
+	if cfg.LeaderElection.Enabled {
+		election := cfg.LeaderElection
+		if election.Identity == "" {
+			hostname, err := os.Hostname()
+			if err != nil {
+				slog.Error("failed to resolve leader identity", "event", "leader_identity_failed", "error", err)
+				os.Exit(1)
+			}
+			election.Identity = hostname
+		}
+		svc.SetLeaderLease(repo, election)
+	}
+	svc.SetServiceDownLog(cfg.App.ServiceDownLog)
+	svc.SetDigest(cfg.Notification.Digest)
+	if dir := cfg.Notification.TemplatesDir; dir != "" {
+		renderer, err := alertrender.New(dir)
+		if err != nil {
+			slog.Error("failed to load alert templates", "event", "alert_templates_invalid", "dir", dir, "error", err)
+			os.Exit(1)
+		}
+		svc.SetAlertTemplates(renderer)
+		slog.Info("loaded alert templates", "event", "alert_templates_loaded", "dir", dir)
+	}
+
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
 	go svc.Start(ctx)
+	if telegram != nil && cfg.Notification.Telegram.Commands {
+		bot := api.NewTelegramBot(svc, telegram, cfg.Notification.Telegram.ChatIDs, cfg.Notification.Telegram.AdminChatIDs)
+		go bot.Run(ctx)
+	}
 
 	router := api.SetupRouter(svc, cfg)
 	server := &http.Server{
@@ -119,6 +186,7 @@ func main() {
 		"version", appmeta.Version,
 		"addr", server.Addr,
 		"exchanges", cfg.Monitor.Exchanges,
+		"leader_election", cfg.LeaderElection.Enabled,
 	)
 	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		slog.Error("http server exited unexpectedly", "event", "http_server_failed", "error", err)
@@ -132,3 +200,49 @@ func defaultConfigPath() string {
 	}
 	return "config/config.yaml"
 }
+
+func exchangeTransportOptions(settings map[string]config.ExchangeHTTPConfig) map[string]exchange.TransportOptions {
+	options := make(map[string]exchange.TransportOptions, len(settings))
+	for name, setting := range settings {
+		headers := make(http.Header, len(setting.Headers))
+		for key, value := range setting.Headers {
+			headers.Set(key, value)
+		}
+		// The version was validated when the config was loaded.
+		minVersion, _ := config.TLSVersion(setting.TLS.MinVersion)
+		options[name] = exchange.TransportOptions{
+			Proxy:              setting.Proxy,
+			Headers:            headers,
+			Timeout:            time.Duration(setting.TimeoutSeconds) * time.Second,
+			InsecureSkipVerify: setting.TLS.InsecureSkipVerify,
+			TLSServerName:      setting.TLS.ServerName,
+			TLSMinVersion:      minVersion,
+		}
+	}
+	return options
+}
+
+func genericJSONSpec(custom config.CustomExchangeConfig) exchange.GenericJSONSpec {
+	return exchange.GenericJSONSpec{
+		Name:         custom.Name,
+		Aliases:      custom.Aliases,
+		Endpoint:     custom.Endpoint,
+		Method:       custom.Method,
+		Headers:      custom.Headers,
+		Body:         custom.Body,
+		Sides:        custom.Sides,
+		ItemsPath:    custom.ItemsPath,
+		SuccessPath:  custom.SuccessPath,
+		SuccessValue: custom.SuccessValue,
+		Fields: exchange.GenericJSONFields{
+			Price:           custom.Fields.Price,
+			MinAmount:       custom.Fields.MinAmount,
+			MaxAmount:       custom.Fields.MaxAmount,
+			AvailableAmount: custom.Fields.AvailableAmount,
+			Merchant:        custom.Fields.Merchant,
+			MerchantID:      custom.Fields.MerchantID,
+			PayMethods:      custom.Fields.PayMethods,
+		},
+		PayMethodCodes: custom.PayMethodCodes,
+	}
+}
@@ -12,8 +12,8 @@ func main() {
 
 	// 1. Test Binance
 	fmt.Println("=== Testing Binance C2C Crawler (Amount: 100,000) ===")
-	bnAdapter := exchange.NewBinanceAdapter()
-	bnPrices, err := bnAdapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 100000)
+	bnAdapter := exchange.NewBinanceAdapter(exchange.DefaultTransport())
+	bnPrices, err := bnAdapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 100000, "")
 	if err != nil {
 		fmt.Printf("❌ Binance Error: %v\n", err)
 	} else {
@@ -26,8 +26,8 @@ func main() {
 	fmt.Println()
 
 	fmt.Println("=== Testing Gate.io C2C Crawler ===")
-	gateAdapter := exchange.NewGateAdapter()
-	gatePrices, err := gateAdapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 100)
+	gateAdapter := exchange.NewGateAdapter(exchange.DefaultTransport())
+	gatePrices, err := gateAdapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 100, "")
 	if err != nil {
 		fmt.Printf("❌ Gate Error: %v\n", err)
 	} else {
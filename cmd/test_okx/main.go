@@ -9,12 +9,12 @@ import (
 )
 
 func main() {
-	adapter := exchange.NewOKXAdapter()
+	adapter := exchange.NewOKXAdapter(exchange.DefaultTransport())
 	ctx := context.Background()
 
 	// Test 1: Lowest Price (Amount = 0)
 	fmt.Println("--- Testing OKX Amount=0 (Lowest Price) ---")
-	lowestPrices, err := adapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 0)
+	lowestPrices, err := adapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 0, "")
 	if err != nil {
 		log.Fatalf("Error fetching lowest prices: %v", err)
 	}
@@ -30,7 +30,7 @@ func main() {
 
 	// Test 2: Amount = 30
 	fmt.Println("\n--- Testing OKX Amount=30 ---")
-	tier30Prices, err := adapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 30)
+	tier30Prices, err := adapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 30, "")
 	if err != nil {
 		log.Fatalf("Error fetching tier 30 prices: %v", err)
 	}
@@ -46,7 +46,7 @@ func main() {
 
 	// Test 3: Amount = 5000
 	fmt.Println("\n--- Testing OKX Amount=5000 ---")
-	tier5000Prices, err := adapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 5000)
+	tier5000Prices, err := adapter.GetTopPrices(ctx, "USDT", "CNY", "BUY", 5000, "")
 	if err != nil {
 		log.Fatalf("Error fetching tier 5000 prices: %v", err)
 	}
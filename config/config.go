@@ -3,6 +3,10 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+	"c2c_monitor/internal/schedule"
 
 	"github.com/spf13/viper"
 )
@@ -12,28 +16,647 @@ type Config struct {
 	Monitor      MonitorConfig      `mapstructure:"monitor"`
 	Database     DatabaseConfig     `mapstructure:"database"`
 	Notification NotificationConfig `mapstructure:"notification"`
+	// LeaderElection lets several replicas share one database; only the
+	// holder of the lease collects prices and sends alerts.
+	LeaderElection LeaderElectionConfig `mapstructure:"leader_election"`
+	// ExchangeHTTP holds per-exchange HTTP settings keyed by exchange name.
+	// Adapters are built once at startup, so changes need a restart.
+	ExchangeHTTP map[string]ExchangeHTTPConfig `mapstructure:"exchange_http"`
+}
+
+// ExchangeHTTPConfig configures how one exchange is reached.
+type ExchangeHTTPConfig struct {
+	// Proxy is an http://, https:// or socks5:// URL.
+	Proxy string `mapstructure:"proxy"`
+	// Headers override the adapter's built-in headers; an empty value removes
+	// the header.
+	Headers        map[string]string `mapstructure:"headers"`
+	TimeoutSeconds int               `mapstructure:"timeout_seconds"`
+	TLS            ExchangeTLSConfig `mapstructure:"tls"`
+}
+
+type ExchangeTLSConfig struct {
+	InsecureSkipVerify bool   `mapstructure:"insecure_skip_verify"`
+	ServerName         string `mapstructure:"server_name"`
+	MinVersion         string `mapstructure:"min_version"` // "1.2" or "1.3"
 }
 
 type AppConfig struct {
 	Port           int      `mapstructure:"port"`
 	AdminToken     string   `mapstructure:"admin_token"`
 	AllowedOrigins []string `mapstructure:"allowed_origins"`
+	// ServiceDownLog is the file service down events are appended to, as
+	// JSON lines; relative paths start at the working directory.
+	ServiceDownLog string `mapstructure:"service_down_log"`
 }
 
 type MonitorConfig struct {
-	C2CIntervalMinutes int       `mapstructure:"c2c_interval_minutes" json:"c2c_interval_minutes"`
-	ForexIntervalHours int       `mapstructure:"forex_interval_hours" json:"forex_interval_hours"`
-	ForexMaxAgeHours   int       `mapstructure:"forex_max_age_hours" json:"forex_max_age_hours"`
-	TargetAmounts      []float64 `mapstructure:"target_amounts" json:"target_amounts"`
-	Exchanges          []string  `mapstructure:"exchanges" json:"exchanges"`
+	C2CIntervalMinutes int `mapstructure:"c2c_interval_minutes" json:"c2c_interval_minutes"`
+	// C2CCron and ForexCron are five-field cron expressions that replace
+	// C2CIntervalMinutes and ForexIntervalHours when set.
+	C2CCron   string `mapstructure:"c2c_cron" json:"c2c_cron,omitempty"`
+	ForexCron string `mapstructure:"forex_cron" json:"forex_cron,omitempty"`
+	// ScheduleTimezone is the IANA zone cron expressions and blackout windows
+	// are evaluated in; empty uses the server's local zone.
+	ScheduleTimezone string `mapstructure:"schedule_timezone" json:"schedule_timezone,omitempty"`
+	// CollectionBlackouts are daily windows, such as exchange maintenance,
+	// during which C2C runs are postponed.
+	CollectionBlackouts []BlackoutWindowConfig `mapstructure:"collection_blackouts" json:"collection_blackouts,omitempty"`
+	// AdaptivePolling polls a tier faster while its best BUY price is close to
+	// the alert benchmark.
+	AdaptivePolling AdaptivePollingConfig `mapstructure:"adaptive_polling" json:"adaptive_polling"`
+	// PollSchedules override the C2C interval or cron for matching tiers; the
+	// first matching schedule wins.
+	PollSchedules      []PollScheduleConfig `mapstructure:"poll_schedules" json:"poll_schedules,omitempty"`
+	ForexIntervalHours int                  `mapstructure:"forex_interval_hours" json:"forex_interval_hours"`
+	ForexMaxAgeHours   int                  `mapstructure:"forex_max_age_hours" json:"forex_max_age_hours"`
+	TargetAmounts      []float64            `mapstructure:"target_amounts" json:"target_amounts"`
+	Exchanges          []string             `mapstructure:"exchanges" json:"exchanges"`
+	// Depth is how many ranked ads are kept per amount tier; ExchangeDepths
+	// overrides it for individual exchanges.
+	Depth          int            `mapstructure:"depth" json:"depth"`
+	ExchangeDepths map[string]int `mapstructure:"exchange_depths" json:"exchange_depths,omitempty"`
+	// RateLimit budgets the requests sent to each exchange;
+	// ExchangeRateLimits overrides it for individual exchanges.
+	RateLimit          RateLimitConfig            `mapstructure:"rate_limit" json:"rate_limit"`
+	ExchangeRateLimits map[string]RateLimitConfig `mapstructure:"exchange_rate_limits" json:"exchange_rate_limits,omitempty"`
+	// Sides lists the user-perspective trade directions to collect ("BUY", "SELL").
+	Sides []string `mapstructure:"sides" json:"sides"`
+	// RoundTripAlertSpread is the minimum SELL-minus-BUY profit per unit that
+	// triggers a round-trip alert; 0 disables round-trip alerts.
+	RoundTripAlertSpread float64 `mapstructure:"round_trip_alert_spread" json:"round_trip_alert_spread"`
+	// RoundTripMaxPriceAgeMinutes is how old the best ad of the other side may
+	// be for a round-trip spread, so tiers polled on different schedules still
+	// pair up; 0 selects 10 minutes.
+	RoundTripMaxPriceAgeMinutes int `mapstructure:"round_trip_max_price_age_minutes" json:"round_trip_max_price_age_minutes"`
+	// Markets is the symbol/fiat matrix to collect; empty means USDT/CNY only.
+	Markets []MarketConfig `mapstructure:"markets" json:"markets"`
+	// PayMethodFilters adds tiers restricted to one canonical pay method
+	// (e.g. 银行卡). The unfiltered tier is always collected as well.
+	PayMethodFilters []string `mapstructure:"pay_method_filters" json:"pay_method_filters"`
+	// MerchantFilter excludes ads from merchants with a weak track record
+	// before ranking.
+	MerchantFilter MerchantFilterConfig `mapstructure:"merchant_filter" json:"merchant_filter"`
+	// CircuitBreaker stops polling an exchange that keeps failing.
+	CircuitBreaker CircuitBreakerConfig `mapstructure:"circuit_breaker" json:"circuit_breaker"`
+	// AlertDelivery retries queued alerts that could not be sent.
+	AlertDelivery AlertDeliveryConfig `mapstructure:"alert_delivery" json:"alert_delivery"`
+	// CustomExchanges declares generic JSON venues. They are registered once at
+	// startup, so they are not exposed to or editable through the config API.
+	CustomExchanges []CustomExchangeConfig `mapstructure:"custom_exchanges" json:"-"`
+}
+
+// PollScheduleConfig polls the matching amount tiers every IntervalMinutes,
+// or at the times of Cron. Empty Exchange, Market or Amounts match every
+// exchange, market or amount.
+type PollScheduleConfig struct {
+	Exchange        string    `mapstructure:"exchange" json:"exchange,omitempty"`
+	Market          string    `mapstructure:"market" json:"market,omitempty"` // e.g. "USDT/CNY"
+	Amounts         []float64 `mapstructure:"amounts" json:"amounts,omitempty"`
+	IntervalMinutes int       `mapstructure:"interval_minutes" json:"interval_minutes,omitempty"`
+	Cron            string    `mapstructure:"cron" json:"cron,omitempty"`
+}
+
+// AdaptivePollingConfig shortens the poll interval of a tier to
+// IntervalSeconds (default 30) while its best BUY price is at most
+// ProximityPercent above the effective alert benchmark. A zero
+// ProximityPercent disables adaptive polling.
+type AdaptivePollingConfig struct {
+	ProximityPercent float64 `mapstructure:"proximity_percent" json:"proximity_percent"`
+	IntervalSeconds  int     `mapstructure:"interval_seconds" json:"interval_seconds"`
+}
+
+// Interval returns the poll interval of tiers close to the benchmark.
+func (c AdaptivePollingConfig) Interval() time.Duration {
+	if c.IntervalSeconds > 0 {
+		return time.Duration(c.IntervalSeconds) * time.Second
+	}
+	return 30 * time.Second
+}
+
+// Near reports whether price is close enough to benchmark to poll faster.
+func (c AdaptivePollingConfig) Near(price, benchmark float64) bool {
+	return c.ProximityPercent > 0 && benchmark > 0 && price > 0 && price <= benchmark*(1+c.ProximityPercent/100)
+}
+
+// BlackoutWindowConfig is a daily "HH:MM" range, optionally limited to
+// weekdays ("mon".."sun"), during which an exchange is not polled. An empty
+// Exchange applies to every exchange; End before Start wraps past midnight.
+type BlackoutWindowConfig struct {
+	Exchange string   `mapstructure:"exchange" json:"exchange,omitempty"`
+	Start    string   `mapstructure:"start" json:"start"`
+	End      string   `mapstructure:"end" json:"end"`
+	Weekdays []string `mapstructure:"weekdays" json:"weekdays,omitempty"`
+}
+
+// Matches reports whether the schedule applies to an amount tier.
+func (p PollScheduleConfig) Matches(exchange, market string, amount float64) bool {
+	if p.Exchange != "" && p.Exchange != exchange {
+		return false
+	}
+	if p.Market != "" && p.Market != market {
+		return false
+	}
+	if len(p.Amounts) == 0 {
+		return true
+	}
+	for _, configured := range p.Amounts {
+		if configured == amount {
+			return true
+		}
+	}
+	return false
+}
+
+// MerchantFilterConfig holds the minimum track record an advertiser needs.
+// Zero values disable a threshold, and a stat the venue does not report never
+// excludes an ad.
+type MerchantFilterConfig struct {
+	MinCompletionRate       float64 `mapstructure:"min_completion_rate" json:"min_completion_rate"` // Percent, 0-100
+	MinMonthOrders          int     `mapstructure:"min_month_orders" json:"min_month_orders"`
+	RequireVerifiedMerchant bool    `mapstructure:"require_verified_merchant" json:"require_verified_merchant"`
+	MaxAvgReleaseSeconds    float64 `mapstructure:"max_avg_release_seconds" json:"max_avg_release_seconds"`
+}
+
+// Allows reports whether an advertiser meets every enabled threshold.
+func (f MerchantFilterConfig) Allows(reputation domain.MerchantReputation) bool {
+	if f.MinCompletionRate > 0 && reputation.CompletionRate != nil && *reputation.CompletionRate < f.MinCompletionRate {
+		return false
+	}
+	if f.MinMonthOrders > 0 && reputation.MonthOrders != nil && *reputation.MonthOrders < f.MinMonthOrders {
+		return false
+	}
+	if f.RequireVerifiedMerchant && reputation.VerifiedMerchant != nil && !*reputation.VerifiedMerchant {
+		return false
+	}
+	if f.MaxAvgReleaseSeconds > 0 && reputation.AvgReleaseSeconds != nil && *reputation.AvgReleaseSeconds > f.MaxAvgReleaseSeconds {
+		return false
+	}
+	return true
+}
+
+// RateLimitConfig is a token bucket refilled at RequestsPerSecond and holding
+// up to Burst requests. A zero rate disables limiting.
+type RateLimitConfig struct {
+	RequestsPerSecond float64 `mapstructure:"requests_per_second" json:"requests_per_second"`
+	Burst             int     `mapstructure:"burst" json:"burst"`
+}
+
+// BurstSize returns the bucket capacity, at least one request.
+func (c RateLimitConfig) BurstSize() int {
+	if c.Burst > 0 {
+		return c.Burst
+	}
+	return 1
+}
+
+// CircuitBreakerConfig controls the per-exchange circuit breaker. It opens
+// after FailureThreshold consecutive failed requests and stays open for
+// CooldownSeconds, doubling after every failed probe up to
+// MaxCooldownSeconds. Zero values use the defaults.
+type CircuitBreakerConfig struct {
+	FailureThreshold   int `mapstructure:"failure_threshold" json:"failure_threshold"`
+	CooldownSeconds    int `mapstructure:"cooldown_seconds" json:"cooldown_seconds"`
+	MaxCooldownSeconds int `mapstructure:"max_cooldown_seconds" json:"max_cooldown_seconds"`
+}
+
+// Threshold returns the consecutive failures that open the circuit (default 5).
+func (c CircuitBreakerConfig) Threshold() int {
+	if c.FailureThreshold > 0 {
+		return c.FailureThreshold
+	}
+	return 5
+}
+
+// Cooldown returns the cool-off after the circuit first opens (default 1m).
+func (c CircuitBreakerConfig) Cooldown() time.Duration {
+	if c.CooldownSeconds > 0 {
+		return time.Duration(c.CooldownSeconds) * time.Second
+	}
+	return time.Minute
+}
+
+// MaxCooldown caps the exponential cool-off (default 30m).
+func (c CircuitBreakerConfig) MaxCooldown() time.Duration {
+	if c.MaxCooldownSeconds > 0 {
+		return time.Duration(c.MaxCooldownSeconds) * time.Second
+	}
+	return 30 * time.Minute
+}
+
+type AlertDeliveryConfig struct {
+	MaxAttempts     int `mapstructure:"max_attempts" json:"max_attempts"`
+	RetrySeconds    int `mapstructure:"retry_seconds" json:"retry_seconds"`
+	MaxRetrySeconds int `mapstructure:"max_retry_seconds" json:"max_retry_seconds"`
+}
+
+// Attempts returns how many deliveries are tried before an alert is dead
+// (default 8).
+func (c AlertDeliveryConfig) Attempts() int {
+	if c.MaxAttempts > 0 {
+		return c.MaxAttempts
+	}
+	return 8
+}
+
+// RetryDelay returns the wait after the first failed delivery (default 30s).
+func (c AlertDeliveryConfig) RetryDelay() time.Duration {
+	if c.RetrySeconds > 0 {
+		return time.Duration(c.RetrySeconds) * time.Second
+	}
+	return 30 * time.Second
+}
+
+// MaxRetryDelay caps the exponential retry delay (default 30m).
+func (c AlertDeliveryConfig) MaxRetryDelay() time.Duration {
+	if c.MaxRetrySeconds > 0 {
+		return time.Duration(c.MaxRetrySeconds) * time.Second
+	}
+	return 30 * time.Minute
+}
+
+// Backoff returns the wait before the next delivery after attempts failed
+// ones; it doubles per failure up to MaxRetryDelay.
+func (c AlertDeliveryConfig) Backoff(attempts int) time.Duration {
+	delay := c.RetryDelay()
+	for i := 1; i < attempts && delay < c.MaxRetryDelay(); i++ {
+		delay *= 2
+	}
+	return min(delay, c.MaxRetryDelay())
+}
+
+// CustomExchangeConfig declares a venue served by the generic JSON adapter.
+// Endpoint and Body may use the {symbol}, {fiat}, {side}, {amount} and
+// {pay_method} placeholders; Sides maps BUY/SELL to the venue's {side} value. Paths are
+// dot separated, with numeric indexes and "*" to expand arrays.
+type CustomExchangeConfig struct {
+	Name           string               `mapstructure:"name"`
+	Aliases        []string             `mapstructure:"aliases"`
+	Endpoint       string               `mapstructure:"endpoint"`
+	Method         string               `mapstructure:"method"`
+	Headers        map[string]string    `mapstructure:"headers"`
+	Body           string               `mapstructure:"body"`
+	Sides          map[string]string    `mapstructure:"sides"`
+	ItemsPath      string               `mapstructure:"items_path"`
+	SuccessPath    string               `mapstructure:"success_path"`
+	SuccessValue   string               `mapstructure:"success_value"`
+	Fields         CustomExchangeFields `mapstructure:"fields"`
+	PayMethodCodes map[string]string    `mapstructure:"pay_method_codes"`
+}
+
+// CustomExchangeFields are the item-relative paths of the ad fields.
+type CustomExchangeFields struct {
+	Price           string `mapstructure:"price"`
+	MinAmount       string `mapstructure:"min_amount"`
+	MaxAmount       string `mapstructure:"max_amount"`
+	AvailableAmount string `mapstructure:"available_amount"`
+	Merchant        string `mapstructure:"merchant"`
+	MerchantID      string `mapstructure:"merchant_id"`
+	PayMethods      string `mapstructure:"pay_methods"`
+}
+
+// MarketConfig describes one symbol/fiat market. Empty TargetAmounts inherit
+// the global tiers; ForexBase is the currency whose rate against Fiat is the
+// alert reference (e.g. USD for USDT/CNY), and empty disables benchmark alerts.
+type MarketConfig struct {
+	Symbol        string    `mapstructure:"symbol" json:"symbol"`
+	Fiat          string    `mapstructure:"fiat" json:"fiat"`
+	TargetAmounts []float64 `mapstructure:"target_amounts" json:"target_amounts,omitempty"`
+	ForexBase     string    `mapstructure:"forex_base" json:"forex_base,omitempty"`
+}
+
+// Key returns the market identifier used in storage and API parameters.
+func (m MarketConfig) Key() string {
+	return domain.MarketKey(m.Symbol, m.Fiat)
+}
+
+// ForexPair returns the reference Forex pair (e.g. USDCNY), or "" when the
+// market has no Forex reference.
+func (m MarketConfig) ForexPair() string {
+	if m.ForexBase == "" {
+		return ""
+	}
+	return m.ForexBase + m.Fiat
+}
+
+// DefaultMarket is the market collected when none are configured.
+func DefaultMarket() MarketConfig {
+	return MarketConfig{Symbol: "USDT", Fiat: "CNY", ForexBase: "USD"}
+}
+
+// CollectedMarkets returns the configured markets, defaulting to USDT/CNY.
+func (c MonitorConfig) CollectedMarkets() []MarketConfig {
+	if len(c.Markets) == 0 {
+		return []MarketConfig{DefaultMarket()}
+	}
+	return c.Markets
+}
+
+// Market looks up a collected market by key; an empty key selects the first one.
+func (c MonitorConfig) Market(key string) (MarketConfig, bool) {
+	markets := c.CollectedMarkets()
+	if key == "" {
+		return markets[0], true
+	}
+	for _, market := range markets {
+		if market.Key() == key {
+			return market, true
+		}
+	}
+	return MarketConfig{}, false
+}
+
+// AmountsFor returns the amount tiers of a market, inheriting the global tiers.
+func (c MonitorConfig) AmountsFor(market MarketConfig) []float64 {
+	if len(market.TargetAmounts) > 0 {
+		return market.TargetAmounts
+	}
+	return c.TargetAmounts
+}
+
+// ForexPairs returns the distinct Forex pairs referenced by collected markets.
+func (c MonitorConfig) ForexPairs() []string {
+	var pairs []string
+	seen := make(map[string]struct{})
+	for _, market := range c.CollectedMarkets() {
+		pair := market.ForexPair()
+		if pair == "" {
+			continue
+		}
+		if _, exists := seen[pair]; exists {
+			continue
+		}
+		seen[pair] = struct{}{}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// CollectedSides returns the trade directions to collect, defaulting to BUY.
+func (c MonitorConfig) CollectedSides() []string {
+	if len(c.Sides) == 0 {
+		return []string{domain.SideBuy}
+	}
+	return c.Sides
+}
+
+// HasSide reports whether a trade direction is collected.
+func (c MonitorConfig) HasSide(side string) bool {
+	for _, configured := range c.CollectedSides() {
+		if configured == side {
+			return true
+		}
+	}
+	return false
+}
+
+// CollectedPayMethods returns the pay-method tiers to collect: "" for the
+// unfiltered tier followed by the configured filters.
+func (c MonitorConfig) CollectedPayMethods() []string {
+	return append([]string{""}, c.PayMethodFilters...)
+}
+
+// HasPayMethod reports whether a pay-method tier is collected.
+func (c MonitorConfig) HasPayMethod(payMethod string) bool {
+	for _, configured := range c.CollectedPayMethods() {
+		if configured == payMethod {
+			return true
+		}
+	}
+	return false
+}
+
+// C2CCronFor returns the cron expression an amount tier is polled on, or ""
+// when it is polled every C2CInterval.
+func (c MonitorConfig) C2CCronFor(exchange, market string, amount float64) string {
+	for _, schedule := range c.PollSchedules {
+		if schedule.Matches(exchange, market, amount) {
+			return schedule.Cron
+		}
+	}
+	return c.C2CCron
+}
+
+// ScheduleLocation returns the zone of cron expressions and blackout windows.
+func (c MonitorConfig) ScheduleLocation() *time.Location {
+	if c.ScheduleTimezone != "" {
+		if location, err := time.LoadLocation(c.ScheduleTimezone); err == nil {
+			return location
+		}
+	}
+	return time.Local
+}
+
+// BlackoutUntil reports whether an exchange is in a blackout window at t and
+// when the window, including any window adjoining it, ends. Windows covering
+// the whole week are rejected by validation; should they still chain, the
+// result stops a week after t.
+func (c MonitorConfig) BlackoutUntil(exchange string, t time.Time) (time.Time, bool) {
+	windows := c.blackoutWindows(exchange)
+	var until time.Time
+	current := t.In(c.ScheduleLocation())
+	limit := current.Add(maxBlackoutSpan)
+	for extended := true; extended && current.Before(limit); {
+		extended = false
+		for _, window := range windows {
+			if end, ok := window.Until(current); ok && end.After(current) {
+				until, current, extended = end, end, true
+			}
+		}
+	}
+	if until.After(limit) {
+		until = limit
+	}
+	return until, !until.IsZero()
+}
+
+// maxBlackoutSpan bounds how far adjoining blackout windows are chained.
+const maxBlackoutSpan = 7 * 24 * time.Hour
+
+// blackoutWindows returns the valid blackout windows that apply to an
+// exchange.
+func (c MonitorConfig) blackoutWindows(exchange string) []schedule.Window {
+	var windows []schedule.Window
+	for _, blackout := range c.CollectionBlackouts {
+		if blackout.Exchange != "" && blackout.Exchange != exchange {
+			continue
+		}
+		window, err := schedule.ParseWindow(blackout.Start, blackout.End, blackout.Weekdays)
+		if err != nil {
+			continue
+		}
+		windows = append(windows, window)
+	}
+	return windows
+}
+
+// C2CInterval returns how often an amount tier is polled.
+func (c MonitorConfig) C2CInterval(exchange, market string, amount float64) time.Duration {
+	minutes := c.C2CIntervalMinutes
+	for _, schedule := range c.PollSchedules {
+		if schedule.Matches(exchange, market, amount) {
+			minutes = schedule.IntervalMinutes
+			break
+		}
+	}
+	if minutes <= 0 {
+		minutes = 3
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// RateLimitFor returns the request budget of an exchange.
+func (c MonitorConfig) RateLimitFor(exchange string) RateLimitConfig {
+	if limit, ok := c.ExchangeRateLimits[exchange]; ok {
+		return limit
+	}
+	return c.RateLimit
+}
+
+// RoundTripMaxPriceAge returns how old a best ad may be to enter a round-trip
+// spread, 10 minutes by default.
+func (c MonitorConfig) RoundTripMaxPriceAge() time.Duration {
+	if c.RoundTripMaxPriceAgeMinutes <= 0 {
+		return 10 * time.Minute
+	}
+	return time.Duration(c.RoundTripMaxPriceAgeMinutes) * time.Minute
+}
+
+// DepthFor returns the number of ranked ads to keep for an exchange.
+func (c MonitorConfig) DepthFor(exchange string) int {
+	if depth, ok := c.ExchangeDepths[exchange]; ok && depth > 0 {
+		return depth
+	}
+	if c.Depth > 0 {
+		return c.Depth
+	}
+	return 1
 }
 
 type DatabaseConfig struct {
 	DSN string `mapstructure:"dsn"`
 }
 
+type LeaderElectionConfig struct {
+	Enabled   bool   `mapstructure:"enabled"`
+	LeaseName string `mapstructure:"lease_name"`
+	// Identity names this replica in the lease; empty uses the hostname, which
+	// is the pod name on Kubernetes.
+	Identity string `mapstructure:"identity"`
+	// A leader that cannot renew within LeaseSeconds is replaced; it renews
+	// every RenewSeconds.
+	LeaseSeconds int `mapstructure:"lease_seconds"`
+	RenewSeconds int `mapstructure:"renew_seconds"`
+}
+
 type NotificationConfig struct {
-	Email EmailConfig `mapstructure:"email"`
+	Email    EmailConfig    `mapstructure:"email"`
+	Webhook  WebhookConfig  `mapstructure:"webhook"`
+	Telegram TelegramConfig `mapstructure:"telegram"`
+	DingTalk RobotConfig    `mapstructure:"dingtalk"`
+	WeCom    RobotConfig    `mapstructure:"wecom"`
+	Feishu   RobotConfig    `mapstructure:"feishu"`
+	Digest   DigestConfig   `mapstructure:"digest"`
+	// TemplatesDir holds alert templates that override the built-in ones:
+	// subject.tmpl, html.tmpl, markdown.tmpl, text.tmpl and json.tmpl. Files
+	// only need to define the templates they change. Read at startup.
+	TemplatesDir string `mapstructure:"templates_dir"`
+}
+
+// WebhookConfig posts every alert as JSON to an HTTP endpoint, alongside
+// email when both are enabled.
+type WebhookConfig struct {
+	Enabled bool   `mapstructure:"enabled"`
+	URL     string `mapstructure:"url"`
+	// Headers are added to every request, e.g. an Authorization token.
+	Headers map[string]string `mapstructure:"headers"`
+	// Secret signs each request: X-C2C-Timestamp carries its Unix time and
+	// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of
+	// "<timestamp>.<body>". Requests are unsigned when it is empty.
+	Secret          string `mapstructure:"secret"`
+	SignatureHeader string `mapstructure:"signature_header"`
+	TimeoutSeconds  int    `mapstructure:"timeout_seconds"`
+	// MaxRetries retries network errors, 429 and 5xx responses within one
+	// delivery attempt; the alert outbox retries the delivery after that.
+	MaxRetries int `mapstructure:"max_retries"`
+}
+
+// Timeout returns the timeout of one request, 10 seconds by default.
+func (c WebhookConfig) Timeout() time.Duration {
+	if c.TimeoutSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// TelegramConfig sends alerts as plain text to Telegram chats through a bot,
+// alongside the other enabled channels.
+type TelegramConfig struct {
+	Enabled  bool   `mapstructure:"enabled"`
+	BotToken string `mapstructure:"bot_token"`
+	// ChatIDs are numeric chat ids or @channel names.
+	ChatIDs []string `mapstructure:"chat_ids"`
+	// APIBaseURL points at the Bot API, or a self-hosted Bot API server.
+	APIBaseURL     string `mapstructure:"api_base_url"`
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"`
+	// Commands answers /status, /price and /benchmark from the numeric chats
+	// in ChatIDs and AdminChatIDs, polling only on the leader. Only
+	// AdminChatIDs may run /reset.
+	Commands     bool     `mapstructure:"commands"`
+	AdminChatIDs []string `mapstructure:"admin_chat_ids"`
+}
+
+// Timeout returns the timeout of one Bot API request, 10 seconds by default.
+func (c TelegramConfig) Timeout() time.Duration {
+	if c.TimeoutSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// RobotConfig sends alerts as Markdown to a DingTalk, WeCom or Feishu group
+// robot, alongside the other enabled channels.
+type RobotConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// WebhookURL is the robot's URL, including its access token or key.
+	WebhookURL string `mapstructure:"webhook_url"`
+	// Secret signs every request with the current timestamp, for DingTalk and
+	// Feishu robots with signature verification turned on. WeCom robots have
+	// no signing.
+	Secret         string `mapstructure:"secret"`
+	TimeoutSeconds int    `mapstructure:"timeout_seconds"`
+}
+
+// Timeout returns the timeout of one request, 10 seconds by default.
+func (c RobotConfig) Timeout() time.Duration {
+	if c.TimeoutSeconds <= 0 {
+		return 10 * time.Second
+	}
+	return time.Duration(c.TimeoutSeconds) * time.Second
+}
+
+// DigestConfig schedules a periodic market summary sent through the notifier.
+type DigestConfig struct {
+	Enabled bool `mapstructure:"enabled"`
+	// Cron is evaluated in monitor.schedule_timezone; the default sends the
+	// digest daily at 09:00.
+	Cron string `mapstructure:"cron"`
+	// PeriodHours is how far back the digest looks. Periods longer than a week
+	// are summarized from daily instead of hourly best prices.
+	PeriodHours int `mapstructure:"period_hours"`
+	// Recipients replace notification.email.to for the digest when set.
+	Recipients []string `mapstructure:"recipients"`
+	// TopMerchants is how many merchants are listed per tier, ranked by how
+	// often they offered the best price.
+	TopMerchants int `mapstructure:"top_merchants"`
+}
+
+// Period returns how far back the digest looks, one day by default.
+func (c DigestConfig) Period() time.Duration {
+	if c.PeriodHours <= 0 {
+		return 24 * time.Hour
+	}
+	return time.Duration(c.PeriodHours) * time.Hour
 }
 
 type EmailConfig struct {
@@ -54,12 +677,30 @@ func LoadConfig(path string) (*Config, error) {
 	v.SetDefault("app.port", 8080)
 	v.SetDefault("app.admin_token", "")
 	v.SetDefault("app.allowed_origins", []string{"http://localhost:8080", "http://127.0.0.1:8080"})
+	v.SetDefault("app.service_down_log", "logs/service_down.log")
 	v.SetDefault("monitor.c2c_interval_minutes", 3)
 	v.SetDefault("monitor.forex_interval_hours", 1)
 	v.SetDefault("monitor.forex_max_age_hours", 6)
+	v.SetDefault("monitor.round_trip_max_price_age_minutes", 10)
 	v.SetDefault("monitor.target_amounts", []float64{0, 30, 50, 200, 500, 1000})
-	v.SetDefault("monitor.exchanges", []string{"Binance", "Gate", "OKX"})
+	v.SetDefault("monitor.exchanges", []string{"Binance", "Gate", "OKX", "Bybit"})
+	v.SetDefault("monitor.depth", 1)
+	v.SetDefault("monitor.sides", []string{"BUY"})
 	v.SetDefault("notification.email.enabled", true)
+	v.SetDefault("notification.webhook.signature_header", "X-C2C-Signature")
+	v.SetDefault("notification.webhook.timeout_seconds", 10)
+	v.SetDefault("notification.webhook.max_retries", 2)
+	v.SetDefault("notification.telegram.api_base_url", "https://api.telegram.org")
+	v.SetDefault("notification.telegram.timeout_seconds", 10)
+	v.SetDefault("notification.dingtalk.timeout_seconds", 10)
+	v.SetDefault("notification.wecom.timeout_seconds", 10)
+	v.SetDefault("notification.feishu.timeout_seconds", 10)
+	v.SetDefault("notification.digest.cron", "0 9 * * *")
+	v.SetDefault("notification.digest.period_hours", 24)
+	v.SetDefault("notification.digest.top_merchants", 3)
+	v.SetDefault("leader_election.lease_name", "c2c-monitor")
+	v.SetDefault("leader_election.lease_seconds", 30)
+	v.SetDefault("leader_election.renew_seconds", 10)
 
 	// Environment variable support
 	v.SetEnvPrefix("C2C")
@@ -74,6 +715,22 @@ func LoadConfig(path string) (*Config, error) {
 		"notification.email.username",
 		"notification.email.password",
 		"notification.email.from",
+		"notification.webhook.enabled",
+		"notification.webhook.url",
+		"notification.webhook.secret",
+		"notification.telegram.enabled",
+		"notification.telegram.bot_token",
+		"notification.dingtalk.enabled",
+		"notification.dingtalk.webhook_url",
+		"notification.dingtalk.secret",
+		"notification.wecom.enabled",
+		"notification.wecom.webhook_url",
+		"notification.feishu.enabled",
+		"notification.feishu.webhook_url",
+		"notification.feishu.secret",
+		"notification.digest.enabled",
+		"leader_election.enabled",
+		"leader_election.identity",
 	} {
 		if err := v.BindEnv(key); err != nil {
 			return nil, fmt.Errorf("bind environment variable for %s: %w", key, err)
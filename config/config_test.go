@@ -4,7 +4,9 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"c2c_monitor/internal/domain"
 )
@@ -32,6 +34,413 @@ func TestNormalizeMonitorConfig(t *testing.T) {
 	}
 }
 
+func TestNormalizeMonitorConfigDepth(t *testing.T) {
+	cfg := MonitorConfig{
+		C2CIntervalMinutes: 3,
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0},
+		Exchanges:          []string{"binance", "okx"},
+		ExchangeDepths:     map[string]int{"okx": 20},
+	}
+
+	got, err := NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Depth != 1 {
+		t.Fatalf("expected default depth 1, got %d", got.Depth)
+	}
+	if got.DepthFor(domain.ExchangeOKX) != 20 || got.DepthFor(domain.ExchangeBinance) != 1 {
+		t.Fatalf("unexpected per-exchange depths: %#v", got.ExchangeDepths)
+	}
+
+	cfg.Depth = domain.MaxDepth + 1
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected depth above the adapter page size to be rejected")
+	}
+
+	cfg.Depth = 5
+	cfg.ExchangeDepths = map[string]int{"kraken": 5}
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected unsupported exchange depth to be rejected")
+	}
+}
+
+func TestNormalizeMonitorConfigRateLimits(t *testing.T) {
+	cfg := MonitorConfig{
+		C2CIntervalMinutes: 3,
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0},
+		Exchanges:          []string{"binance", "okx"},
+		RateLimit:          RateLimitConfig{RequestsPerSecond: 5},
+		ExchangeRateLimits: map[string]RateLimitConfig{"Binance": {RequestsPerSecond: 0.5, Burst: 2}},
+	}
+
+	got, err := NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if limit := got.RateLimitFor(domain.ExchangeBinance); limit.RequestsPerSecond != 0.5 || limit.BurstSize() != 2 {
+		t.Fatalf("expected binance override, got %#v", limit)
+	}
+	if limit := got.RateLimitFor(domain.ExchangeOKX); limit.RequestsPerSecond != 5 || limit.BurstSize() != 1 {
+		t.Fatalf("expected global limit with a burst of one, got %#v", limit)
+	}
+
+	cfg.ExchangeRateLimits = map[string]RateLimitConfig{"okx": {RequestsPerSecond: -1}}
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected negative rate to be rejected")
+	}
+}
+
+func TestNormalizeMonitorConfigPollSchedules(t *testing.T) {
+	cfg := MonitorConfig{
+		C2CIntervalMinutes: 3,
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0, 1000},
+		Exchanges:          []string{"binance", "okx"},
+		PollSchedules: []PollScheduleConfig{
+			{Exchange: "Binance", Amounts: []float64{1000, 0}, IntervalMinutes: 1},
+			{Exchange: "okx", Market: "usdt/cny", IntervalMinutes: 15},
+		},
+	}
+
+	got, err := NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.PollSchedules[0].Exchange != domain.ExchangeBinance || got.PollSchedules[1].Market != "USDT/CNY" {
+		t.Fatalf("expected normalized schedules, got %#v", got.PollSchedules)
+	}
+	if interval := got.C2CInterval(domain.ExchangeBinance, "USDT/CNY", 1000); interval != time.Minute {
+		t.Fatalf("expected binance tier every minute, got %s", interval)
+	}
+	if interval := got.C2CInterval(domain.ExchangeOKX, "USDT/CNY", 0); interval != 15*time.Minute {
+		t.Fatalf("expected okx every 15 minutes, got %s", interval)
+	}
+	if interval := got.C2CInterval(domain.ExchangeOKX, "BTC/CNY", 0); interval != 3*time.Minute {
+		t.Fatalf("expected unmatched tier to use the global interval, got %s", interval)
+	}
+
+	cfg.PollSchedules = []PollScheduleConfig{{Exchange: "okx"}}
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected schedule without interval to be rejected")
+	}
+	cfg.PollSchedules = []PollScheduleConfig{{Market: "USDTCNY", IntervalMinutes: 1}}
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected malformed market to be rejected")
+	}
+}
+
+func TestNormalizeMonitorConfigCronAndBlackouts(t *testing.T) {
+	cfg := MonitorConfig{
+		C2CCron:            " */2  9-23 * * * ",
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0},
+		Exchanges:          []string{"binance", "okx"},
+		ScheduleTimezone:   "UTC",
+		CollectionBlackouts: []BlackoutWindowConfig{
+			{Exchange: "OKX", Start: "08:00", End: "08:30", Weekdays: []string{" Wed "}},
+		},
+		PollSchedules: []PollScheduleConfig{{Exchange: "binance", Cron: "* * * * *"}},
+	}
+
+	got, err := NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected cron to replace the interval, got %v", err)
+	}
+	if got.C2CCron != "*/2 9-23 * * *" || got.CollectionBlackouts[0].Exchange != domain.ExchangeOKX || got.CollectionBlackouts[0].Weekdays[0] != "wed" {
+		t.Fatalf("expected normalized schedule settings, got %#v", got)
+	}
+	if expr := got.C2CCronFor(domain.ExchangeBinance, "USDT/CNY", 0); expr != "* * * * *" {
+		t.Fatalf("expected binance poll schedule cron, got %q", expr)
+	}
+	// 2026-10-14 is a Wednesday.
+	if until, ok := got.BlackoutUntil(domain.ExchangeOKX, time.Date(2026, 10, 14, 8, 10, 0, 0, time.UTC)); !ok || !until.Equal(time.Date(2026, 10, 14, 8, 30, 0, 0, time.UTC)) {
+		t.Fatalf("expected okx maintenance window, got %s %v", until, ok)
+	}
+	if _, ok := got.BlackoutUntil(domain.ExchangeBinance, time.Date(2026, 10, 14, 8, 10, 0, 0, time.UTC)); ok {
+		t.Fatal("expected the okx window not to apply to binance")
+	}
+
+	invalid := []func(*MonitorConfig){
+		func(c *MonitorConfig) { c.C2CCron = "every minute" },
+		func(c *MonitorConfig) { c.ScheduleTimezone = "Mars/Olympus" },
+		func(c *MonitorConfig) { c.AdaptivePolling.ProximityPercent = -1 },
+		func(c *MonitorConfig) {
+			c.CollectionBlackouts = []BlackoutWindowConfig{{Start: "8:00pm", End: "09:00"}}
+		},
+		func(c *MonitorConfig) {
+			c.PollSchedules = []PollScheduleConfig{{Cron: "* * * * *", IntervalMinutes: 1}}
+		},
+	}
+	for index, mutate := range invalid {
+		candidate := cfg
+		mutate(&candidate)
+		if _, err := NormalizeMonitorConfig(candidate); err == nil {
+			t.Fatalf("case %d: expected invalid schedule settings to be rejected", index)
+		}
+	}
+}
+
+func TestBlackoutsCoveringTheWholeWeek(t *testing.T) {
+	fullDay := []BlackoutWindowConfig{{Start: "00:00", End: "12:00"}, {Start: "12:00", End: "00:00"}}
+
+	// Loaded without validation, the chained windows stop a week later.
+	cfg := MonitorConfig{ScheduleTimezone: "UTC", CollectionBlackouts: fullDay}
+	now := time.Date(2026, 10, 14, 8, 10, 0, 0, time.UTC)
+	if until, ok := cfg.BlackoutUntil(domain.ExchangeGate, now); !ok || !until.Equal(now.Add(7*24*time.Hour)) {
+		t.Fatalf("expected the blackout to be capped at a week, got %s %v", until, ok)
+	}
+
+	base := MonitorConfig{C2CIntervalMinutes: 3, ForexIntervalHours: 1, ForexMaxAgeHours: 6, TargetAmounts: []float64{0}, Exchanges: []string{"Gate", "OKX"}}
+	for _, tc := range []struct {
+		blackouts []BlackoutWindowConfig
+		wantErr   string
+	}{
+		{blackouts: fullDay, wantErr: "cover the whole week"},
+		{blackouts: []BlackoutWindowConfig{{Start: "00:00", End: "23:00"}, {Exchange: "okx", Start: "22:00", End: "01:00"}}, wantErr: "whole week for OKX"},
+		{blackouts: []BlackoutWindowConfig{{Start: "00:00", End: "23:59"}, {Start: "12:00", End: "00:00", Weekdays: []string{"mon"}}}},
+	} {
+		candidate := base
+		candidate.CollectionBlackouts = tc.blackouts
+		_, err := NormalizeMonitorConfig(candidate)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Fatalf("%#v: expected error containing %q, got %v", tc.blackouts, tc.wantErr, err)
+		}
+	}
+}
+
+func TestNormalizeMonitorConfigSides(t *testing.T) {
+	cfg := MonitorConfig{
+		C2CIntervalMinutes: 3,
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0},
+		Exchanges:          []string{"binance"},
+	}
+
+	got, err := NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got.Sides, []string{domain.SideBuy}) {
+		t.Fatalf("expected BUY by default, got %v", got.Sides)
+	}
+
+	cfg.Sides = []string{" sell", "BUY", "Sell"}
+	got, err = NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got.Sides, []string{domain.SideSell, domain.SideBuy}) {
+		t.Fatalf("expected normalized unique sides, got %v", got.Sides)
+	}
+
+	cfg.Sides = []string{"HOLD"}
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected unsupported side to be rejected")
+	}
+
+	cfg.Sides = nil
+	cfg.RoundTripAlertSpread = -0.1
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected negative round-trip spread threshold to be rejected")
+	}
+
+	cfg.RoundTripAlertSpread = 0
+	cfg.RoundTripMaxPriceAgeMinutes = -1
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected negative round-trip price age to be rejected")
+	}
+}
+
+func TestNormalizeMonitorConfigPayMethodFilters(t *testing.T) {
+	cfg := MonitorConfig{
+		C2CIntervalMinutes: 3,
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0},
+		Exchanges:          []string{"binance"},
+		PayMethodFilters:   []string{" bank ", "银行卡", "Alipay", ""},
+	}
+
+	got, err := NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got.PayMethodFilters, []string{"银行卡", "支付宝"}) {
+		t.Fatalf("expected canonical unique filters, got %v", got.PayMethodFilters)
+	}
+	if !reflect.DeepEqual(got.CollectedPayMethods(), []string{"", "银行卡", "支付宝"}) {
+		t.Fatalf("expected unfiltered tier first, got %v", got.CollectedPayMethods())
+	}
+
+	cfg.PayMethodFilters = []string{"PayPal"}
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected unknown pay method filter to be rejected")
+	}
+}
+
+func TestNormalizeMonitorConfigMerchantFilter(t *testing.T) {
+	cfg := MonitorConfig{
+		C2CIntervalMinutes: 3,
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0},
+		Exchanges:          []string{"binance"},
+		MerchantFilter:     MerchantFilterConfig{MinCompletionRate: 95, MinMonthOrders: 100},
+	}
+	if _, err := NormalizeMonitorConfig(cfg); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	cfg.MerchantFilter.MinCompletionRate = 950
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected completion rate above 100 to be rejected")
+	}
+	cfg.MerchantFilter = MerchantFilterConfig{MinMonthOrders: -1}
+	if _, err := NormalizeMonitorConfig(cfg); err == nil {
+		t.Fatal("expected negative order threshold to be rejected")
+	}
+}
+
+func TestMerchantFilterAllows(t *testing.T) {
+	rate := func(value float64) *float64 { return &value }
+	orders := func(value int) *int { return &value }
+	filter := MerchantFilterConfig{MinCompletionRate: 95, MinMonthOrders: 100, RequireVerifiedMerchant: true}
+
+	unverified := false
+	cases := []struct {
+		name       string
+		reputation domain.MerchantReputation
+		want       bool
+	}{
+		{"established", domain.MerchantReputation{CompletionRate: rate(98.5), MonthOrders: orders(812)}, true},
+		{"low completion", domain.MerchantReputation{CompletionRate: rate(91.4), MonthOrders: orders(812)}, false},
+		{"new merchant", domain.MerchantReputation{CompletionRate: rate(100), MonthOrders: orders(3)}, false},
+		{"no badge", domain.MerchantReputation{VerifiedMerchant: &unverified}, false},
+		{"unreported", domain.MerchantReputation{}, true},
+	}
+	for _, tc := range cases {
+		if got := filter.Allows(tc.reputation); got != tc.want {
+			t.Fatalf("%s: expected Allows=%v, got %v", tc.name, tc.want, got)
+		}
+	}
+	if !(MerchantFilterConfig{}).Allows(domain.MerchantReputation{CompletionRate: rate(10)}) {
+		t.Fatal("expected zero thresholds to allow every ad")
+	}
+}
+
+func TestNormalizeMonitorConfigMarkets(t *testing.T) {
+	cfg := MonitorConfig{
+		C2CIntervalMinutes: 3,
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0, 30},
+		Exchanges:          []string{"binance"},
+	}
+
+	got, err := NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got.Markets, []MarketConfig{DefaultMarket()}) {
+		t.Fatalf("expected USDT/CNY by default, got %#v", got.Markets)
+	}
+
+	cfg.Markets = []MarketConfig{
+		{Symbol: "usdt", Fiat: "cny"},
+		{Symbol: "USDT", Fiat: "HKD", TargetAmounts: []float64{1000, 100, 1000}},
+		{Symbol: "BTC", Fiat: "CNY"},
+	}
+	got, err = NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if got.Markets[0].Key() != "USDT/CNY" || got.Markets[0].ForexPair() != "USDCNY" {
+		t.Fatalf("expected stablecoin market to default to USD Forex, got %#v", got.Markets[0])
+	}
+	if !reflect.DeepEqual(got.AmountsFor(got.Markets[0]), []float64{0, 30}) ||
+		!reflect.DeepEqual(got.AmountsFor(got.Markets[1]), []float64{100, 1000}) {
+		t.Fatalf("unexpected market amount tiers: %#v", got.Markets)
+	}
+	if got.Markets[2].ForexPair() != "" {
+		t.Fatalf("expected BTC/CNY to have no Forex reference, got %q", got.Markets[2].ForexPair())
+	}
+	if !reflect.DeepEqual(got.ForexPairs(), []string{"USDCNY", "USDHKD"}) {
+		t.Fatalf("unexpected Forex pairs: %v", got.ForexPairs())
+	}
+
+	for _, markets := range [][]MarketConfig{
+		{{Symbol: "USDT", Fiat: "CNY"}, {Symbol: "usdt", Fiat: "CNY"}},
+		{{Symbol: "USDT", Fiat: "RMB1"}},
+		{{Symbol: "USDT/", Fiat: "CNY"}},
+		{{Symbol: "USDT", Fiat: "CNY", ForexBase: "CNY"}},
+	} {
+		cfg.Markets = markets
+		if _, err := NormalizeMonitorConfig(cfg); err == nil {
+			t.Fatalf("expected markets %#v to be rejected", markets)
+		}
+	}
+}
+
+func TestNormalizeMonitorConfigCustomExchanges(t *testing.T) {
+	custom := CustomExchangeConfig{
+		Name:     " DeskOTC ",
+		Aliases:  []string{"Desk"},
+		Endpoint: "https://otc.example.com/ads?coin={symbol}&fiat={fiat}",
+		Sides:    map[string]string{"buy": "sell"},
+		Fields:   CustomExchangeFields{Price: "price", MinAmount: "min", MaxAmount: "max"},
+	}
+	cfg := MonitorConfig{
+		C2CIntervalMinutes: 3,
+		ForexIntervalHours: 1,
+		ForexMaxAgeHours:   6,
+		TargetAmounts:      []float64{0},
+		Exchanges:          []string{"binance", "desk"},
+		ExchangeDepths:     map[string]int{"deskotc": 5},
+		CustomExchanges:    []CustomExchangeConfig{custom},
+	}
+
+	got, err := NormalizeMonitorConfig(cfg)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !reflect.DeepEqual(got.Exchanges, []string{"Binance", "DeskOTC"}) {
+		t.Fatalf("expected custom exchange to resolve by alias, got %v", got.Exchanges)
+	}
+	if got.ExchangeDepths["DeskOTC"] != 5 {
+		t.Fatalf("expected custom exchange depth override, got %v", got.ExchangeDepths)
+	}
+	normalized := got.CustomExchanges[0]
+	if normalized.Method != "GET" || normalized.Sides["BUY"] != "sell" || normalized.Sides["SELL"] != "SELL" {
+		t.Fatalf("unexpected normalized custom exchange: %#v", normalized)
+	}
+
+	for _, broken := range []func(*CustomExchangeConfig){
+		func(c *CustomExchangeConfig) { c.Name = "" },
+		func(c *CustomExchangeConfig) { c.Endpoint = "ftp://otc.example.com" },
+		func(c *CustomExchangeConfig) { c.Method = "PUT" },
+		func(c *CustomExchangeConfig) { c.Body = "{}"; c.Method = "GET" },
+		func(c *CustomExchangeConfig) { c.Sides = map[string]string{"hold": "x"} },
+		func(c *CustomExchangeConfig) { c.Fields.MaxAmount = "" },
+	} {
+		invalid := custom
+		broken(&invalid)
+		cfg.CustomExchanges = []CustomExchangeConfig{invalid}
+		cfg.Exchanges = []string{"binance"}
+		cfg.ExchangeDepths = nil
+		if _, err := NormalizeMonitorConfig(cfg); err == nil {
+			t.Fatalf("expected custom exchange %#v to be rejected", invalid)
+		}
+	}
+}
+
 func TestNormalizeMonitorConfigRejectsUnsupportedExchange(t *testing.T) {
 	cfg := MonitorConfig{
 		C2CIntervalMinutes: 3,
@@ -166,6 +575,8 @@ notification:
 	t.Setenv("C2C_NOTIFICATION_EMAIL_USERNAME", "sender@example.com")
 	t.Setenv("C2C_NOTIFICATION_EMAIL_PASSWORD", "smtp-password")
 	t.Setenv("C2C_NOTIFICATION_EMAIL_FROM", "sender@example.com")
+	t.Setenv("C2C_LEADER_ELECTION_ENABLED", "true")
+	t.Setenv("C2C_LEADER_ELECTION_IDENTITY", "c2c-monitor-0")
 
 	cfg, err := LoadConfig(configPath)
 	if err != nil {
@@ -185,4 +596,206 @@ notification:
 		cfg.Notification.Email.From != "sender@example.com" {
 		t.Fatalf("SMTP secret overrides were not applied: %#v", cfg.Notification.Email)
 	}
+	if want := (LeaderElectionConfig{Enabled: true, LeaseName: "c2c-monitor", Identity: "c2c-monitor-0", LeaseSeconds: 30, RenewSeconds: 10}); cfg.LeaderElection != want {
+		t.Fatalf("expected leader election from the environment and defaults, got %#v", cfg.LeaderElection)
+	}
+}
+
+func TestNormalizeAndValidateLeaderElection(t *testing.T) {
+	for _, tc := range []struct {
+		election LeaderElectionConfig
+		wantErr  string
+	}{
+		{election: LeaderElectionConfig{LeaseName: " c2c-monitor ", LeaseSeconds: 30, RenewSeconds: 10}},
+		{election: LeaderElectionConfig{Enabled: true, LeaseName: " ", LeaseSeconds: 30, RenewSeconds: 10}, wantErr: "lease_name"},
+		{election: LeaderElectionConfig{Enabled: true, LeaseName: "c2c-monitor", LeaseSeconds: 15, RenewSeconds: 10}, wantErr: "twice renew_seconds"},
+		{election: LeaderElectionConfig{Enabled: true, LeaseName: "c2c-monitor"}, wantErr: "renew_seconds"},
+	} {
+		cfg := &Config{
+			App:            AppConfig{Port: 8001, AdminToken: "0123456789abcdef"},
+			Monitor:        MonitorConfig{C2CIntervalMinutes: 3, ForexIntervalHours: 1, ForexMaxAgeHours: 6, TargetAmounts: []float64{0}, Exchanges: []string{"Gate"}},
+			Database:       DatabaseConfig{DSN: "test"},
+			LeaderElection: tc.election,
+		}
+		err := NormalizeAndValidate(cfg)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Fatalf("%#v: expected error containing %q, got %v", tc.election, tc.wantErr, err)
+		}
+	}
+}
+
+func TestNormalizeAndValidateDigest(t *testing.T) {
+	for _, tc := range []struct {
+		digest  DigestConfig
+		wantErr string
+	}{
+		{digest: DigestConfig{Recipients: []string{" desk@example.com ", ""}}},
+		{digest: DigestConfig{Enabled: true, Cron: "0  9 * * *", PeriodHours: 24, TopMerchants: 3}},
+		{digest: DigestConfig{Enabled: true, Cron: "0 25 * * *", PeriodHours: 24}, wantErr: "notification.digest.cron"},
+		{digest: DigestConfig{Enabled: true, PeriodHours: 24}, wantErr: "cron must not be empty"},
+		{digest: DigestConfig{Enabled: true, Cron: "0 9 * * *"}, wantErr: "period_hours"},
+		{digest: DigestConfig{Enabled: true, Cron: "0 9 * * *", PeriodHours: 24, TopMerchants: 50}, wantErr: "top_merchants"},
+	} {
+		cfg := &Config{
+			App:          AppConfig{Port: 8001, AdminToken: "0123456789abcdef"},
+			Monitor:      MonitorConfig{C2CIntervalMinutes: 3, ForexIntervalHours: 1, ForexMaxAgeHours: 6, TargetAmounts: []float64{0}, Exchanges: []string{"Gate"}},
+			Database:     DatabaseConfig{DSN: "test"},
+			Notification: NotificationConfig{Digest: tc.digest},
+		}
+		err := NormalizeAndValidate(cfg)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Fatalf("%#v: expected error containing %q, got %v", tc.digest, tc.wantErr, err)
+		}
+		if tc.wantErr == "" && (len(cfg.Notification.Digest.Recipients) > 1 || tc.digest.Enabled && cfg.Notification.Digest.Cron != "0 9 * * *") {
+			t.Fatalf("expected normalized digest config, got %#v", cfg.Notification.Digest)
+		}
+	}
+}
+
+func TestNormalizeAndValidateWebhook(t *testing.T) {
+	for _, tc := range []struct {
+		webhook WebhookConfig
+		wantErr string
+	}{
+		{webhook: WebhookConfig{URL: "not a url"}},
+		{webhook: WebhookConfig{Enabled: true, URL: " https://hooks.example.com/c2c ", Secret: "secret", SignatureHeader: "X-C2C-Signature", MaxRetries: 2}},
+		{webhook: WebhookConfig{Enabled: true, URL: "hooks.example.com"}, wantErr: "notification.webhook.url"},
+		{webhook: WebhookConfig{Enabled: true, URL: "https://hooks.example.com", Headers: map[string]string{"X-Token": "a\r\nX-Evil: b"}}, wantErr: "headers"},
+		{webhook: WebhookConfig{Enabled: true, URL: "https://hooks.example.com", Secret: "secret"}, wantErr: "signature_header"},
+		{webhook: WebhookConfig{Enabled: true, URL: "https://hooks.example.com", MaxRetries: 11}, wantErr: "max_retries"},
+	} {
+		cfg := &Config{
+			App:          AppConfig{Port: 8001, AdminToken: "0123456789abcdef"},
+			Monitor:      MonitorConfig{C2CIntervalMinutes: 3, ForexIntervalHours: 1, ForexMaxAgeHours: 6, TargetAmounts: []float64{0}, Exchanges: []string{"Gate"}},
+			Database:     DatabaseConfig{DSN: "test"},
+			Notification: NotificationConfig{Webhook: tc.webhook},
+		}
+		err := NormalizeAndValidate(cfg)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Fatalf("%#v: expected error containing %q, got %v", tc.webhook, tc.wantErr, err)
+		}
+		if tc.wantErr == "" && tc.webhook.Enabled && cfg.Notification.Webhook.URL != "https://hooks.example.com/c2c" {
+			t.Fatalf("expected normalized webhook config, got %#v", cfg.Notification.Webhook)
+		}
+	}
+}
+
+func TestNormalizeAndValidateTelegram(t *testing.T) {
+	for _, tc := range []struct {
+		telegram TelegramConfig
+		wantErr  string
+	}{
+		{telegram: TelegramConfig{ChatIDs: []string{""}}},
+		{telegram: TelegramConfig{Enabled: true, BotToken: " 123:token ", ChatIDs: []string{" -100200 ", "", "@alerts"}, APIBaseURL: "https://api.telegram.org/", Commands: true, AdminChatIDs: []string{"42"}}},
+		{telegram: TelegramConfig{Enabled: true, ChatIDs: []string{"42"}}, wantErr: "bot_token"},
+		{telegram: TelegramConfig{Enabled: true, BotToken: "123:token", ChatIDs: []string{" "}}, wantErr: "chat_ids"},
+		{telegram: TelegramConfig{Enabled: true, BotToken: "123:token", ChatIDs: []string{"42"}, APIBaseURL: "api.telegram.org"}, wantErr: "api_base_url"},
+		{telegram: TelegramConfig{Enabled: true, BotToken: "123:token", ChatIDs: []string{"42"}, AdminChatIDs: []string{"@alerts"}}, wantErr: "admin_chat_ids"},
+	} {
+		cfg := &Config{
+			App:          AppConfig{Port: 8001, AdminToken: "0123456789abcdef"},
+			Monitor:      MonitorConfig{C2CIntervalMinutes: 3, ForexIntervalHours: 1, ForexMaxAgeHours: 6, TargetAmounts: []float64{0}, Exchanges: []string{"Gate"}},
+			Database:     DatabaseConfig{DSN: "test"},
+			Notification: NotificationConfig{Telegram: tc.telegram},
+		}
+		err := NormalizeAndValidate(cfg)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Fatalf("%#v: expected error containing %q, got %v", tc.telegram, tc.wantErr, err)
+		}
+		telegram := cfg.Notification.Telegram
+		if tc.wantErr == "" && tc.telegram.Enabled && (telegram.BotToken != "123:token" || len(telegram.ChatIDs) != 2 || telegram.ChatIDs[0] != "-100200" || telegram.APIBaseURL != "https://api.telegram.org") {
+			t.Fatalf("expected normalized telegram config, got %#v", telegram)
+		}
+	}
+}
+
+func TestNormalizeAndValidateRobots(t *testing.T) {
+	for _, tc := range []struct {
+		notification NotificationConfig
+		wantErr      string
+	}{
+		{notification: NotificationConfig{DingTalk: RobotConfig{WebhookURL: "not a url"}}},
+		{notification: NotificationConfig{
+			DingTalk: RobotConfig{Enabled: true, WebhookURL: " https://oapi.dingtalk.com/robot/send?access_token=abc ", Secret: " SECsecret "},
+			WeCom:    RobotConfig{Enabled: true, WebhookURL: "https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=abc"},
+			Feishu:   RobotConfig{Enabled: true, WebhookURL: "https://open.feishu.cn/open-apis/bot/v2/hook/abc"},
+		}},
+		{notification: NotificationConfig{DingTalk: RobotConfig{Enabled: true, WebhookURL: "oapi.dingtalk.com/robot/send?access_token=abc"}}, wantErr: "notification.dingtalk.webhook_url"},
+		{notification: NotificationConfig{WeCom: RobotConfig{Enabled: true, WebhookURL: "https://qyapi.weixin.qq.com/cgi-bin/webhook/send?key=abc", Secret: "secret"}}, wantErr: "notification.wecom.secret"},
+		{notification: NotificationConfig{Feishu: RobotConfig{Enabled: true, WebhookURL: "https://open.feishu.cn/open-apis/bot/v2/hook/abc", TimeoutSeconds: -1}}, wantErr: "notification.feishu.timeout_seconds"},
+	} {
+		cfg := &Config{
+			App:          AppConfig{Port: 8001, AdminToken: "0123456789abcdef"},
+			Monitor:      MonitorConfig{C2CIntervalMinutes: 3, ForexIntervalHours: 1, ForexMaxAgeHours: 6, TargetAmounts: []float64{0}, Exchanges: []string{"Gate"}},
+			Database:     DatabaseConfig{DSN: "test"},
+			Notification: tc.notification,
+		}
+		err := NormalizeAndValidate(cfg)
+		if tc.wantErr == "" && err != nil || tc.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tc.wantErr)) {
+			t.Fatalf("%#v: expected error containing %q, got %v", tc.notification, tc.wantErr, err)
+		}
+		if dingTalk := cfg.Notification.DingTalk; tc.wantErr == "" && dingTalk.Enabled && (dingTalk.WebhookURL != "https://oapi.dingtalk.com/robot/send?access_token=abc" || dingTalk.Secret != "SECsecret") {
+			t.Fatalf("expected normalized dingtalk config, got %#v", dingTalk)
+		}
+	}
+}
+
+func TestLoadConfigExchangeHTTP(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	body := []byte(`
+app:
+  port: 8001
+  admin_token: "0123456789abcdef"
+monitor:
+  exchanges: ["Gate"]
+database:
+  dsn: "test"
+notification:
+  email:
+    enabled: false
+exchange_http:
+  gate:
+    proxy: "socks5://127.0.0.1:1080"
+    headers:
+      User-Agent: "Mozilla/5.0 (X11; Linux x86_64)"
+      Cookie: ""
+    timeout_seconds: 5
+    tls:
+      min_version: "1.3"
+`)
+	if err := os.WriteFile(configPath, body, 0o600); err != nil {
+		t.Fatalf("write test config: %v", err)
+	}
+
+	cfg, err := LoadConfig(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfig returned error: %v", err)
+	}
+	gate, ok := cfg.ExchangeHTTP["Gate"]
+	if !ok {
+		t.Fatalf("expected settings keyed by canonical exchange name, got %#v", cfg.ExchangeHTTP)
+	}
+	if gate.Proxy != "socks5://127.0.0.1:1080" || gate.TimeoutSeconds != 5 || gate.TLS.MinVersion != "1.3" {
+		t.Fatalf("unexpected gate transport settings: %#v", gate)
+	}
+	if gate.Headers["user-agent"] != "Mozilla/5.0 (X11; Linux x86_64)" {
+		t.Fatalf("expected header override, got %#v", gate.Headers)
+	}
+	if value, ok := gate.Headers["cookie"]; !ok || value != "" {
+		t.Fatalf("expected empty cookie override to be kept, got %#v", gate.Headers)
+	}
+
+	for _, invalid := range []ExchangeHTTPConfig{
+		{Proxy: "ftp://proxy.local:21"},
+		{Proxy: "127.0.0.1:1080"},
+		{TimeoutSeconds: -1},
+		{TLS: ExchangeTLSConfig{MinVersion: "1.0"}},
+	} {
+		if _, err := normalizeExchangeHTTP(map[string]ExchangeHTTPConfig{"gate": invalid}, nil); err == nil {
+			t.Fatalf("expected exchange_http settings %#v to be rejected", invalid)
+		}
+	}
+	if _, err := normalizeExchangeHTTP(map[string]ExchangeHTTPConfig{"kraken": {}}, nil); err == nil {
+		t.Fatal("expected unknown exchange to be rejected")
+	}
 }
@@ -1,14 +1,18 @@
 package config
 
 import (
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"math"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"c2c_monitor/internal/domain"
+	"c2c_monitor/internal/schedule"
 )
 
 func NormalizeAndValidate(cfg *Config) error {
@@ -30,6 +34,7 @@ func NormalizeAndValidate(cfg *Config) error {
 		return err
 	}
 	cfg.App.AllowedOrigins = allowedOrigins
+	cfg.App.ServiceDownLog = strings.TrimSpace(cfg.App.ServiceDownLog)
 
 	monitorCfg, err := NormalizeMonitorConfig(cfg.Monitor)
 	if err != nil {
@@ -37,10 +42,47 @@ func NormalizeAndValidate(cfg *Config) error {
 	}
 	cfg.Monitor = monitorCfg
 
+	exchangeHTTP, err := normalizeExchangeHTTP(cfg.ExchangeHTTP, cfg.Monitor.CustomExchanges)
+	if err != nil {
+		return err
+	}
+	cfg.ExchangeHTTP = exchangeHTTP
+
 	if strings.TrimSpace(cfg.Database.DSN) == "" {
 		return fmt.Errorf("database.dsn must not be empty")
 	}
 
+	if err := normalizeLeaderElection(&cfg.LeaderElection); err != nil {
+		return err
+	}
+
+	cfg.Notification.TemplatesDir = strings.TrimSpace(cfg.Notification.TemplatesDir)
+	if err := normalizeWebhook(&cfg.Notification.Webhook); err != nil {
+		return err
+	}
+
+	if err := normalizeTelegram(&cfg.Notification.Telegram); err != nil {
+		return err
+	}
+
+	for _, robot := range []struct {
+		name    string
+		config  *RobotConfig
+		signing bool
+	}{
+		{"dingtalk", &cfg.Notification.DingTalk, true},
+		{"wecom", &cfg.Notification.WeCom, false},
+		{"feishu", &cfg.Notification.Feishu, true},
+	} {
+		if err := normalizeRobot(robot.name, robot.config, robot.signing); err != nil {
+			return err
+		}
+	}
+
+	if err := normalizeDigest(&cfg.Notification.Digest); err != nil {
+		return err
+	}
+
 	email := &cfg.Notification.Email
 	email.SMTPHost = strings.TrimSpace(email.SMTPHost)
 	email.Username = strings.TrimSpace(email.Username)
@@ -69,12 +111,24 @@ func NormalizeAndValidate(cfg *Config) error {
 }
 
 func NormalizeMonitorConfig(cfg MonitorConfig) (MonitorConfig, error) {
-	if cfg.C2CIntervalMinutes <= 0 {
+	var err error
+	if cfg.C2CCron, err = normalizeCron("monitor.c2c_cron", cfg.C2CCron); err != nil {
+		return cfg, err
+	}
+	if cfg.ForexCron, err = normalizeCron("monitor.forex_cron", cfg.ForexCron); err != nil {
+		return cfg, err
+	}
+	if cfg.C2CIntervalMinutes <= 0 && cfg.C2CCron == "" {
 		return cfg, fmt.Errorf("monitor.c2c_interval_minutes must be > 0")
 	}
-	if cfg.ForexIntervalHours <= 0 {
+	if cfg.ForexIntervalHours <= 0 && cfg.ForexCron == "" {
 		return cfg, fmt.Errorf("monitor.forex_interval_hours must be > 0")
 	}
+	if cfg.ScheduleTimezone = strings.TrimSpace(cfg.ScheduleTimezone); cfg.ScheduleTimezone != "" {
+		if _, err := time.LoadLocation(cfg.ScheduleTimezone); err != nil {
+			return cfg, fmt.Errorf("monitor.schedule_timezone: %w", err)
+		}
+	}
 	if cfg.ForexMaxAgeHours <= 0 {
 		return cfg, fmt.Errorf("monitor.forex_max_age_hours must be > 0")
 	}
@@ -85,30 +139,692 @@ func NormalizeMonitorConfig(cfg MonitorConfig) (MonitorConfig, error) {
 		return cfg, fmt.Errorf("monitor.exchanges must not be empty")
 	}
 
-	normalizedAmounts := make([]float64, 0, len(cfg.TargetAmounts))
-	seenAmounts := make(map[float64]struct{}, len(cfg.TargetAmounts))
-	for _, amount := range cfg.TargetAmounts {
-		if math.IsNaN(amount) || math.IsInf(amount, 0) || amount < 0 {
-			return cfg, fmt.Errorf("monitor.target_amounts must be >= 0, got %.4f", amount)
-		}
-		if _, exists := seenAmounts[amount]; exists {
-			continue
-		}
-		seenAmounts[amount] = struct{}{}
-		normalizedAmounts = append(normalizedAmounts, amount)
+	normalizedAmounts, err := normalizeTargetAmounts("monitor.target_amounts", cfg.TargetAmounts)
+	if err != nil {
+		return cfg, err
 	}
-	sort.Float64s(normalizedAmounts)
 	cfg.TargetAmounts = normalizedAmounts
 
-	normalizedExchanges, err := domain.NormalizeExchangeNames(cfg.Exchanges)
+	customExchanges, err := normalizeCustomExchanges(cfg.CustomExchanges)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.CustomExchanges = customExchanges
+
+	normalizedExchanges, err := normalizeExchangeNames(cfg.Exchanges, customExchanges)
 	if err != nil {
 		return cfg, err
 	}
 	cfg.Exchanges = normalizedExchanges
 
+	if cfg.Depth == 0 {
+		cfg.Depth = 1
+	}
+	if cfg.Depth < 0 || cfg.Depth > domain.MaxDepth {
+		return cfg, fmt.Errorf("monitor.depth must be between 1 and %d", domain.MaxDepth)
+	}
+	if len(cfg.ExchangeDepths) > 0 {
+		normalizedDepths := make(map[string]int, len(cfg.ExchangeDepths))
+		for name, depth := range cfg.ExchangeDepths {
+			normalizedName, err := normalizeExchangeName(name, customExchanges)
+			if err != nil {
+				return cfg, fmt.Errorf("monitor.exchange_depths: %w", err)
+			}
+			if depth < 1 || depth > domain.MaxDepth {
+				return cfg, fmt.Errorf("monitor.exchange_depths.%s must be between 1 and %d", normalizedName, domain.MaxDepth)
+			}
+			normalizedDepths[normalizedName] = depth
+		}
+		cfg.ExchangeDepths = normalizedDepths
+	} else {
+		cfg.ExchangeDepths = nil
+	}
+
+	if err := validateRateLimit("monitor.rate_limit", cfg.RateLimit); err != nil {
+		return cfg, err
+	}
+	if len(cfg.ExchangeRateLimits) > 0 {
+		normalizedLimits := make(map[string]RateLimitConfig, len(cfg.ExchangeRateLimits))
+		for name, limit := range cfg.ExchangeRateLimits {
+			normalizedName, err := normalizeExchangeName(name, customExchanges)
+			if err != nil {
+				return cfg, fmt.Errorf("monitor.exchange_rate_limits: %w", err)
+			}
+			if err := validateRateLimit("monitor.exchange_rate_limits."+normalizedName, limit); err != nil {
+				return cfg, err
+			}
+			normalizedLimits[normalizedName] = limit
+		}
+		cfg.ExchangeRateLimits = normalizedLimits
+	} else {
+		cfg.ExchangeRateLimits = nil
+	}
+
+	normalizedSides, err := normalizeSides(cfg.Sides)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Sides = normalizedSides
+
+	if math.IsNaN(cfg.RoundTripAlertSpread) || math.IsInf(cfg.RoundTripAlertSpread, 0) || cfg.RoundTripAlertSpread < 0 {
+		return cfg, fmt.Errorf("monitor.round_trip_alert_spread must be >= 0")
+	}
+	if cfg.RoundTripMaxPriceAgeMinutes < 0 {
+		return cfg, fmt.Errorf("monitor.round_trip_max_price_age_minutes must be >= 0")
+	}
+
+	normalizedMarkets, err := normalizeMarkets(cfg.Markets)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.Markets = normalizedMarkets
+
+	pollSchedules, err := normalizePollSchedules(cfg.PollSchedules, customExchanges)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.PollSchedules = pollSchedules
+
+	adaptive := cfg.AdaptivePolling
+	if math.IsNaN(adaptive.ProximityPercent) || adaptive.ProximityPercent < 0 || adaptive.ProximityPercent > 100 {
+		return cfg, fmt.Errorf("monitor.adaptive_polling.proximity_percent must be between 0 and 100")
+	}
+	if adaptive.IntervalSeconds < 0 {
+		return cfg, fmt.Errorf("monitor.adaptive_polling.interval_seconds must be >= 0")
+	}
+
+	blackouts, err := normalizeBlackouts(cfg.CollectionBlackouts, customExchanges)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.CollectionBlackouts = blackouts
+
+	payMethodFilters, err := normalizePayMethodFilters(cfg.PayMethodFilters)
+	if err != nil {
+		return cfg, err
+	}
+	cfg.PayMethodFilters = payMethodFilters
+
+	if err := validateMerchantFilter(cfg.MerchantFilter); err != nil {
+		return cfg, err
+	}
+
+	breaker := cfg.CircuitBreaker
+	if breaker.FailureThreshold < 0 || breaker.CooldownSeconds < 0 || breaker.MaxCooldownSeconds < 0 {
+		return cfg, fmt.Errorf("monitor.circuit_breaker values must be >= 0")
+	}
+	if breaker.MaxCooldown() < breaker.Cooldown() {
+		return cfg, fmt.Errorf("monitor.circuit_breaker.max_cooldown_seconds must be >= cooldown_seconds")
+	}
+
+	delivery := cfg.AlertDelivery
+	if delivery.MaxAttempts < 0 || delivery.RetrySeconds < 0 || delivery.MaxRetrySeconds < 0 {
+		return cfg, fmt.Errorf("monitor.alert_delivery values must be >= 0")
+	}
+	if delivery.MaxRetryDelay() < delivery.RetryDelay() {
+		return cfg, fmt.Errorf("monitor.alert_delivery.max_retry_seconds must be >= retry_seconds")
+	}
+
 	return cfg, nil
 }
 
+// normalizeExchangeName resolves registered venues first and then the
+// configured custom venues, which are only registered after config loading.
+func normalizeExchangeName(name string, custom []CustomExchangeConfig) (string, error) {
+	normalized, err := domain.NormalizeExchangeName(name)
+	if err == nil {
+		return normalized, nil
+	}
+
+	key := strings.ToLower(strings.TrimSpace(name))
+	for _, exchange := range custom {
+		if strings.ToLower(exchange.Name) == key {
+			return exchange.Name, nil
+		}
+		for _, alias := range exchange.Aliases {
+			if alias == key {
+				return exchange.Name, nil
+			}
+		}
+	}
+	return "", err
+}
+
+func normalizeExchangeNames(names []string, custom []CustomExchangeConfig) ([]string, error) {
+	result := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		normalized, err := normalizeExchangeName(name, custom)
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := seen[normalized]; exists {
+			continue
+		}
+		seen[normalized] = struct{}{}
+		result = append(result, normalized)
+	}
+	return result, nil
+}
+
+func normalizeCustomExchanges(exchanges []CustomExchangeConfig) ([]CustomExchangeConfig, error) {
+	if len(exchanges) == 0 {
+		return nil, nil
+	}
+
+	result := make([]CustomExchangeConfig, 0, len(exchanges))
+	seen := make(map[string]struct{}, len(exchanges))
+	for index, exchange := range exchanges {
+		field := fmt.Sprintf("monitor.custom_exchanges[%d]", index)
+		exchange.Name = strings.TrimSpace(exchange.Name)
+		if exchange.Name == "" {
+			return nil, fmt.Errorf("%s.name must not be empty", field)
+		}
+		aliases := make([]string, 0, len(exchange.Aliases))
+		for _, alias := range trimNonEmptyStrings(exchange.Aliases) {
+			aliases = append(aliases, strings.ToLower(alias))
+		}
+		exchange.Aliases = aliases
+		for _, key := range append([]string{strings.ToLower(exchange.Name)}, aliases...) {
+			if _, exists := seen[key]; exists {
+				return nil, fmt.Errorf("%s duplicates custom exchange name or alias %q", field, key)
+			}
+			seen[key] = struct{}{}
+		}
+
+		exchange.Endpoint = strings.TrimSpace(exchange.Endpoint)
+		sampleEndpoint := strings.NewReplacer("{symbol}", "USDT", "{fiat}", "CNY", "{side}", "BUY", "{amount}", "100", "{pay_method}", "").Replace(exchange.Endpoint)
+		parsed, err := url.Parse(sampleEndpoint)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("%s.endpoint must be an http(s) URL, got %q", field, exchange.Endpoint)
+		}
+
+		exchange.Method = strings.ToUpper(strings.TrimSpace(exchange.Method))
+		if exchange.Method == "" {
+			exchange.Method = "GET"
+			if strings.TrimSpace(exchange.Body) != "" {
+				exchange.Method = "POST"
+			}
+		}
+		if exchange.Method != "GET" && exchange.Method != "POST" {
+			return nil, fmt.Errorf("%s.method must be GET or POST, got %q", field, exchange.Method)
+		}
+		if exchange.Method == "GET" && strings.TrimSpace(exchange.Body) != "" {
+			return nil, fmt.Errorf("%s.body requires method POST", field)
+		}
+
+		sides := map[string]string{domain.SideBuy: domain.SideBuy, domain.SideSell: domain.SideSell}
+		for side, value := range exchange.Sides {
+			side = strings.ToUpper(strings.TrimSpace(side))
+			if side != domain.SideBuy && side != domain.SideSell {
+				return nil, fmt.Errorf("%s.sides contains invalid side %q (supported: BUY, SELL)", field, side)
+			}
+			sides[side] = strings.TrimSpace(value)
+		}
+		exchange.Sides = sides
+
+		exchange.ItemsPath = strings.TrimSpace(exchange.ItemsPath)
+		exchange.SuccessPath = strings.TrimSpace(exchange.SuccessPath)
+		fields := &exchange.Fields
+		for _, path := range []*string{&fields.Price, &fields.MinAmount, &fields.MaxAmount, &fields.AvailableAmount, &fields.Merchant, &fields.MerchantID, &fields.PayMethods} {
+			*path = strings.TrimSpace(*path)
+		}
+		if fields.Price == "" || fields.MinAmount == "" || fields.MaxAmount == "" {
+			return nil, fmt.Errorf("%s.fields price, min_amount and max_amount must not be empty", field)
+		}
+
+		result = append(result, exchange)
+	}
+	return result, nil
+}
+
+func normalizeTargetAmounts(field string, values []float64) ([]float64, error) {
+	normalized := make([]float64, 0, len(values))
+	seen := make(map[float64]struct{}, len(values))
+	for _, amount := range values {
+		if math.IsNaN(amount) || math.IsInf(amount, 0) || amount < 0 {
+			return nil, fmt.Errorf("%s must be >= 0, got %.4f", field, amount)
+		}
+		if _, exists := seen[amount]; exists {
+			continue
+		}
+		seen[amount] = struct{}{}
+		normalized = append(normalized, amount)
+	}
+	sort.Float64s(normalized)
+	return normalized, nil
+}
+
+// usdStablecoins default their Forex reference to USD against the market fiat.
+var usdStablecoins = map[string]struct{}{"USDT": {}, "USDC": {}}
+
+func normalizeMarkets(markets []MarketConfig) ([]MarketConfig, error) {
+	if len(markets) == 0 {
+		return []MarketConfig{DefaultMarket()}, nil
+	}
+
+	result := make([]MarketConfig, 0, len(markets))
+	seen := make(map[string]struct{}, len(markets))
+	for index, market := range markets {
+		field := fmt.Sprintf("monitor.markets[%d]", index)
+		market.Symbol = strings.ToUpper(strings.TrimSpace(market.Symbol))
+		market.Fiat = strings.ToUpper(strings.TrimSpace(market.Fiat))
+		market.ForexBase = strings.ToUpper(strings.TrimSpace(market.ForexBase))
+		if !isCurrencyCode(market.Symbol, 2, 6) {
+			return nil, fmt.Errorf("%s.symbol must be 2-6 letters or digits, got %q", field, market.Symbol)
+		}
+		if !isCurrencyCode(market.Fiat, 3, 3) {
+			return nil, fmt.Errorf("%s.fiat must be a 3-letter currency code, got %q", field, market.Fiat)
+		}
+		if market.ForexBase == "" {
+			if _, ok := usdStablecoins[market.Symbol]; ok {
+				market.ForexBase = "USD"
+			}
+		} else if !isCurrencyCode(market.ForexBase, 3, 3) || market.ForexBase == market.Fiat {
+			return nil, fmt.Errorf("%s.forex_base must be a 3-letter currency code other than the fiat, got %q", field, market.ForexBase)
+		}
+
+		if len(market.TargetAmounts) > 0 {
+			amounts, err := normalizeTargetAmounts(field+".target_amounts", market.TargetAmounts)
+			if err != nil {
+				return nil, err
+			}
+			market.TargetAmounts = amounts
+		}
+
+		if _, exists := seen[market.Key()]; exists {
+			return nil, fmt.Errorf("%s duplicates market %s", field, market.Key())
+		}
+		seen[market.Key()] = struct{}{}
+		result = append(result, market)
+	}
+	return result, nil
+}
+
+func normalizePollSchedules(schedules []PollScheduleConfig, custom []CustomExchangeConfig) ([]PollScheduleConfig, error) {
+	if len(schedules) == 0 {
+		return nil, nil
+	}
+
+	result := make([]PollScheduleConfig, 0, len(schedules))
+	for index, schedule := range schedules {
+		field := fmt.Sprintf("monitor.poll_schedules[%d]", index)
+		if schedule.Exchange = strings.TrimSpace(schedule.Exchange); schedule.Exchange != "" {
+			name, err := normalizeExchangeName(schedule.Exchange, custom)
+			if err != nil {
+				return nil, fmt.Errorf("%s.exchange: %w", field, err)
+			}
+			schedule.Exchange = name
+		}
+		if schedule.Market = strings.ToUpper(strings.TrimSpace(schedule.Market)); schedule.Market != "" {
+			if _, _, ok := domain.ParseMarketKey(schedule.Market); !ok {
+				return nil, fmt.Errorf("%s.market must look like USDT/CNY, got %q", field, schedule.Market)
+			}
+		}
+		if len(schedule.Amounts) > 0 {
+			amounts, err := normalizeTargetAmounts(field+".amounts", schedule.Amounts)
+			if err != nil {
+				return nil, err
+			}
+			schedule.Amounts = amounts
+		}
+		cron, err := normalizeCron(field+".cron", schedule.Cron)
+		if err != nil {
+			return nil, err
+		}
+		schedule.Cron = cron
+		if (schedule.IntervalMinutes > 0) == (schedule.Cron != "") || schedule.IntervalMinutes < 0 {
+			return nil, fmt.Errorf("%s must set either interval_minutes > 0 or cron", field)
+		}
+		result = append(result, schedule)
+	}
+	return result, nil
+}
+
+func normalizeCron(field, expr string) (string, error) {
+	expr = strings.Join(strings.Fields(expr), " ")
+	if expr == "" {
+		return "", nil
+	}
+	if _, err := schedule.ParseCron(expr); err != nil {
+		return "", fmt.Errorf("%s: %w", field, err)
+	}
+	return expr, nil
+}
+
+func normalizeBlackouts(blackouts []BlackoutWindowConfig, custom []CustomExchangeConfig) ([]BlackoutWindowConfig, error) {
+	if len(blackouts) == 0 {
+		return nil, nil
+	}
+
+	result := make([]BlackoutWindowConfig, 0, len(blackouts))
+	for index, blackout := range blackouts {
+		field := fmt.Sprintf("monitor.collection_blackouts[%d]", index)
+		if blackout.Exchange = strings.TrimSpace(blackout.Exchange); blackout.Exchange != "" {
+			name, err := normalizeExchangeName(blackout.Exchange, custom)
+			if err != nil {
+				return nil, fmt.Errorf("%s.exchange: %w", field, err)
+			}
+			blackout.Exchange = name
+		}
+		blackout.Start = strings.TrimSpace(blackout.Start)
+		blackout.End = strings.TrimSpace(blackout.End)
+		weekdays := make([]string, 0, len(blackout.Weekdays))
+		for _, weekday := range blackout.Weekdays {
+			weekdays = append(weekdays, strings.ToLower(strings.TrimSpace(weekday)))
+		}
+		blackout.Weekdays = weekdays
+		if _, err := schedule.ParseWindow(blackout.Start, blackout.End, blackout.Weekdays); err != nil {
+			return nil, fmt.Errorf("%s: %w", field, err)
+		}
+		result = append(result, blackout)
+	}
+
+	// Global windows apply to every exchange, so each exchange is checked with
+	// them, and the global windows alone cover exchanges without their own.
+	scopes := []string{""}
+	for _, blackout := range result {
+		if blackout.Exchange != "" {
+			scopes = append(scopes, blackout.Exchange)
+		}
+	}
+	for _, exchange := range scopes {
+		if blackoutsCoverWeek(MonitorConfig{CollectionBlackouts: result}.blackoutWindows(exchange)) {
+			if exchange == "" {
+				return nil, fmt.Errorf("monitor.collection_blackouts cover the whole week, so collection would never run")
+			}
+			return nil, fmt.Errorf("monitor.collection_blackouts cover the whole week for %s, so it would never be collected", exchange)
+		}
+	}
+	return result, nil
+}
+
+// blackoutsCoverWeek reports whether windows leave no minute of a week free.
+// Windows have minute precision, so checking every minute is exact.
+func blackoutsCoverWeek(windows []schedule.Window) bool {
+	if len(windows) == 0 {
+		return false
+	}
+	// A week without DST changes, starting on a Sunday.
+	start := time.Date(2026, time.January, 4, 0, 0, 0, 0, time.UTC)
+	for t := start; t.Before(start.Add(maxBlackoutSpan)); t = t.Add(time.Minute) {
+		covered := false
+		for _, window := range windows {
+			if _, ok := window.Until(t); ok {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+func isCurrencyCode(value string, minLen, maxLen int) bool {
+	if len(value) < minLen || len(value) > maxLen {
+		return false
+	}
+	for _, r := range value {
+		if (r < 'A' || r > 'Z') && (r < '0' || r > '9') {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeSides(values []string) ([]string, error) {
+	if len(values) == 0 {
+		return []string{domain.SideBuy}, nil
+	}
+
+	result := make([]string, 0, len(values))
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range values {
+		side := strings.ToUpper(strings.TrimSpace(value))
+		if side != domain.SideBuy && side != domain.SideSell {
+			return nil, fmt.Errorf("monitor.sides contains invalid side %q (supported: BUY, SELL)", value)
+		}
+		if _, exists := seen[side]; exists {
+			continue
+		}
+		seen[side] = struct{}{}
+		result = append(result, side)
+	}
+	return result, nil
+}
+
+func normalizePayMethodFilters(values []string) ([]string, error) {
+	var result []string
+	seen := make(map[string]struct{}, len(values))
+	for _, value := range trimNonEmptyStrings(values) {
+		method, ok := domain.CanonicalPayMethodName(value)
+		if !ok {
+			return nil, fmt.Errorf("monitor.pay_method_filters contains unknown pay method %q (supported: 银行卡, 微信, 支付宝, QQ 钱包)", value)
+		}
+		if _, exists := seen[method]; exists {
+			continue
+		}
+		seen[method] = struct{}{}
+		result = append(result, method)
+	}
+	return result, nil
+}
+
+func normalizeLeaderElection(election *LeaderElectionConfig) error {
+	election.LeaseName = strings.TrimSpace(election.LeaseName)
+	election.Identity = strings.TrimSpace(election.Identity)
+	if !election.Enabled {
+		return nil
+	}
+	if election.LeaseName == "" || len(election.LeaseName) > 64 {
+		return fmt.Errorf("leader_election.lease_name must be 1 to 64 characters")
+	}
+	if len(election.Identity) > 128 {
+		return fmt.Errorf("leader_election.identity must be at most 128 characters")
+	}
+	if election.RenewSeconds <= 0 {
+		return fmt.Errorf("leader_election.renew_seconds must be greater than 0")
+	}
+	if election.LeaseSeconds < 2*election.RenewSeconds {
+		return fmt.Errorf("leader_election.lease_seconds must be at least twice renew_seconds")
+	}
+	return nil
+}
+
+func normalizeWebhook(webhook *WebhookConfig) error {
+	webhook.URL = strings.TrimSpace(webhook.URL)
+	webhook.SignatureHeader = strings.TrimSpace(webhook.SignatureHeader)
+	if !webhook.Enabled {
+		return nil
+	}
+	parsed, err := url.Parse(webhook.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fmt.Errorf("notification.webhook.url must be an http(s) URL, got %q", webhook.URL)
+	}
+	for key, value := range webhook.Headers {
+		if strings.TrimSpace(key) == "" || strings.ContainsAny(key+value, "\r\n") {
+			return fmt.Errorf("notification.webhook.headers contains an invalid header %q", key)
+		}
+	}
+	if webhook.Secret != "" && webhook.SignatureHeader == "" {
+		return fmt.Errorf("notification.webhook.signature_header must not be empty when secret is set")
+	}
+	if webhook.TimeoutSeconds < 0 {
+		return fmt.Errorf("notification.webhook.timeout_seconds must be >= 0")
+	}
+	if webhook.MaxRetries < 0 || webhook.MaxRetries > 10 {
+		return fmt.Errorf("notification.webhook.max_retries must be between 0 and 10")
+	}
+	return nil
+}
+
+func normalizeTelegram(telegram *TelegramConfig) error {
+	telegram.BotToken = strings.TrimSpace(telegram.BotToken)
+	telegram.APIBaseURL = strings.TrimRight(strings.TrimSpace(telegram.APIBaseURL), "/")
+	telegram.ChatIDs = trimNonEmptyStrings(telegram.ChatIDs)
+	telegram.AdminChatIDs = trimNonEmptyStrings(telegram.AdminChatIDs)
+	if !telegram.Enabled {
+		return nil
+	}
+	if telegram.BotToken == "" {
+		return fmt.Errorf("notification.telegram.bot_token must not be empty")
+	}
+	if len(telegram.ChatIDs) == 0 {
+		return fmt.Errorf("notification.telegram.chat_ids must not be empty")
+	}
+	if telegram.APIBaseURL != "" {
+		parsed, err := url.Parse(telegram.APIBaseURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("notification.telegram.api_base_url must be an http(s) URL, got %q", telegram.APIBaseURL)
+		}
+	}
+	if telegram.TimeoutSeconds < 0 {
+		return fmt.Errorf("notification.telegram.timeout_seconds must be >= 0")
+	}
+	// Commands come from chats, never from channels, so admins need numeric ids.
+	for _, id := range telegram.AdminChatIDs {
+		if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+			return fmt.Errorf("notification.telegram.admin_chat_ids must be numeric chat ids, got %q", id)
+		}
+	}
+	return nil
+}
+
+func normalizeRobot(name string, robot *RobotConfig, signing bool) error {
+	robot.WebhookURL = strings.TrimSpace(robot.WebhookURL)
+	robot.Secret = strings.TrimSpace(robot.Secret)
+	if !robot.Enabled {
+		return nil
+	}
+	parsed, err := url.Parse(robot.WebhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		// The URL carries the robot's token, so it is not echoed.
+		return fmt.Errorf("notification.%s.webhook_url must be an http(s) URL", name)
+	}
+	if robot.Secret != "" && !signing {
+		return fmt.Errorf("notification.%s.secret is not supported; %s robots are not signed", name, name)
+	}
+	if robot.TimeoutSeconds < 0 {
+		return fmt.Errorf("notification.%s.timeout_seconds must be >= 0", name)
+	}
+	return nil
+}
+
+func normalizeDigest(digest *DigestConfig) error {
+	digest.Recipients = trimNonEmptyStrings(digest.Recipients)
+	if !digest.Enabled {
+		return nil
+	}
+	var err error
+	if digest.Cron, err = normalizeCron("notification.digest.cron", digest.Cron); err != nil {
+		return err
+	}
+	if digest.Cron == "" {
+		return fmt.Errorf("notification.digest.cron must not be empty")
+	}
+	if digest.PeriodHours <= 0 || digest.PeriodHours > 90*24 {
+		return fmt.Errorf("notification.digest.period_hours must be between 1 and 2160")
+	}
+	if digest.TopMerchants < 0 || digest.TopMerchants > 20 {
+		return fmt.Errorf("notification.digest.top_merchants must be between 0 and 20")
+	}
+	return nil
+}
+
+func validateRateLimit(field string, limit RateLimitConfig) error {
+	if math.IsNaN(limit.RequestsPerSecond) || math.IsInf(limit.RequestsPerSecond, 0) || limit.RequestsPerSecond < 0 {
+		return fmt.Errorf("%s.requests_per_second must be >= 0", field)
+	}
+	if limit.Burst < 0 {
+		return fmt.Errorf("%s.burst must be >= 0", field)
+	}
+	return nil
+}
+
+func validateMerchantFilter(filter MerchantFilterConfig) error {
+	if math.IsNaN(filter.MinCompletionRate) || filter.MinCompletionRate < 0 || filter.MinCompletionRate > 100 {
+		return fmt.Errorf("monitor.merchant_filter.min_completion_rate must be between 0 and 100")
+	}
+	if filter.MinMonthOrders < 0 {
+		return fmt.Errorf("monitor.merchant_filter.min_month_orders must be >= 0")
+	}
+	if math.IsNaN(filter.MaxAvgReleaseSeconds) || math.IsInf(filter.MaxAvgReleaseSeconds, 0) || filter.MaxAvgReleaseSeconds < 0 {
+		return fmt.Errorf("monitor.merchant_filter.max_avg_release_seconds must be >= 0")
+	}
+	return nil
+}
+
+func normalizeExchangeHTTP(settings map[string]ExchangeHTTPConfig, custom []CustomExchangeConfig) (map[string]ExchangeHTTPConfig, error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+
+	result := make(map[string]ExchangeHTTPConfig, len(settings))
+	for name, setting := range settings {
+		normalizedName, err := normalizeExchangeName(name, custom)
+		if err != nil {
+			return nil, fmt.Errorf("exchange_http: %w", err)
+		}
+		if _, exists := result[normalizedName]; exists {
+			return nil, fmt.Errorf("exchange_http.%s is configured twice", normalizedName)
+		}
+		field := "exchange_http." + strings.ToLower(normalizedName)
+
+		setting.Proxy = strings.TrimSpace(setting.Proxy)
+		if setting.Proxy != "" {
+			proxyURL, err := url.Parse(setting.Proxy)
+			if err != nil || proxyURL.Host == "" {
+				return nil, fmt.Errorf("%s.proxy must be an absolute URL", field)
+			}
+			switch proxyURL.Scheme {
+			case "http", "https", "socks5", "socks5h":
+			default:
+				return nil, fmt.Errorf("%s.proxy scheme must be http, https or socks5", field)
+			}
+		}
+
+		headers := make(map[string]string, len(setting.Headers))
+		for key, value := range setting.Headers {
+			key = strings.TrimSpace(key)
+			if key == "" {
+				return nil, fmt.Errorf("%s.headers contains an empty header name", field)
+			}
+			headers[key] = strings.TrimSpace(value)
+		}
+		setting.Headers = headers
+
+		if setting.TimeoutSeconds < 0 || setting.TimeoutSeconds > 300 {
+			return nil, fmt.Errorf("%s.timeout_seconds must be between 0 and 300", field)
+		}
+
+		setting.TLS.ServerName = strings.TrimSpace(setting.TLS.ServerName)
+		setting.TLS.MinVersion = strings.TrimSpace(setting.TLS.MinVersion)
+		if _, err := TLSVersion(setting.TLS.MinVersion); err != nil {
+			return nil, fmt.Errorf("%s.tls.min_version: %w", field, err)
+		}
+
+		result[normalizedName] = setting
+	}
+	return result, nil
+}
+
+// TLSVersion maps a configured minimum TLS version to its crypto/tls value;
+// empty keeps the Go default.
+func TLSVersion(version string) (uint16, error) {
+	switch version {
+	case "":
+		return 0, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q (supported: 1.2, 1.3)", version)
+	}
+}
+
 func normalizeAllowedOrigins(values []string) ([]string, error) {
 	values = trimNonEmptyStrings(values)
 	result := make([]string, 0, len(values))
@@ -4,7 +4,8 @@ const state = {
         c2c_interval_minutes: 3,
         forex_interval_hours: 1,
         forex_max_age_hours: 6,
-        target_amounts: []
+        target_amounts: [],
+        markets: []
     },
     alertBenchmark: {
         benchmark_price: null,
@@ -18,12 +19,15 @@ const state = {
     version: 'unknown',
     supportedExchanges: [],
     historyKeys: {},
+    currentMarket: '',
+    currentPayMethod: '',
     currentAmount: null,
     currentRange: '1d',
-    chartInstance: null
+    chartInstance: null,
+    spreadChartInstance: null
 };
 
-const FOREX_SERIES_NAME = 'USD/CNY 汇率';
+const FOREX_SERIES_NAME = '参考汇率';
 
 // Initialization
 document.addEventListener('DOMContentLoaded', () => {
@@ -47,6 +51,8 @@ function getElements() {
     return {
         tabs: document.querySelectorAll('.tab-btn'),
         tabContents: document.querySelectorAll('.tab-content'),
+        marketSelect: document.getElementById('market-select'),
+        payMethodSelect: document.getElementById('pay-method-select'),
         amountSelect: document.getElementById('amount-select'),
         rangeBtns: document.querySelectorAll('.range-btn'),
         refreshBtn: document.getElementById('refresh-btn'),
@@ -69,7 +75,9 @@ function getElements() {
         addAmountBtn: document.getElementById('add-amount-btn'),
         saveConfigBtn: document.getElementById('save-config-btn'),
         saveStatus: document.getElementById('save-status'),
+        nextRuns: document.getElementById('next-runs'),
         mainChart: document.getElementById('main-chart'),
+        spreadChart: document.getElementById('spread-chart'),
         alertStatusTableBody: document.querySelector('#alert-status-table tbody'),
         systemStatusIndicator: document.getElementById('system-status-indicator'),
         statusDetailsTooltip: document.querySelector('.status-details-tooltip'),
@@ -96,7 +104,10 @@ function initTabs() {
             
             // Resize chart if showing dashboard
             if (target === 'dashboard' && state.chartInstance) {
-                setTimeout(() => state.chartInstance.resize(), 100);
+                setTimeout(() => {
+                    state.chartInstance.resize();
+                    state.spreadChartInstance.resize();
+                }, 100);
             }
             
             if (target === 'dashboard') {
@@ -213,9 +224,34 @@ function initChart() {
         }
     });
     
+    state.spreadChartInstance = echarts.init(el.spreadChart);
+    state.spreadChartInstance.setOption({
+        title: { text: '跨所搬砖价差 (卖出所 − 买入所)' },
+        tooltip: {
+            trigger: 'axis',
+            formatter: function (params) {
+                if (!params || params.length === 0) return '';
+                let result = `${escapeHTML(params[0].axisValueLabel)}<br/>`;
+                params.forEach(item => {
+                    // value array: [date, spread, sell_price, buy_price, spread_percent]
+                    const [, spread, sellPrice, buyPrice, percent] = item.value;
+                    result += `${item.marker} ${escapeHTML(item.seriesName)}: ${formatNumber(spread)} CNY (${formatNumber(percent)}%)`;
+                    result += `<br/><span style="font-size:12px;color:#666;margin-left:14px">卖 ${formatNumber(sellPrice)} / 买 ${formatNumber(buyPrice)}</span><br/>`;
+                });
+                return result;
+            }
+        },
+        legend: { data: [], type: 'scroll', top: 32 },
+        grid: { left: '3%', right: '4%', top: 72, bottom: '3%', containLabel: true },
+        xAxis: { type: 'time', boundaryGap: false },
+        yAxis: { type: 'value', scale: true },
+        series: []
+    });
+
     // Responsive
     window.addEventListener('resize', () => {
         state.chartInstance.resize();
+        state.spreadChartInstance.resize();
     });
 }
 
@@ -224,6 +260,22 @@ function bindEvents() {
     const el = getElements();
 
     // Dashboard Controls
+    if (el.marketSelect) {
+        el.marketSelect.addEventListener('change', async (e) => {
+            state.currentMarket = e.target.value;
+            renderConfigUI();
+            await Promise.all([loadDashboardBenchmark(), loadAlertBenchmark()]);
+            loadChartData();
+        });
+    }
+
+    if (el.payMethodSelect) {
+        el.payMethodSelect.addEventListener('change', (e) => {
+            state.currentPayMethod = e.target.value;
+            loadChartData();
+        });
+    }
+
     if (el.amountSelect) {
         el.amountSelect.addEventListener('change', async (e) => {
             state.currentAmount = Number(e.target.value);
@@ -295,8 +347,17 @@ async function loadConfig() {
             c2c_interval_minutes: config.C2CIntervalMinutes || config.c2c_interval_minutes || 3,
             forex_interval_hours: config.ForexIntervalHours || config.forex_interval_hours || 1,
             forex_max_age_hours: config.ForexMaxAgeHours || config.forex_max_age_hours || 6,
-            target_amounts: config.TargetAmounts || config.target_amounts || []
+            target_amounts: config.TargetAmounts || config.target_amounts || [],
+            markets: config.Markets || config.markets || [],
+            pay_method_filters: config.PayMethodFilters || config.pay_method_filters || []
         };
+        state.schedule = config.schedule || {};
+        if (!state.config.markets.some(market => marketKey(market) === state.currentMarket)) {
+            state.currentMarket = state.config.markets.length > 0 ? marketKey(state.config.markets[0]) : '';
+        }
+        if (!state.config.pay_method_filters.includes(state.currentPayMethod)) {
+            state.currentPayMethod = '';
+        }
 
         renderConfigUI();
     } catch (error) {
@@ -387,10 +448,29 @@ async function loadDashboardBenchmark() {
 }
 
 function alertBenchmarkURL(targetAmount) {
-    const baseURL = `${AppConfig.apiBaseUrl}/api/alerts/benchmark`;
-    return targetAmount === null || targetAmount === undefined
-        ? baseURL
-        : `${baseURL}?amount=${encodeURIComponent(targetAmount)}`;
+    const params = new URLSearchParams();
+    if (state.currentMarket) params.set('market', state.currentMarket);
+    if (targetAmount !== null && targetAmount !== undefined) params.set('amount', targetAmount);
+    const query = params.toString();
+    return `${AppConfig.apiBaseUrl}/api/alerts/benchmark${query ? `?${query}` : ''}`;
+}
+
+function marketKey(market) {
+    return `${market.symbol}/${market.fiat}`;
+}
+
+// currentMarketAmounts returns the amount tiers of the selected market, which
+// inherit the global tiers unless the market configures its own.
+function currentMarketAmounts() {
+    const market = state.config.markets.find(item => marketKey(item) === state.currentMarket);
+    if (market && Array.isArray(market.target_amounts) && market.target_amounts.length > 0) {
+        return market.target_amounts;
+    }
+    return state.config.target_amounts;
+}
+
+function marketQuery() {
+    return state.currentMarket ? `&market=${encodeURIComponent(state.currentMarket)}` : '';
 }
 
 function normalizeAlertBenchmark(data) {
@@ -437,6 +517,7 @@ async function saveAlertBenchmark() {
             headers: adminHeaders(token),
             body: JSON.stringify({
                 benchmark_price: benchmarkPrice,
+                ...(state.currentMarket ? { market: state.currentMarket } : {}),
                 ...(state.benchmarkScope === null ? {} : { target_amount: state.benchmarkScope })
             })
         });
@@ -481,8 +562,10 @@ async function resetAlert(key) {
 
     const payload = {
         exchange: parts.exchange,
+        market: parts.market,
         side: parts.side,
-        amount: parts.amount
+        amount: parts.amount,
+        ...(parts.payMethod ? { pay_method: parts.payMethod } : {})
     };
 
     try {
@@ -548,7 +631,8 @@ async function loadChartData() {
     
     state.chartInstance.showLoading();
     try {
-        const url = `${AppConfig.apiBaseUrl}/api/v1/history?amount=${state.currentAmount}&range=${state.currentRange}`;
+        const payMethodQuery = state.currentPayMethod ? `&pay_method=${encodeURIComponent(state.currentPayMethod)}` : '';
+        const url = `${AppConfig.apiBaseUrl}/api/v1/history?amount=${state.currentAmount}&range=${state.currentRange}${marketQuery()}${payMethodQuery}`;
         const response = await fetch(url);
         if (!response.ok) throw new Error('Failed to fetch history');
 
@@ -560,6 +644,50 @@ async function loadChartData() {
         console.error('Error loading history:', error);
         state.chartInstance.hideLoading();
     }
+    loadSpreadData();
+}
+
+async function loadSpreadData() {
+    if (state.currentAmount === null) return;
+
+    state.spreadChartInstance.showLoading();
+    try {
+        const url = `${AppConfig.apiBaseUrl}/api/v1/spreads?amount=${state.currentAmount}&range=${state.currentRange}${marketQuery()}`;
+        const response = await fetch(url);
+        if (!response.ok) throw new Error('Failed to fetch spreads');
+
+        const json = await response.json();
+        updateSpreadChart(json.data.series || []);
+    } catch (error) {
+        console.error('Error loading spreads:', error);
+        state.spreadChartInstance.hideLoading();
+    }
+}
+
+function updateSpreadChart(seriesList) {
+    const series = seriesList.map(item => {
+        const points = (item.points || []).map(point => [
+            new Date(point.t * 1000),
+            point.v,
+            point.sell_price,
+            point.buy_price,
+            point.spread_percent
+        ]);
+        return {
+            name: `${item.sell_exchange} → ${item.buy_exchange}`,
+            type: 'line',
+            data: points,
+            showSymbol: points.length <= 1,
+            symbolSize: points.length <= 1 ? 10 : 4,
+            lineStyle: { width: 2 }
+        };
+    });
+
+    state.spreadChartInstance.setOption({
+        legend: { data: series.map(item => item.name) },
+        series
+    }, { replaceMerge: ['series'] });
+    state.spreadChartInstance.hideLoading();
 }
 
 async function loadSystemStatus() {
@@ -612,6 +740,13 @@ function updateSystemStatusUI(statusMap) {
         const details = document.createElement('div');
         details.appendChild(createTextElement('div', key, 'status-item-name'));
         details.appendChild(createTextElement('div', `Last check: ${lastCheck}`, 'status-item-time'));
+        if (val.circuit && val.circuit.state !== 'closed') {
+            const until = val.circuit.open_until ? ` until ${new Date(val.circuit.open_until).toLocaleTimeString()}` : '';
+            details.appendChild(createTextElement('div', `Circuit ${val.circuit.state.replace('_', '-')}${until}`, 'status-item-time'));
+        }
+        if (val.rate_limit && val.rate_limit.waited_requests > 0) {
+            details.appendChild(createTextElement('div', `Rate limited: ${val.rate_limit.waited_requests}/${val.rate_limit.requests} waited, max ${val.rate_limit.max_wait_seconds.toFixed(1)}s`, 'status-item-time'));
+        }
         if (val.message) {
             details.appendChild(createTextElement('div', val.message, `status-item-message ${normalizedStatus}`));
         }
@@ -697,6 +832,13 @@ function renderConfigUI() {
     if (el.c2cIntervalInput) el.c2cIntervalInput.value = state.config.c2c_interval_minutes;
     if (el.forexIntervalInput) el.forexIntervalInput.value = state.config.forex_interval_hours;
     if (el.forexMaxAgeInput) el.forexMaxAgeInput.value = state.config.forex_max_age_hours;
+    if (el.nextRuns) {
+        const formatRun = value => value ? new Date(value).toLocaleString() : 'running';
+        const schedule = state.schedule || {};
+        el.nextRuns.textContent = schedule.leader === false
+            ? 'Standby replica: another replica is collecting'
+            : `Next C2C run: ${formatRun(schedule.next_c2c_run)} · Next Forex run: ${formatRun(schedule.next_forex_run)}`;
+    }
     renderBenchmarkScopeOptions();
 
     // Render Tags in Settings
@@ -720,10 +862,33 @@ function renderConfigUI() {
         });
     }
 
-    // Update Dashboard Selector
+    // Update Dashboard Selectors
+    if (el.marketSelect) {
+        el.marketSelect.replaceChildren();
+        state.config.markets.forEach(market => {
+            const option = document.createElement('option');
+            option.value = marketKey(market);
+            option.textContent = marketKey(market);
+            option.selected = option.value === state.currentMarket;
+            el.marketSelect.appendChild(option);
+        });
+    }
+
+    if (el.payMethodSelect) {
+        el.payMethodSelect.replaceChildren();
+        ['', ...state.config.pay_method_filters].forEach(method => {
+            const option = document.createElement('option');
+            option.value = method;
+            option.textContent = method || '全部';
+            option.selected = method === state.currentPayMethod;
+            el.payMethodSelect.appendChild(option);
+        });
+    }
+
     if (el.amountSelect) {
         el.amountSelect.replaceChildren();
-        const sortedAmounts = [...state.config.target_amounts].sort((a,b) => a-b);
+        const marketAmounts = currentMarketAmounts();
+        const sortedAmounts = [...marketAmounts].sort((a,b) => a-b);
         
         sortedAmounts.forEach(amt => {
             const option = document.createElement('option');
@@ -734,8 +899,8 @@ function renderConfigUI() {
         });
         
         // If current amount is not in list (e.g. deleted), pick first
-        if (!state.config.target_amounts.includes(state.currentAmount) && state.config.target_amounts.length > 0) {
-            state.currentAmount = state.config.target_amounts[0];
+        if (!marketAmounts.includes(state.currentAmount) && marketAmounts.length > 0) {
+            state.currentAmount = marketAmounts[0];
             el.amountSelect.value = state.currentAmount;
         }
     }
@@ -744,7 +909,8 @@ function renderConfigUI() {
 function renderBenchmarkScopeOptions() {
     const el = getElements();
     if (!el.alertBenchmarkScope) return;
-    if (state.benchmarkScope !== null && !state.config.target_amounts.includes(state.benchmarkScope)) {
+    const marketAmounts = currentMarketAmounts();
+    if (state.benchmarkScope !== null && !marketAmounts.includes(state.benchmarkScope)) {
         state.benchmarkScope = null;
     }
 
@@ -755,7 +921,7 @@ function renderBenchmarkScopeOptions() {
     globalOption.selected = state.benchmarkScope === null;
     el.alertBenchmarkScope.appendChild(globalOption);
 
-    const sortedAmounts = [...state.config.target_amounts].sort((a, b) => a - b);
+    const sortedAmounts = [...marketAmounts].sort((a, b) => a - b);
     sortedAmounts.forEach(amount => {
         const option = document.createElement('option');
         option.value = amount;
@@ -939,11 +1105,11 @@ async function readErrorResponse(response) {
 }
 
 function parseAlertKey(key) {
-    const match = /^(.+)-(BUY|SELL)-(.+)$/.exec(key);
+    const match = /^(.+?)-([A-Z0-9]+\/[A-Z0-9]+)-(BUY|SELL)-(\d+(?:\.\d+)?)(?:-(.+))?$/.exec(key);
     if (!match) return null;
-    const amount = Number(match[3]);
+    const amount = Number(match[4]);
     if (!Number.isFinite(amount) || amount < 0) return null;
-    return { exchange: match[1], side: match[2], amount };
+    return { exchange: match[1], market: match[2], side: match[3], amount, payMethod: match[5] || '' };
 }
 
 function createTextElement(tagName, text, className = '') {
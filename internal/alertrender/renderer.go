@@ -0,0 +1,210 @@
+// Package alertrender renders alert events for each notification channel from
+// templates that operators may override.
+package alertrender
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+//go:embed templates/*.tmpl
+var defaultTemplates embed.FS
+
+// Formats lists the body formats, each rendered from <format>.tmpl.
+var Formats = []string{domain.FormatHTML, domain.FormatMarkdown, domain.FormatText, domain.FormatJSON}
+
+var eventTypes = []domain.AlertEventType{
+	domain.AlertEventPriceOpportunity,
+	domain.AlertEventNewLow,
+	domain.AlertEventRoundTrip,
+	domain.AlertEventServiceDown,
+	domain.AlertEventServiceRecovered,
+	domain.AlertEventForexStale,
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "~", `\~`, ">", `\>`, "#", `\#`, "|", `\|`,
+)
+
+var funcs = map[string]any{
+	"md": markdownEscaper.Replace,
+	"json": func(value any) (string, error) {
+		data, err := json.Marshal(value)
+		return string(data), err
+	},
+	"time": func(t time.Time) string {
+		return t.Format(time.RFC3339)
+	},
+}
+
+// View is the data templates are executed with: the event, its rendered
+// subject and its market key.
+type View struct {
+	domain.AlertEvent
+	Subject string `json:"subject"`
+	Market  string `json:"market,omitempty"`
+}
+
+// bodyTemplate hides whether a format is an html/template or text/template.
+type bodyTemplate struct {
+	execute func(w io.Writer, name string, data any) error
+	defines func(name string) bool
+}
+
+// Renderer renders alert subjects from subject.tmpl and bodies from one file
+// per format. Each file defines a template per event type, named after it
+// (e.g. "new_low"), and a "default" for types it does not define. HTML is
+// rendered with html/template, every other format with text/template.
+type Renderer struct {
+	subject *texttemplate.Template
+	bodies  map[string]bodyTemplate
+}
+
+var defaultRenderer = mustNew()
+
+func mustNew() *Renderer {
+	renderer, err := New("")
+	if err != nil {
+		panic(err)
+	}
+	return renderer
+}
+
+// Default returns the renderer of the built-in templates.
+func Default() *Renderer {
+	return defaultRenderer
+}
+
+// New parses the built-in templates and then the files of the same name in
+// dir, if dir is set. Overrides only need to define the templates they
+// change. Every template is rendered once with a sample event so mistakes are
+// reported at startup rather than when an alert fires.
+func New(dir string) (*Renderer, error) {
+	subject, err := parseText("subject.tmpl", dir)
+	if err != nil {
+		return nil, err
+	}
+	renderer := &Renderer{subject: subject, bodies: make(map[string]bodyTemplate, len(Formats))}
+	for _, format := range Formats {
+		name := format + ".tmpl"
+		if format == domain.FormatHTML {
+			tmpl, err := parseHTML(name, dir)
+			if err != nil {
+				return nil, err
+			}
+			renderer.bodies[format] = bodyTemplate{
+				execute: tmpl.ExecuteTemplate,
+				defines: func(name string) bool { return tmpl.Lookup(name) != nil },
+			}
+			continue
+		}
+		tmpl, err := parseText(name, dir)
+		if err != nil {
+			return nil, err
+		}
+		renderer.bodies[format] = bodyTemplate{
+			execute: tmpl.ExecuteTemplate,
+			defines: func(name string) bool { return tmpl.Lookup(name) != nil },
+		}
+	}
+
+	for _, eventType := range eventTypes {
+		sample := domain.AlertEvent{Type: eventType, Time: time.Now(), Symbol: "USDT", Fiat: "CNY"}
+		for _, format := range Formats {
+			if _, _, err := renderer.Render(sample, format); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return renderer, nil
+}
+
+// Render returns the subject of event and its body in format, one of Formats.
+func (r *Renderer) Render(event domain.AlertEvent, format string) (subject, body string, err error) {
+	var buf bytes.Buffer
+	if err := r.subject.ExecuteTemplate(&buf, templateName(event.Type, func(name string) bool { return r.subject.Lookup(name) != nil }), event); err != nil {
+		return "", "", fmt.Errorf("render %s subject: %w", event.Type, err)
+	}
+	// Subjects become mail headers and chat titles, so they stay on one line.
+	subject = strings.Join(strings.Fields(buf.String()), " ")
+
+	tmpl, ok := r.bodies[format]
+	if !ok {
+		return "", "", fmt.Errorf("unknown alert format %q", format)
+	}
+	buf.Reset()
+	view := View{AlertEvent: event, Subject: subject, Market: event.Market()}
+	if err := tmpl.execute(&buf, templateName(event.Type, tmpl.defines), view); err != nil {
+		return "", "", fmt.Errorf("render %s %s body: %w", event.Type, format, err)
+	}
+	return subject, strings.TrimSpace(buf.String()), nil
+}
+
+func templateName(eventType domain.AlertEventType, defines func(string) bool) string {
+	if defines(string(eventType)) {
+		return string(eventType)
+	}
+	return "default"
+}
+
+func parseText(name, dir string) (*texttemplate.Template, error) {
+	tmpl := texttemplate.New(name).Funcs(funcs)
+	sources, err := templateSources(name, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		if tmpl, err = tmpl.Parse(source); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+	}
+	return tmpl, nil
+}
+
+func parseHTML(name, dir string) (*htmltemplate.Template, error) {
+	tmpl := htmltemplate.New(name).Funcs(funcs)
+	sources, err := templateSources(name, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, source := range sources {
+		if tmpl, err = tmpl.Parse(source); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+	}
+	return tmpl, nil
+}
+
+// templateSources returns the built-in template and the override in dir, if
+// there is one.
+func templateSources(name, dir string) ([]string, error) {
+	builtIn, err := defaultTemplates.ReadFile("templates/" + name)
+	if err != nil {
+		return nil, err
+	}
+	sources := []string{string(builtIn)}
+	if dir == "" {
+		return sources, nil
+	}
+	override, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return sources, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read alert template: %w", err)
+	}
+	return append(sources, string(override)), nil
+}
@@ -0,0 +1,96 @@
+package alertrender
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+func testEvent() domain.AlertEvent {
+	return domain.AlertEvent{
+		Type:          domain.AlertEventNewLow,
+		AlertType:     "Lower",
+		Time:          time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC),
+		Exchange:      domain.ExchangeGate,
+		Symbol:        "USDT",
+		Fiat:          "CNY",
+		Side:          domain.SideBuy,
+		Merchant:      "<b>fast_pay</b>",
+		Price:         6.9,
+		Benchmark:     7.0,
+		ForexPair:     "USDCNY",
+		ForexRate:     7.2,
+		SpreadPercent: 4.17,
+	}
+}
+
+func TestDefaultTemplatesRenderEveryFormat(t *testing.T) {
+	renderer := Default()
+
+	subject, body, err := renderer.Render(testEvent(), domain.FormatHTML)
+	if err != nil {
+		t.Fatalf("render html: %v", err)
+	}
+	if subject != "📉 New Low! Gate <b>fast_pay</b> USDT Price: 6.9000 (Benchmark: 7.0000)" {
+		t.Fatalf("unexpected subject %q", subject)
+	}
+	if !strings.Contains(body, "&lt;b&gt;fast_pay&lt;/b&gt;") || !strings.Contains(body, "USDT/CNY") || !strings.Contains(body, "Time: 2026-10-16T08:00:00Z") {
+		t.Fatalf("expected an escaped HTML body, got %q", body)
+	}
+
+	_, body, err = renderer.Render(testEvent(), domain.FormatMarkdown)
+	if err != nil || !strings.Contains(body, `fast\_pay`) || !strings.HasPrefix(body, "**📉 New Low**") {
+		t.Fatalf("expected a Markdown body with escaped values, got %q, %v", body, err)
+	}
+
+	_, body, err = renderer.Render(testEvent(), domain.FormatText)
+	if err != nil || !strings.Contains(body, "Merchant: <b>fast_pay</b>") {
+		t.Fatalf("expected a plain text body, got %q, %v", body, err)
+	}
+
+	_, body, err = renderer.Render(testEvent(), domain.FormatJSON)
+	var payload map[string]any
+	if err != nil || json.Unmarshal([]byte(body), &payload) != nil {
+		t.Fatalf("expected a JSON body, got %q, %v", body, err)
+	}
+	if payload["event_type"] != "new_low" || payload["market"] != "USDT/CNY" || payload["subject"] != subject || payload["price"] != 6.9 {
+		t.Fatalf("unexpected JSON payload: %v", payload)
+	}
+
+	if _, _, err := renderer.Render(testEvent(), "xml"); err == nil {
+		t.Fatal("expected an unknown format to be rejected")
+	}
+}
+
+func TestTemplateOverridesReplaceOnlyTheTemplatesTheyDefine(t *testing.T) {
+	dir := t.TempDir()
+	override := `{{define "new_low"}}{{.Exchange}} hit {{printf "%.2f" .Price}}{{end}}`
+	if err := os.WriteFile(filepath.Join(dir, "markdown.tmpl"), []byte(override), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	renderer, err := New(dir)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	if _, body, err := renderer.Render(testEvent(), domain.FormatMarkdown); err != nil || body != "Gate hit 6.90" {
+		t.Fatalf("expected the override to render, got %q, %v", body, err)
+	}
+	event := testEvent()
+	event.Type = domain.AlertEventPriceOpportunity
+	if _, body, err := renderer.Render(event, domain.FormatMarkdown); err != nil || !strings.Contains(body, "C2C Opportunity") {
+		t.Fatalf("expected other events to keep the built-in template, got %q, %v", body, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "html.tmpl"), []byte(`{{define "service_down"}}{{.Missing}}{{end}}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := New(dir); err == nil || !strings.Contains(err.Error(), "service_down") {
+		t.Fatalf("expected a broken override to be reported when loading, got %v", err)
+	}
+}
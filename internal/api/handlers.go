@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"c2c_monitor/config"
 	"c2c_monitor/internal/appmeta"
 	"c2c_monitor/internal/domain"
 	"c2c_monitor/internal/service"
@@ -24,7 +26,8 @@ func NewHandler(svc *service.MonitorService) *Handler {
 }
 
 func (h *Handler) GetHistory(c *gin.Context) {
-	// Params: range (1d, 7d), amount (required), exchange (optional)
+	// Params: range (1d, 7d), amount (required), rank (optional, default 1), side (optional, default BUY),
+	// market (optional, default first configured market)
 	rangeStr := c.Query("range")
 	amountStr := c.Query("amount")
 
@@ -39,36 +42,43 @@ func (h *Handler) GetHistory(c *gin.Context) {
 		return
 	}
 
-	// Calculate start time
-	now := time.Now()
-	var startTime time.Time
-	granularity := domain.HistoryGranularityRaw
-	switch rangeStr {
-	case "1d":
-		startTime = now.Add(-24 * time.Hour)
-	case "7d":
-		startTime = now.Add(-7 * 24 * time.Hour)
-		granularity = domain.HistoryGranularityHour
-	case "30d":
-		startTime = now.Add(-30 * 24 * time.Hour)
-		granularity = domain.HistoryGranularityHour
-	case "all":
-		// Zero time means no lower bound in repository filters.
-		startTime = time.Time{}
-		granularity = domain.HistoryGranularityDay
-	default:
-		startTime = now.Add(-24 * time.Hour) // Default 1d
+	rank, err := parseHistoryRank(c.Query("rank"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	side, err := parseHistorySide(c.Query("side"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	market, err := h.resolveMarket(c.Query("market"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	payMethod, err := parsePayMethodFilter(c.Query("pay_method"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
 	}
 
+	now := time.Now()
+	startTime, granularity := parseHistoryRange(rangeStr, now)
+
 	filter := domain.PriceQueryFilter{
-		Symbol:       "USDT",
-		Fiat:         "CNY",
-		Side:         "BUY", // Default monitoring side
-		TargetAmount: &amount,
-		Rank:         1, // Only get best price for chart
-		StartTime:    startTime,
-		EndTime:      now,
-		Limit:        5000, // Safety limit
+		Symbol:          market.Symbol,
+		Fiat:            market.Fiat,
+		Side:            side,
+		TargetAmount:    &amount,
+		PayMethodFilter: payMethod,
+		Rank:            rank,
+		StartTime:       startTime,
+		EndTime:         now,
+		Limit:           5000, // Safety limit
 	}
 
 	resp := gin.H{"forex": []gin.H{}}
@@ -91,22 +101,28 @@ func (h *Handler) GetHistory(c *gin.Context) {
 			list = append(list, gin.H{
 				"t":                p.CreatedAt.Unix(),
 				"v":                p.Price,
+				"rank":             p.Rank,
 				"merchant":         p.Merchant,
 				"pay_methods":      domain.NormalizePayMethodsString(p.PayMethods),
 				"min_amount":       p.MinAmount,
 				"max_amount":       p.MaxAmount,
 				"available_amount": p.AvailableAmount,
+				"reputation":       p.Reputation,
 			})
 		}
 		resp[responseKey] = list
 	}
 
-	// 1. Forex
-	forexHistory, err := h.svc.GetForexHistoryByGranularity(c.Request.Context(), "USDCNY", startTime, now, granularity)
-	if granularity != domain.HistoryGranularityRaw && (err != nil || len(forexHistory) == 0) {
-		forexHistory, err = h.svc.GetForexHistory(c.Request.Context(), "USDCNY", startTime, now)
+	// 1. Forex (markets without a Forex reference return an empty series)
+	forexPair := market.ForexPair()
+	var forexHistory []*domain.ForexRate
+	if forexPair != "" {
+		forexHistory, err = h.svc.GetForexHistoryByGranularity(c.Request.Context(), forexPair, startTime, now, granularity)
+		if granularity != domain.HistoryGranularityRaw && (err != nil || len(forexHistory) == 0) {
+			forexHistory, err = h.svc.GetForexHistory(c.Request.Context(), forexPair, startTime, now)
+		}
 	}
-	if err == nil {
+	if forexPair != "" && err == nil {
 		var list []gin.H
 		for _, f := range forexHistory {
 			list = append(list, gin.H{"t": f.CreatedAt.Unix(), "v": f.Rate})
@@ -122,8 +138,76 @@ func (h *Handler) GetHistory(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"code": 200, "data": resp})
 }
 
+// GetSpreads returns round-trip spread series (sell exchange minus buy exchange)
+// for one amount tier.
+func (h *Handler) GetSpreads(c *gin.Context) {
+	amountStr := c.Query("amount")
+	if amountStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "amount parameter is required"})
+		return
+	}
+	amount, err := strconv.ParseFloat(amountStr, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) || amount < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amount"})
+		return
+	}
+
+	market, err := h.resolveMarket(c.Query("market"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	now := time.Now()
+	startTime, _ := parseHistoryRange(c.Query("range"), now)
+	spreads, err := h.svc.GetRoundTripSpreadHistory(c.Request.Context(), domain.SpreadQueryFilter{
+		Symbol:       market.Symbol,
+		Fiat:         market.Fiat,
+		TargetAmount: &amount,
+		StartTime:    startTime,
+		EndTime:      now,
+		Limit:        5000, // Safety limit
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	series := []gin.H{}
+	seriesIndex := make(map[string]int)
+	for _, spread := range spreads {
+		key := spread.SellExchange + ">" + spread.BuyExchange
+		index, exists := seriesIndex[key]
+		if !exists {
+			index = len(series)
+			seriesIndex[key] = index
+			series = append(series, gin.H{
+				"sell_exchange": spread.SellExchange,
+				"buy_exchange":  spread.BuyExchange,
+				"points":        []gin.H{},
+			})
+		}
+		series[index]["points"] = append(series[index]["points"].([]gin.H), gin.H{
+			"t":              spread.CreatedAt.Unix(),
+			"v":              spread.Spread,
+			"sell_price":     spread.SellPrice,
+			"buy_price":      spread.BuyPrice,
+			"spread_percent": spread.SpreadPercent,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"code": 200, "data": gin.H{"market": market.Key(), "series": series}})
+}
+
+// configResponse is the monitor config plus when the collection loops next
+// run; the schedule is read-only and ignored by UpdateConfig.
+type configResponse struct {
+	config.MonitorConfig
+	Schedule domain.ScheduleStatus `json:"schedule"`
+}
+
 func (h *Handler) GetConfig(c *gin.Context) {
-	c.JSON(http.StatusOK, h.svc.GetConfig())
+	c.JSON(http.StatusOK, configResponse{MonitorConfig: h.svc.GetConfig(), Schedule: h.svc.GetSchedule()})
 }
 
 func (h *Handler) GetMeta(c *gin.Context) {
@@ -185,9 +269,9 @@ func (h *Handler) GetAlertBenchmark(c *gin.Context) {
 		return
 	}
 
-	status, err := h.svc.GetAlertBenchmark(c.Request.Context(), targetAmount)
+	status, err := h.svc.GetAlertBenchmark(c.Request.Context(), normalizeMarketParam(c.Query("market")), targetAmount)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidAlertBenchmark) {
+		if errors.Is(err, service.ErrInvalidAlertBenchmark) || errors.Is(err, service.ErrInvalidMarket) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -199,6 +283,7 @@ func (h *Handler) GetAlertBenchmark(c *gin.Context) {
 }
 
 type UpdateAlertBenchmarkRequest struct {
+	Market         string   `json:"market"`
 	BenchmarkPrice *float64 `json:"benchmark_price"`
 	TargetAmount   *float64 `json:"target_amount"`
 }
@@ -220,9 +305,9 @@ func (h *Handler) UpdateAlertBenchmark(c *gin.Context) {
 		return
 	}
 
-	status, err := h.svc.UpdateAlertBenchmark(c.Request.Context(), *req.BenchmarkPrice, req.TargetAmount)
+	status, err := h.svc.UpdateAlertBenchmark(c.Request.Context(), normalizeMarketParam(req.Market), *req.BenchmarkPrice, req.TargetAmount)
 	if err != nil {
-		if errors.Is(err, service.ErrInvalidAlertBenchmark) {
+		if errors.Is(err, service.ErrInvalidAlertBenchmark) || errors.Is(err, service.ErrInvalidMarket) {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
@@ -233,6 +318,189 @@ func (h *Handler) UpdateAlertBenchmark(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": status})
 }
 
+// GetAlertHistory pages through past alerts, newest first. Without a range it
+// covers all history.
+func (h *Handler) GetAlertHistory(c *gin.Context) {
+	filter := domain.AlertHistoryFilter{Limit: 50}
+	switch kind := strings.ToLower(strings.TrimSpace(c.Query("kind"))); kind {
+	case "", domain.AlertKindPrice, domain.AlertKindRoundTrip, domain.AlertKindService:
+		filter.Kind = kind
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "kind must be price, round_trip or service"})
+		return
+	}
+	if raw := strings.TrimSpace(c.Query("exchange")); raw != "" {
+		// Service alerts are recorded under their service name.
+		filter.Exchange = raw
+		if exchange, err := domain.NormalizeExchangeName(raw); err == nil {
+			filter.Exchange = exchange
+		}
+	}
+	if raw := c.Query("market"); raw != "" {
+		symbol, fiat, ok := domain.ParseMarketKey(normalizeMarketParam(raw))
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "market must be SYMBOL/FIAT"})
+			return
+		}
+		filter.Symbol, filter.Fiat = symbol, fiat
+	}
+	amount, err := parseOptionalTargetAmount(c.Query("amount"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	filter.TargetAmount = amount
+	if raw := c.Query("range"); raw != "" {
+		filter.EndTime = time.Now()
+		filter.StartTime, _ = parseHistoryRange(raw, filter.EndTime)
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		filter.Limit = limit
+	}
+	if raw := c.Query("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "offset must be >= 0"})
+			return
+		}
+		filter.Offset = offset
+	}
+
+	records, total, err := h.svc.GetAlertHistory(c.Request.Context(), filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if records == nil {
+		records = []*domain.AlertRecord{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       records,
+		"pagination": gin.H{"total": total, "limit": filter.Limit, "offset": filter.Offset},
+	})
+}
+
+// GetAlertOutbox lists alert deliveries, newest first, optionally filtered by
+// status (pending, sent or dead).
+func (h *Handler) GetAlertOutbox(c *gin.Context) {
+	status := strings.ToLower(strings.TrimSpace(c.Query("status")))
+	switch status {
+	case "", domain.AlertPending, domain.AlertSent, domain.AlertDead:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, sent or dead"})
+		return
+	}
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 500"})
+			return
+		}
+		limit = parsed
+	}
+
+	alerts, err := h.svc.GetAlertOutbox(c.Request.Context(), domain.AlertOutboxFilter{Status: status, Limit: limit})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if alerts == nil {
+		alerts = []*domain.OutboxAlert{}
+	}
+	c.JSON(http.StatusOK, gin.H{"data": alerts})
+}
+
+// RetryAlert puts a dead alert back in the delivery queue.
+func (h *Handler) RetryAlert(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid alert id"})
+		return
+	}
+
+	if err := h.svc.RequeueAlert(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrAlertNotDead) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to requeue alert"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "requeued"})
+}
+
+// resolveMarket looks up a configured market; an empty value selects the first one.
+func (h *Handler) resolveMarket(raw string) (config.MarketConfig, error) {
+	key := normalizeMarketParam(raw)
+	market, ok := h.svc.GetConfig().Market(key)
+	if !ok {
+		return config.MarketConfig{}, fmt.Errorf("market %s is not configured", key)
+	}
+	return market, nil
+}
+
+func normalizeMarketParam(raw string) string {
+	return strings.ToUpper(strings.TrimSpace(raw))
+}
+
+// parseHistoryRange maps a range parameter to a start time and the storage
+// granularity that keeps the response size bounded. Unknown values mean 1d.
+func parseHistoryRange(rangeStr string, now time.Time) (time.Time, domain.HistoryGranularity) {
+	switch rangeStr {
+	case "7d":
+		return now.Add(-7 * 24 * time.Hour), domain.HistoryGranularityHour
+	case "30d":
+		return now.Add(-30 * 24 * time.Hour), domain.HistoryGranularityHour
+	case "all":
+		// Zero time means no lower bound in repository filters.
+		return time.Time{}, domain.HistoryGranularityDay
+	default:
+		return now.Add(-24 * time.Hour), domain.HistoryGranularityRaw
+	}
+}
+
+func parseHistorySide(raw string) (string, error) {
+	side := strings.ToUpper(strings.TrimSpace(raw))
+	switch side {
+	case "":
+		return domain.SideBuy, nil
+	case domain.SideBuy, domain.SideSell:
+		return side, nil
+	default:
+		return "", fmt.Errorf("side must be BUY or SELL")
+	}
+}
+
+// parsePayMethodFilter accepts any alias of a known pay method; empty selects
+// the unfiltered tier.
+func parsePayMethodFilter(raw string) (string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return "", nil
+	}
+	payMethod, ok := domain.CanonicalPayMethodName(raw)
+	if !ok {
+		return "", fmt.Errorf("unknown pay_method %q", raw)
+	}
+	return payMethod, nil
+}
+
+func parseHistoryRank(raw string) (int, error) {
+	if strings.TrimSpace(raw) == "" {
+		return 1, nil
+	}
+	rank, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || rank < 1 || rank > domain.MaxDepth {
+		return 0, fmt.Errorf("rank must be between 1 and %d", domain.MaxDepth)
+	}
+	return rank, nil
+}
+
 func parseOptionalTargetAmount(raw string) (*float64, error) {
 	if strings.TrimSpace(raw) == "" {
 		return nil, nil
@@ -245,9 +513,11 @@ func parseOptionalTargetAmount(raw string) (*float64, error) {
 }
 
 type ResetAlertRequest struct {
-	Exchange string   `json:"exchange" binding:"required"`
-	Side     string   `json:"side" binding:"required"`
-	Amount   *float64 `json:"amount" binding:"required"`
+	Exchange  string   `json:"exchange" binding:"required"`
+	Market    string   `json:"market"`
+	Side      string   `json:"side" binding:"required"`
+	Amount    *float64 `json:"amount" binding:"required"`
+	PayMethod string   `json:"pay_method"`
 }
 
 func (h *Handler) ResetAlert(c *gin.Context) {
@@ -274,13 +544,112 @@ func (h *Handler) ResetAlert(c *gin.Context) {
 		return
 	}
 
-	if err := h.svc.ResetAlertState(c.Request.Context(), exchange, side, *req.Amount); err != nil {
+	payMethod, err := parsePayMethodFilter(req.PayMethod)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Markets no longer configured can still be reset, so only the key format is checked.
+	market := normalizeMarketParam(req.Market)
+	if market == "" {
+		primary, _ := h.svc.GetConfig().Market("")
+		market = primary.Key()
+	}
+
+	if err := h.svc.ResetAlertState(c.Request.Context(), exchange, market, side, *req.Amount, payMethod); err != nil {
+		if errors.Is(err, service.ErrInvalidMarket) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset alert state"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"status": "reset"})
 }
 
+// CollectRequest triggers a collection outside the schedule. When neither c2c
+// nor forex is set, both run.
+type CollectRequest struct {
+	C2C       *bool     `json:"c2c"`
+	Forex     *bool     `json:"forex"`
+	Exchanges []string  `json:"exchanges"`
+	Markets   []string  `json:"markets"`
+	Amounts   []float64 `json:"amounts"`
+}
+
+// collectTimeout bounds a manual collection, retries and rate-limit waits
+// included. The response may be written until collectWriteTimeout, which
+// outlasts the server's WriteTimeout.
+const (
+	collectTimeout      = 2 * time.Minute
+	collectWriteTimeout = collectTimeout + 10*time.Second
+)
+
+func (h *Handler) Collect(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 64<<10)
+	var req CollectRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	both := req.C2C == nil && req.Forex == nil
+	collect := service.CollectRequest{
+		C2C:       both || req.C2C != nil && *req.C2C,
+		Forex:     both || req.Forex != nil && *req.Forex,
+		Exchanges: req.Exchanges,
+		Amounts:   req.Amounts,
+	}
+	for _, market := range req.Markets {
+		collect.Markets = append(collect.Markets, normalizeMarketParam(market))
+	}
+
+	// Writers that do not support deadlines, such as test recorders, have none
+	// to extend.
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(collectWriteTimeout))
+	ctx, cancel := context.WithTimeout(c.Request.Context(), collectTimeout)
+	defer cancel()
+	result, err := h.svc.CollectNow(ctx, collect)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidCollectScope) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrNotLeader) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "collection did not finish in time", "data": result})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result})
+}
+
+// PreviewDigest builds the market digest for the period ending now without
+// sending it. format=html returns the email body itself.
+func (h *Handler) PreviewDigest(c *gin.Context) {
+	format := strings.ToLower(strings.TrimSpace(c.Query("format")))
+	if format != "" && format != "json" && format != "html" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "format must be json or html"})
+		return
+	}
+
+	digest, err := h.svc.BuildDigest(c.Request.Context(), time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	subject, body := h.svc.RenderDigest(digest)
+	if format == "html" {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(body))
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": digest, "subject": subject, "body": body})
+}
+
 func (h *Handler) GetServiceStatus(c *gin.Context) {
 	status := h.svc.GetServiceStatuses()
 	c.JSON(http.StatusOK, gin.H{"data": status})
@@ -33,6 +33,7 @@ func SetupRouter(svc *service.MonitorService, cfg *config.Config) *gin.Engine {
 	v1 := r.Group("/api/v1")
 	{
 		v1.GET("/history", h.GetHistory)
+		v1.GET("/spreads", h.GetSpreads)
 	}
 
 	// Config Routes
@@ -43,23 +44,40 @@ func SetupRouter(svc *service.MonitorService, cfg *config.Config) *gin.Engine {
 	// Alert Routes
 	r.GET("/api/alerts/status", h.GetAlertStatus)
 	r.GET("/api/alerts/benchmark", h.GetAlertBenchmark)
+	r.GET("/api/alerts/history", h.GetAlertHistory)
+	r.GET("/api/alerts/outbox", h.GetAlertOutbox)
 
 	// Service Status
 	r.GET("/api/status", h.GetServiceStatus)
+	r.GET("/api/digest/preview", h.PreviewDigest)
 
 	adminToken := ""
 	if cfg != nil {
 		adminToken = cfg.App.AdminToken
 	}
 	admin := r.Group("/api")
-	admin.Use(requireAdminToken(adminToken))
+	admin.Use(requireAdminToken(adminToken), requireLeader(svc))
 	admin.POST("/config", h.UpdateConfig)
 	admin.POST("/alerts/benchmark", h.UpdateAlertBenchmark)
 	admin.POST("/alerts/reset", h.ResetAlert)
+	admin.POST("/alerts/outbox/:id/retry", h.RetryAlert)
+	admin.POST("/collect", h.Collect)
 
 	return r
 }
 
+// requireLeader sends admin writes to the replica that collects, since only
+// its in-memory config and alert state are used.
+func requireLeader(svc *service.MonitorService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !svc.IsLeader() {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": service.ErrNotLeader.Error() + "; retry against the leader"})
+			return
+		}
+		c.Next()
+	}
+}
+
 func requireAdminToken(expectedToken string) gin.HandlerFunc {
 	expected := []byte(strings.TrimSpace(expectedToken))
 
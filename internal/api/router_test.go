@@ -3,6 +3,7 @@ package api
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -52,6 +53,39 @@ func TestAdminRoutesRequireBearerToken(t *testing.T) {
 	}
 }
 
+func TestAdminWritesRequireLeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _ := newTestService()
+	// Start is never called, so this replica never acquires the lease.
+	svc.SetLeaderLease(apiTestLease{}, config.LeaderElectionConfig{LeaseName: "c2c-monitor", Identity: "replica-1", LeaseSeconds: 30, RenewSeconds: 10})
+	router := SetupRouter(svc, testAPIConfig())
+
+	req := httptest.NewRequest(http.MethodPost, "/api/config", bytes.NewBufferString(`{"forex_interval_hours":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected followers to refuse admin writes, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/status", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected followers to serve reads, got %d", recorder.Code)
+	}
+}
+
+type apiTestLease struct{}
+
+func (apiTestLease) AcquireLease(ctx context.Context, name, holder string, now time.Time, ttl time.Duration) (bool, error) {
+	return false, nil
+}
+
+func (apiTestLease) ReleaseLease(ctx context.Context, name, holder string) error {
+	return nil
+}
+
 func TestHealthAndReadinessRoutes(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc, _ := newTestService()
@@ -70,6 +104,128 @@ func TestHealthAndReadinessRoutes(t *testing.T) {
 	}
 }
 
+func TestGetConfigIncludesSchedule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _ := newTestService()
+	router := SetupRouter(svc, testAPIConfig())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/config", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", recorder.Code)
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if string(body["c2c_interval_minutes"]) != "3" {
+		t.Fatalf("expected config fields at the top level, got %s", recorder.Body.String())
+	}
+	if string(body["schedule"]) != `{"leader":true,"next_c2c_run":null,"next_forex_run":null}` {
+		t.Fatalf("expected an empty schedule before the loops start, got %s", body["schedule"])
+	}
+}
+
+func TestRejectedConfigUpdateLeavesConfigUnchanged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _ := newTestService()
+	cfg := svc.GetConfig()
+	cfg.CollectionBlackouts = []config.BlackoutWindowConfig{{Start: "08:00", End: "08:30", Weekdays: []string{"wed"}}}
+	if err := svc.UpdateConfig(cfg); err != nil {
+		t.Fatalf("update config: %v", err)
+	}
+	router := SetupRouter(svc, testAPIConfig())
+
+	body := `{"collection_blackouts":[{"start":"01:00","end":"02:00","weekdays":["someday"]}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/config", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected the invalid weekday to be rejected, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+
+	blackouts := svc.GetConfig().CollectionBlackouts
+	if len(blackouts) != 1 || blackouts[0].Start != "08:00" || blackouts[0].End != "08:30" || blackouts[0].Weekdays[0] != "wed" {
+		t.Fatalf("expected the running blackouts to be unchanged, got %#v", blackouts)
+	}
+}
+
+func TestHistoryValidatesRank(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _ := newTestService()
+	router := SetupRouter(svc, testAPIConfig())
+
+	tests := []struct {
+		query      string
+		wantStatus int
+	}{
+		{query: "amount=30", wantStatus: http.StatusOK},
+		{query: "amount=30&rank=5", wantStatus: http.StatusOK},
+		{query: "amount=30&rank=0", wantStatus: http.StatusBadRequest},
+		{query: "amount=30&rank=21", wantStatus: http.StatusBadRequest},
+		{query: "amount=30&rank=two", wantStatus: http.StatusBadRequest},
+		{query: "amount=30&side=sell", wantStatus: http.StatusOK},
+		{query: "amount=30&side=hold", wantStatus: http.StatusBadRequest},
+		{query: "amount=30&market=usdt/cny", wantStatus: http.StatusOK},
+		{query: "amount=30&market=BTC/CNY", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/history?"+tt.query, nil))
+		if recorder.Code != tt.wantStatus {
+			t.Fatalf("%s: expected status %d, got %d: %s", tt.query, tt.wantStatus, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
+func TestSpreadsGroupsSeriesByExchangePair(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, repo := newTestService()
+	now := time.Now()
+	repo.spreads = []*domain.RoundTripSpread{
+		{CreatedAt: now, SellExchange: domain.ExchangeOKX, BuyExchange: domain.ExchangeGate, TargetAmount: 30, Spread: 0.05},
+		{CreatedAt: now, SellExchange: domain.ExchangeGate, BuyExchange: domain.ExchangeOKX, TargetAmount: 30, Spread: -0.07},
+		{CreatedAt: now.Add(time.Minute), SellExchange: domain.ExchangeOKX, BuyExchange: domain.ExchangeGate, TargetAmount: 30, Spread: 0.06},
+	}
+	router := SetupRouter(svc, testAPIConfig())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/spreads?amount=30&range=7d", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if repo.spreadFilter.TargetAmount == nil || *repo.spreadFilter.TargetAmount != 30 {
+		t.Fatalf("expected amount filter 30, got %#v", repo.spreadFilter)
+	}
+
+	var body struct {
+		Data struct {
+			Series []struct {
+				SellExchange string `json:"sell_exchange"`
+				BuyExchange  string `json:"buy_exchange"`
+				Points       []struct {
+					V float64 `json:"v"`
+				} `json:"points"`
+			} `json:"series"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body.Data.Series) != 2 || len(body.Data.Series[0].Points) != 2 || body.Data.Series[0].SellExchange != domain.ExchangeOKX {
+		t.Fatalf("unexpected spread series: %s", recorder.Body.String())
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/v1/spreads", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected missing amount to be rejected, got %d", recorder.Code)
+	}
+}
+
 func TestResetAlertAcceptsZeroAmount(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc, repo := newTestService()
@@ -89,8 +245,35 @@ func TestResetAlertAcceptsZeroAmount(t *testing.T) {
 	if recorder.Code != http.StatusOK {
 		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
 	}
-	if repo.deletedExchange != domain.ExchangeGate || repo.deletedSide != "BUY" || repo.deletedAmount != 0 {
-		t.Fatalf("unexpected reset target: exchange=%q side=%q amount=%v", repo.deletedExchange, repo.deletedSide, repo.deletedAmount)
+	if repo.deletedExchange != domain.ExchangeGate || repo.deletedMarket != "USDT/CNY" || repo.deletedSide != "BUY" || repo.deletedAmount != 0 {
+		t.Fatalf("unexpected reset target: exchange=%q market=%q side=%q amount=%v", repo.deletedExchange, repo.deletedMarket, repo.deletedSide, repo.deletedAmount)
+	}
+
+	for body, wantStatus := range map[string]int{
+		`{"exchange":"gate","market":"usdt/hkd","side":"buy","amount":0}`:   http.StatusOK,
+		`{"exchange":"gate","market":"USDTHKD","side":"buy","amount":0}`:    http.StatusBadRequest,
+		`{"exchange":"gate","side":"buy","amount":0,"pay_method":"paypal"}`: http.StatusBadRequest,
+	} {
+		req := httptest.NewRequest(http.MethodPost, "/api/alerts/reset", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		if recorder.Code != wantStatus {
+			t.Fatalf("%s: expected status %d, got %d: %s", body, wantStatus, recorder.Code, recorder.Body.String())
+		}
+	}
+	if repo.deletedMarket != "USDT/HKD" {
+		t.Fatalf("expected explicit market to be reset, got %q", repo.deletedMarket)
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/api/alerts/reset", bytes.NewBufferString(`{"exchange":"gate","side":"buy","amount":0,"pay_method":"bank"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK || repo.deletedPayMethod != "银行卡" {
+		t.Fatalf("expected bank-card tier to be reset, got status %d pay method %q", recorder.Code, repo.deletedPayMethod)
 	}
 }
 
@@ -178,6 +361,47 @@ func TestAlertBenchmarkRoutesOnlyAllowLowerPrices(t *testing.T) {
 	}
 }
 
+func TestCollectRunsRequestedCollections(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _ := newTestService()
+	router := SetupRouter(svc, testAPIConfig())
+
+	post := func(body, authorization string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/collect", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	if recorder := post(`{"forex":true}`, ""); recorder.Code != http.StatusUnauthorized {
+		t.Fatalf("expected admin token to be required, got %d", recorder.Code)
+	}
+
+	recorder := post(`{"forex":true}`, "Bearer "+testAdminToken)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var body struct {
+		Data domain.CollectResult `json:"data"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if body.Data.Forex["USDCNY"] != 7.2 || len(body.Data.Prices) != 0 || body.Data.Services["Forex (Reference Sources)"] == nil {
+		t.Fatalf("expected only a forex run, got %s", recorder.Body.String())
+	}
+
+	for _, body := range []string{`{"amounts":[500]}`, `{"exchanges":["unknown"]}`, `{"c2c":false,"forex":false}`} {
+		if recorder := post(body, "Bearer "+testAdminToken); recorder.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d: %s", body, recorder.Code, recorder.Body.String())
+		}
+	}
+}
+
 func TestCORSAllowsOnlyConfiguredOrigins(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	svc, _ := newTestService()
@@ -204,6 +428,105 @@ func TestCORSAllowsOnlyConfiguredOrigins(t *testing.T) {
 	}
 }
 
+func TestAlertOutboxListsAndRetriesDeadAlerts(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, repo := newTestService()
+	repo.outbox = []*domain.OutboxAlert{
+		{ID: 2, Kind: domain.AlertKindPrice, Subject: "Gate USDT/CNY BUY", Status: domain.AlertDead, Attempts: 8, LastError: "SMTP timeout"},
+	}
+	router := SetupRouter(svc, testAPIConfig())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/alerts/outbox?status=dead&limit=10", nil))
+	if recorder.Code != http.StatusOK || !strings.Contains(recorder.Body.String(), `"last_error":"SMTP timeout"`) {
+		t.Fatalf("expected the dead alert to be listed, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if repo.outboxFilter != (domain.AlertOutboxFilter{Status: domain.AlertDead, Limit: 10}) {
+		t.Fatalf("unexpected outbox filter: %#v", repo.outboxFilter)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/alerts/outbox?status=lost", nil))
+	if recorder.Code != http.StatusBadRequest {
+		t.Fatalf("expected unknown status to be rejected, got %d", recorder.Code)
+	}
+
+	retry := func(id string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/alerts/outbox/"+id+"/retry", nil)
+		req.Header.Set("Authorization", "Bearer "+testAdminToken)
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+	if recorder := retry("2"); recorder.Code != http.StatusOK || repo.requeuedAlert != 2 {
+		t.Fatalf("expected the dead alert to be requeued, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	if recorder := retry("3"); recorder.Code != http.StatusConflict {
+		t.Fatalf("expected unknown alerts to conflict, got %d", recorder.Code)
+	}
+}
+
+func TestAlertHistoryFiltersAndPaginates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, repo := newTestService()
+	repo.alertRecords = []*domain.AlertRecord{
+		{ID: 7, Kind: domain.AlertKindPrice, AlertType: "Lower", Exchange: domain.ExchangeGate, Merchant: "m1", Price: 7.0, Benchmark: 7.1, DeliveryStatus: domain.AlertSent},
+	}
+	router := SetupRouter(svc, testAPIConfig())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/alerts/history?kind=price&exchange=gate&market=usdt/cny&amount=1000&range=7d&limit=20&offset=40", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	for _, want := range []string{`"alert_type":"Lower"`, `"delivery_status":"sent"`, `"pagination":{"limit":20,"offset":40,"total":1}`} {
+		if !strings.Contains(recorder.Body.String(), want) {
+			t.Fatalf("expected %s in %s", want, recorder.Body.String())
+		}
+	}
+	filter := repo.historyFilter
+	if filter.Kind != domain.AlertKindPrice || filter.Exchange != domain.ExchangeGate || filter.Symbol != "USDT" || filter.Fiat != "CNY" ||
+		filter.TargetAmount == nil || *filter.TargetAmount != 1000 || filter.StartTime.IsZero() || filter.Limit != 20 || filter.Offset != 40 {
+		t.Fatalf("unexpected history filter: %#v", filter)
+	}
+
+	for _, query := range []string{"kind=email", "market=USDT", "limit=0", "offset=-1"} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/alerts/history?"+query, nil))
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status 400, got %d", query, recorder.Code)
+		}
+	}
+}
+
+func TestPreviewDigestRendersWithoutSending(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc, _ := newTestService()
+	router := SetupRouter(svc, testAPIConfig())
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/digest/preview", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", recorder.Code, recorder.Body.String())
+	}
+	var body struct {
+		Data    domain.Digest `json:"data"`
+		Subject string        `json:"subject"`
+	}
+	if err := json.Unmarshal(recorder.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(body.Data.Tiers) != 3 || !strings.Contains(body.Subject, "Market Digest") {
+		t.Fatalf("expected one tier per configured amount, got %#v", body)
+	}
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/digest/preview?format=html", nil))
+	if recorder.Code != http.StatusOK || !strings.HasPrefix(recorder.Header().Get("Content-Type"), "text/html") || !strings.Contains(recorder.Body.String(), "<table") {
+		t.Fatalf("expected the email body, got %d %q", recorder.Code, recorder.Header().Get("Content-Type"))
+	}
+}
+
 func testAPIConfig() *config.Config {
 	return &config.Config{
 		App: config.AppConfig{
@@ -214,6 +537,33 @@ func testAPIConfig() *config.Config {
 	}
 }
 
+func TestCollectOutlastsTheServerWriteTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	svc := service.NewMonitorService(
+		config.MonitorConfig{ForexIntervalHours: 1, ForexMaxAgeHours: 6, Exchanges: []string{domain.ExchangeGate}},
+		&apiTestRepository{},
+		map[string]domain.IExchange{},
+		slowAPITestForex{delay: 300 * time.Millisecond},
+		apiTestNotifier{},
+	)
+	server := httptest.NewUnstartedServer(SetupRouter(svc, testAPIConfig()))
+	server.Config.WriteTimeout = 100 * time.Millisecond
+	server.Start()
+	defer server.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/api/collect", bytes.NewBufferString(`{"forex":true}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+testAdminToken)
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatalf("expected the slow collection to be answered, got %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", resp.StatusCode)
+	}
+}
+
 func newTestService() (*service.MonitorService, *apiTestRepository) {
 	repo := &apiTestRepository{}
 	svc := service.NewMonitorService(
@@ -238,6 +588,20 @@ func (apiTestForex) GetRate(ctx context.Context, from, to string) (float64, erro
 	return 7.2, nil
 }
 
+// slowAPITestForex answers after delay, like a forex source near its timeout.
+type slowAPITestForex struct {
+	delay time.Duration
+}
+
+func (f slowAPITestForex) GetRate(ctx context.Context, from, to string) (float64, error) {
+	select {
+	case <-time.After(f.delay):
+		return 7.2, nil
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	}
+}
+
 type apiTestNotifier struct{}
 
 func (apiTestNotifier) Send(ctx context.Context, subject, body string) error {
@@ -246,10 +610,19 @@ func (apiTestNotifier) Send(ctx context.Context, subject, body string) error {
 
 type apiTestRepository struct {
 	deletedExchange    string
+	deletedMarket      string
 	deletedSide        string
 	deletedAmount      float64
+	deletedPayMethod   string
 	alertBenchmark     *domain.AlertBenchmark
 	benchmarkOverrides map[float64]*domain.AlertBenchmarkOverride
+	spreads            []*domain.RoundTripSpread
+	spreadFilter       domain.SpreadQueryFilter
+	outbox             []*domain.OutboxAlert
+	outboxFilter       domain.AlertOutboxFilter
+	requeuedAlert      int64
+	alertRecords       []*domain.AlertRecord
+	historyFilter      domain.AlertHistoryFilter
 }
 
 func (r *apiTestRepository) SavePricePoints(ctx context.Context, points []*domain.PricePoint) error {
@@ -264,6 +637,15 @@ func (r *apiTestRepository) GetPriceHistoryByGranularity(ctx context.Context, fi
 	return nil, nil
 }
 
+func (r *apiTestRepository) SaveRoundTripSpreads(ctx context.Context, spreads []*domain.RoundTripSpread) error {
+	return nil
+}
+
+func (r *apiTestRepository) GetRoundTripSpreadHistory(ctx context.Context, filter domain.SpreadQueryFilter) ([]*domain.RoundTripSpread, error) {
+	r.spreadFilter = filter
+	return r.spreads, nil
+}
+
 func (r *apiTestRepository) SaveMerchant(ctx context.Context, merchant *domain.Merchant) error {
 	return nil
 }
@@ -288,10 +670,12 @@ func (r *apiTestRepository) UpsertAlertState(ctx context.Context, state *domain.
 	return nil
 }
 
-func (r *apiTestRepository) DeleteAlertState(ctx context.Context, exchange, side string, amount float64) error {
+func (r *apiTestRepository) DeleteAlertState(ctx context.Context, exchange, symbol, fiat, side string, amount float64, payMethod string) error {
 	r.deletedExchange = exchange
+	r.deletedMarket = domain.MarketKey(symbol, fiat)
 	r.deletedSide = side
 	r.deletedAmount = amount
+	r.deletedPayMethod = payMethod
 	return nil
 }
 
@@ -299,6 +683,42 @@ func (r *apiTestRepository) GetAlertStates(ctx context.Context) ([]*domain.Alert
 	return nil, nil
 }
 
+func (r *apiTestRepository) EnqueueAlert(ctx context.Context, alert *domain.OutboxAlert, record *domain.AlertRecord, state *domain.AlertState) error {
+	return nil
+}
+
+func (r *apiTestRepository) GetDueAlerts(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxAlert, error) {
+	return nil, nil
+}
+
+func (r *apiTestRepository) ClaimAlert(ctx context.Context, id int64, now, until time.Time) (bool, error) {
+	return false, nil
+}
+
+func (r *apiTestRepository) UpdateAlertDelivery(ctx context.Context, alert *domain.OutboxAlert) error {
+	return nil
+}
+
+func (r *apiTestRepository) GetAlertOutbox(ctx context.Context, filter domain.AlertOutboxFilter) ([]*domain.OutboxAlert, error) {
+	r.outboxFilter = filter
+	return r.outbox, nil
+}
+
+func (r *apiTestRepository) RequeueAlert(ctx context.Context, id int64, now time.Time) (bool, error) {
+	for _, alert := range r.outbox {
+		if alert.ID == id && alert.Status == domain.AlertDead {
+			r.requeuedAlert = id
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *apiTestRepository) GetAlertHistory(ctx context.Context, filter domain.AlertHistoryFilter) ([]*domain.AlertRecord, int64, error) {
+	r.historyFilter = filter
+	return r.alertRecords, int64(len(r.alertRecords)), nil
+}
+
 func (r *apiTestRepository) UpsertAlertBenchmark(ctx context.Context, benchmark *domain.AlertBenchmark) error {
 	copyBenchmark := *benchmark
 	r.alertBenchmark = &copyBenchmark
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+	"c2c_monitor/internal/infrastructure/notifier"
+	"c2c_monitor/internal/service"
+)
+
+const (
+	telegramPollWait   = 30 * time.Second
+	telegramRetryDelay = 5 * time.Second
+)
+
+const telegramUsage = `Commands:
+/status - service health
+/price <amount> [market] - latest best prices of an amount tier
+/benchmark [amount] [market] - effective alert benchmark
+/reset <exchange> <BUY|SELL> <amount> [market] [pay_method] - re-arm a new-low alert (admin chats only)`
+
+// TelegramBot answers chat commands through the Telegram Bot API. It polls
+// only while this replica leads, so replicas never compete for updates and
+// resets reach the alert state that is in use. Chats that are not configured
+// are ignored.
+type TelegramBot struct {
+	svc    *service.MonitorService
+	client *notifier.TelegramNotifier
+	chats  map[int64]bool // Chats that may run commands
+	admins map[int64]bool // Chats that may also run /reset
+	offset int64
+}
+
+// NewTelegramBot lets chatIDs read and adminChatIDs also reset alert state.
+// Non-numeric ids, such as @channel names, cannot send commands and are
+// skipped.
+func NewTelegramBot(svc *service.MonitorService, client *notifier.TelegramNotifier, chatIDs, adminChatIDs []string) *TelegramBot {
+	bot := &TelegramBot{svc: svc, client: client, chats: make(map[int64]bool), admins: make(map[int64]bool)}
+	for _, raw := range chatIDs {
+		if id, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			bot.chats[id] = true
+		}
+	}
+	for _, raw := range adminChatIDs {
+		if id, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			bot.chats[id] = true
+			bot.admins[id] = true
+		}
+	}
+	return bot
+}
+
+// Run long-polls for commands until ctx is done.
+func (b *TelegramBot) Run(ctx context.Context) {
+	slog.Info("telegram command handler started", "event", "telegram_bot_started", "chats", len(b.chats), "admin_chats", len(b.admins))
+	for ctx.Err() == nil {
+		if !b.svc.IsLeader() {
+			sleepContext(ctx, telegramRetryDelay)
+			continue
+		}
+		updates, err := b.client.GetUpdates(ctx, b.offset, telegramPollWait)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			delay := telegramRetryDelay
+			var telegramErr *notifier.TelegramError
+			if errors.As(err, &telegramErr) && telegramErr.RetryAfter > delay {
+				delay = telegramErr.RetryAfter
+			}
+			slog.Warn("failed to poll telegram updates", "event", "telegram_poll_failed", "retry_in", delay.String(), "error", err)
+			sleepContext(ctx, delay)
+			continue
+		}
+		for _, update := range updates {
+			b.offset = update.UpdateID + 1
+			b.handle(ctx, update)
+		}
+	}
+}
+
+func (b *TelegramBot) handle(ctx context.Context, update notifier.TelegramUpdate) {
+	if update.Message == nil || !strings.HasPrefix(update.Message.Text, "/") {
+		return
+	}
+	chatID := update.Message.Chat.ID
+	reply, ok := b.Execute(ctx, chatID, update.Message.Text)
+	if !ok {
+		slog.Warn("ignored telegram command from an unknown chat", "event", "telegram_command_rejected", "chat_id", chatID)
+		return
+	}
+	if err := b.client.SendMessage(ctx, strconv.FormatInt(chatID, 10), reply); err != nil {
+		slog.Error("failed to reply to telegram command", "event", "telegram_reply_failed", "chat_id", chatID, "error", err)
+	}
+}
+
+// Execute runs one command from chatID and returns the reply, or false when
+// the chat may not use the bot.
+func (b *TelegramBot) Execute(ctx context.Context, chatID int64, text string) (string, bool) {
+	if !b.chats[chatID] {
+		return "", false
+	}
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return telegramUsage, true
+	}
+	// Commands in groups may be addressed as /status@BotName.
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	args := fields[1:]
+
+	switch command {
+	case "/status":
+		return b.status(), true
+	case "/price":
+		return b.price(args), true
+	case "/benchmark":
+		return b.benchmark(ctx, args), true
+	case "/reset":
+		if !b.admins[chatID] {
+			slog.Warn("rejected telegram reset from a non-admin chat", "event", "telegram_reset_rejected", "chat_id", chatID)
+			return "Only admin chats may reset alerts.", true
+		}
+		return b.reset(ctx, chatID, args), true
+	default:
+		return telegramUsage, true
+	}
+}
+
+func (b *TelegramBot) status() string {
+	statuses := b.svc.GetServiceStatuses()
+	names := make([]string, 0, len(statuses))
+	for name := range statuses {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		status := statuses[name]
+		line := fmt.Sprintf("%s: %s", name, status.Status)
+		if status.Message != "" {
+			line += " - " + status.Message
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (b *TelegramBot) price(args []string) string {
+	if len(args) == 0 || len(args) > 2 {
+		return "Usage: /price <amount> [market]"
+	}
+	amount, err := parseTelegramAmount(args[0])
+	if err != nil {
+		return err.Error()
+	}
+	market := ""
+	if len(args) == 2 {
+		market = normalizeMarketParam(args[1])
+	}
+	prices, err := b.svc.LatestPrices(market, amount)
+	if err != nil {
+		return err.Error()
+	}
+	if len(prices) == 0 {
+		return fmt.Sprintf("No prices collected for amount %s yet.", formatTelegramAmount(amount))
+	}
+
+	lines := []string{fmt.Sprintf("%s, amount %s", domain.MarketKey(prices[0].Symbol, prices[0].Fiat), formatTelegramAmount(amount))}
+	for _, p := range prices {
+		lines = append(lines, fmt.Sprintf("%s %s %.4f %s (%s)", p.Side, p.Exchange, p.Price, p.Merchant, p.CreatedAt.Format("15:04:05")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (b *TelegramBot) benchmark(ctx context.Context, args []string) string {
+	if len(args) > 2 {
+		return "Usage: /benchmark [amount] [market]"
+	}
+	var amount *float64
+	if len(args) > 0 {
+		value, err := parseTelegramAmount(args[0])
+		if err != nil {
+			return err.Error()
+		}
+		amount = &value
+	}
+	market := ""
+	if len(args) == 2 {
+		market = normalizeMarketParam(args[1])
+	}
+	status, err := b.svc.GetAlertBenchmark(ctx, market, amount)
+	if err != nil {
+		return err.Error()
+	}
+
+	scope := "all amounts"
+	if status.TargetAmount != nil {
+		scope = "amount " + formatTelegramAmount(*status.TargetAmount)
+	}
+	return fmt.Sprintf("%s benchmark for %s: %.4f\nGlobal: %.4f\nForex: %.4f", status.Market, scope, status.BenchmarkPrice, status.GlobalBenchmarkPrice, status.ForexRate)
+}
+
+func (b *TelegramBot) reset(ctx context.Context, chatID int64, args []string) string {
+	if len(args) < 3 || len(args) > 5 {
+		return "Usage: /reset <exchange> <BUY|SELL> <amount> [market] [pay_method]"
+	}
+	exchange, err := domain.NormalizeExchangeName(args[0])
+	if err != nil {
+		return err.Error()
+	}
+	side := strings.ToUpper(args[1])
+	if side != domain.SideBuy && side != domain.SideSell {
+		return "side must be BUY or SELL"
+	}
+	amount, err := parseTelegramAmount(args[2])
+	if err != nil {
+		return err.Error()
+	}
+	// Markets no longer configured can still be reset, as through the API.
+	market := ""
+	if len(args) > 3 {
+		market = normalizeMarketParam(args[3])
+	}
+	if market == "" {
+		primary, _ := b.svc.GetConfig().Market("")
+		market = primary.Key()
+	}
+	payMethod := ""
+	if len(args) > 4 {
+		if payMethod, err = parsePayMethodFilter(args[4]); err != nil {
+			return err.Error()
+		}
+	}
+
+	if err := b.svc.ResetAlertState(ctx, exchange, market, side, amount, payMethod); err != nil {
+		if errors.Is(err, service.ErrInvalidMarket) {
+			return err.Error()
+		}
+		slog.Error("failed to reset alert state from telegram", "event", "telegram_reset_failed", "chat_id", chatID, "error", err)
+		return "Failed to reset the alert state."
+	}
+	slog.Info("reset alert state from telegram", "event", "telegram_reset", "chat_id", chatID, "exchange", exchange, "market", market, "side", side, "amount", amount, "pay_method", payMethod)
+	return fmt.Sprintf("Reset %s %s %s %s %s.", exchange, market, side, formatTelegramAmount(amount), payMethodLabel(payMethod))
+}
+
+func parseTelegramAmount(raw string) (float64, error) {
+	amount, err := strconv.ParseFloat(raw, 64)
+	if err != nil || math.IsNaN(amount) || math.IsInf(amount, 0) || amount < 0 {
+		return 0, fmt.Errorf("amount must be a number >= 0")
+	}
+	return amount, nil
+}
+
+func formatTelegramAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+func payMethodLabel(payMethod string) string {
+	if payMethod == "" {
+		return "(any pay method)"
+	}
+	return "(" + payMethod + ")"
+}
+
+func sleepContext(ctx context.Context, delay time.Duration) {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
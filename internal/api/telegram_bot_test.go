@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c2c_monitor/internal/domain"
+	"c2c_monitor/internal/infrastructure/notifier"
+	"c2c_monitor/internal/service"
+)
+
+func TestTelegramBotCommands(t *testing.T) {
+	svc, repo := newTestService()
+	if _, err := svc.CollectNow(context.Background(), service.CollectRequest{Forex: true}); err != nil {
+		t.Fatal(err)
+	}
+	bot := NewTelegramBot(svc, nil, []string{"100", "@alerts"}, []string{"200"})
+	ctx := context.Background()
+
+	if _, ok := bot.Execute(ctx, 300, "/status"); ok {
+		t.Fatal("expected chats outside the whitelist to be ignored")
+	}
+	if reply, _ := bot.Execute(ctx, 100, "/status@C2CMonitorBot"); !strings.Contains(reply, "Gate: Pending") {
+		t.Fatalf("expected service statuses, got %q", reply)
+	}
+	if reply, _ := bot.Execute(ctx, 100, "/price 1000"); !strings.Contains(reply, "No prices collected for amount 1000") {
+		t.Fatalf("expected an empty price reply, got %q", reply)
+	}
+	if reply, _ := bot.Execute(ctx, 100, "/price lots"); !strings.Contains(reply, "amount must be") {
+		t.Fatalf("expected an amount error, got %q", reply)
+	}
+	if reply, _ := bot.Execute(ctx, 100, "/benchmark 1000"); !strings.Contains(reply, "USDT/CNY benchmark for amount 1000") || !strings.Contains(reply, "Forex: 7.2000") {
+		t.Fatalf("expected the tier benchmark, got %q", reply)
+	}
+
+	if reply, _ := bot.Execute(ctx, 100, "/reset binance BUY 500"); !strings.Contains(reply, "Only admin chats") {
+		t.Fatalf("expected non-admin resets to be refused, got %q", reply)
+	}
+	if repo.deletedExchange != "" {
+		t.Fatalf("expected no reset from a non-admin chat, got %q", repo.deletedExchange)
+	}
+	reply, _ := bot.Execute(ctx, 200, "/reset Binance buy 500")
+	if !strings.HasPrefix(reply, "Reset Binance USDT/CNY BUY 500") {
+		t.Fatalf("expected reset confirmation, got %q", reply)
+	}
+	if repo.deletedExchange != domain.ExchangeBinance || repo.deletedMarket != "USDT/CNY" || repo.deletedSide != "BUY" || repo.deletedAmount != 500 || repo.deletedPayMethod != "" {
+		t.Fatalf("unexpected reset: %#v", repo)
+	}
+	if reply, _ := bot.Execute(ctx, 200, "/help"); !strings.Contains(reply, "/reset <exchange>") {
+		t.Fatalf("expected usage, got %q", reply)
+	}
+}
+
+func TestTelegramBotRepliesToPolledCommands(t *testing.T) {
+	replies := make(chan map[string]any, 1)
+	var served atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+			if served.Swap(true) {
+				time.Sleep(50 * time.Millisecond)
+				_, _ = w.Write([]byte(`{"ok":true,"result":[]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"ok":true,"result":[{"update_id":7,"message":{"text":"/price 30","chat":{"id":100}}}]}`))
+		case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			replies <- payload
+			_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	svc, _ := newTestService()
+	client := notifier.NewTelegramNotifier(server.URL, "123:token", []string{"100"}, time.Second)
+	bot := NewTelegramBot(svc, client, []string{"100"}, nil)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go bot.Run(ctx)
+
+	select {
+	case payload := <-replies:
+		if payload["chat_id"] != "100" || !strings.Contains(payload["text"].(string), "amount 30") {
+			t.Fatalf("unexpected reply: %#v", payload)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the command reply")
+	}
+}
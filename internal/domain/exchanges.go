@@ -3,37 +3,138 @@ package domain
 import (
 	"fmt"
 	"strings"
+	"sync"
 )
 
 const (
 	ExchangeBinance = "Binance"
 	ExchangeGate    = "Gate"
 	ExchangeOKX     = "OKX"
+	ExchangeBybit   = "Bybit"
+	ExchangeHTX     = "HTX"
+	ExchangeBitget  = "Bitget"
 )
 
-var supportedExchanges = []string{
-	ExchangeBinance,
-	ExchangeGate,
-	ExchangeOKX,
+// ExchangeInfo is the registry metadata of one venue: its canonical name, the
+// lower-case aliases accepted in configuration, and the key of its series in
+// the history API response.
+type ExchangeInfo struct {
+	Name        string
+	Aliases     []string
+	ResponseKey string
 }
 
-var exchangeAliases = map[string]string{
-	"binance": ExchangeBinance,
-	"gate":    ExchangeGate,
-	"okx":     ExchangeOKX,
+type exchangeRegistry struct {
+	mu      sync.RWMutex
+	order   []string
+	infos   map[string]ExchangeInfo
+	aliases map[string]string
+}
+
+var exchanges = &exchangeRegistry{
+	infos:   make(map[string]ExchangeInfo),
+	aliases: make(map[string]string),
+}
+
+var builtinExchanges = []ExchangeInfo{
+	{Name: ExchangeBinance},
+	{Name: ExchangeGate},
+	{Name: ExchangeOKX, ResponseKey: "okx"},
+	{Name: ExchangeBybit},
+	{Name: ExchangeHTX, Aliases: []string{"huobi"}},
+	{Name: ExchangeBitget},
+}
+
+func init() {
+	for _, info := range builtinExchanges {
+		MustRegisterExchange(info)
+	}
+}
+
+// RegisterExchange adds a venue to the registry. The lower-cased name is
+// always an alias, and an empty ResponseKey defaults to it. Names and aliases
+// must not collide with an existing venue.
+func RegisterExchange(info ExchangeInfo) error {
+	info.Name = strings.TrimSpace(info.Name)
+	if info.Name == "" {
+		return fmt.Errorf("exchange name must not be empty")
+	}
+	lowerName := strings.ToLower(info.Name)
+	if info.ResponseKey == "" {
+		info.ResponseKey = lowerName
+	}
+
+	aliases := []string{lowerName}
+	for _, alias := range info.Aliases {
+		alias = strings.ToLower(strings.TrimSpace(alias))
+		if alias != "" && alias != lowerName {
+			aliases = append(aliases, alias)
+		}
+	}
+	info.Aliases = aliases
+
+	exchanges.mu.Lock()
+	defer exchanges.mu.Unlock()
+
+	if _, exists := exchanges.infos[info.Name]; exists {
+		return fmt.Errorf("exchange %q is already registered", info.Name)
+	}
+	for _, alias := range aliases {
+		if owner, exists := exchanges.aliases[alias]; exists {
+			return fmt.Errorf("exchange alias %q is already registered by %s", alias, owner)
+		}
+	}
+	for _, registered := range exchanges.infos {
+		if registered.ResponseKey == info.ResponseKey {
+			return fmt.Errorf("exchange response key %q is already registered by %s", info.ResponseKey, registered.Name)
+		}
+	}
+
+	exchanges.infos[info.Name] = info
+	for _, alias := range aliases {
+		exchanges.aliases[alias] = info.Name
+	}
+	exchanges.order = append(exchanges.order, info.Name)
+	return nil
+}
+
+// MustRegisterExchange is RegisterExchange for package initialisation.
+func MustRegisterExchange(info ExchangeInfo) {
+	if err := RegisterExchange(info); err != nil {
+		panic(err)
+	}
+}
+
+// LookupExchange returns the metadata of a registered venue by canonical name.
+func LookupExchange(name string) (ExchangeInfo, bool) {
+	exchanges.mu.RLock()
+	defer exchanges.mu.RUnlock()
+
+	info, ok := exchanges.infos[name]
+	if !ok {
+		return ExchangeInfo{}, false
+	}
+	info.Aliases = append([]string(nil), info.Aliases...)
+	return info, true
 }
 
 func SupportedExchangeNames() []string {
-	return append([]string(nil), supportedExchanges...)
+	exchanges.mu.RLock()
+	defer exchanges.mu.RUnlock()
+	return append([]string(nil), exchanges.order...)
 }
 
 func NormalizeExchangeName(name string) (string, error) {
 	key := strings.ToLower(strings.TrimSpace(name))
-	if normalized, ok := exchangeAliases[key]; ok {
+
+	exchanges.mu.RLock()
+	normalized, ok := exchanges.aliases[key]
+	exchanges.mu.RUnlock()
+	if ok {
 		return normalized, nil
 	}
 
-	return "", fmt.Errorf("unsupported exchange %q (supported: %s)", name, strings.Join(supportedExchanges, ", "))
+	return "", fmt.Errorf("unsupported exchange %q (supported: %s)", name, strings.Join(SupportedExchangeNames(), ", "))
 }
 
 func NormalizeExchangeNames(names []string) ([]string, error) {
@@ -57,8 +158,8 @@ func NormalizeExchangeNames(names []string) ([]string, error) {
 }
 
 func ExchangeResponseKey(name string) string {
-	if name == ExchangeOKX {
-		return "okx"
+	if info, ok := LookupExchange(name); ok {
+		return info.ResponseKey
 	}
 	return strings.ToLower(name)
 }
@@ -6,12 +6,12 @@ import (
 )
 
 func TestNormalizeExchangeNames(t *testing.T) {
-	got, err := NormalizeExchangeNames([]string{"binance", "OKX", "gate", "Binance"})
+	got, err := NormalizeExchangeNames([]string{"binance", "OKX", "gate", "Binance", "BYBIT", "huobi", "HTX", "bitget"})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 
-	want := []string{ExchangeBinance, ExchangeOKX, ExchangeGate}
+	want := []string{ExchangeBinance, ExchangeOKX, ExchangeGate, ExchangeBybit, ExchangeHTX, ExchangeBitget}
 	if !reflect.DeepEqual(got, want) {
 		t.Fatalf("expected %v, got %v", want, got)
 	}
@@ -22,3 +22,34 @@ func TestNormalizeExchangeNameRejectsUnsupportedValue(t *testing.T) {
 		t.Fatal("expected unsupported exchange error")
 	}
 }
+
+func TestRegisterExchangeAddsPrivateVenue(t *testing.T) {
+	if err := RegisterExchange(ExchangeInfo{Name: "DeskOTC", Aliases: []string{"desk"}, ResponseKey: "desk_otc"}); err != nil {
+		t.Fatalf("RegisterExchange returned error: %v", err)
+	}
+
+	for _, alias := range []string{"deskotc", "DESK"} {
+		got, err := NormalizeExchangeName(alias)
+		if err != nil || got != "DeskOTC" {
+			t.Fatalf("expected %q to normalize to DeskOTC, got %q err=%v", alias, got, err)
+		}
+	}
+	if got := ExchangeResponseKey("DeskOTC"); got != "desk_otc" {
+		t.Fatalf("expected registered response key, got %q", got)
+	}
+	names := SupportedExchangeNames()
+	if names[len(names)-1] != "DeskOTC" {
+		t.Fatalf("expected registration order to be kept, got %v", names)
+	}
+
+	for _, info := range []ExchangeInfo{
+		{Name: "DeskOTC"},
+		{Name: "Other", Aliases: []string{"gate"}},
+		{Name: "Another", ResponseKey: "okx"},
+		{Name: " "},
+	} {
+		if err := RegisterExchange(info); err == nil {
+			t.Fatalf("expected registration %#v to be rejected", info)
+		}
+	}
+}
@@ -2,10 +2,18 @@ package domain
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
 	"time"
 )
 
+// Trade directions from the user's perspective.
+const (
+	SideBuy  = "BUY"
+	SideSell = "SELL"
+)
+
 // PricePoint represents a single C2C price record
 type PricePoint struct {
 	ID              int64     `json:"id"`
@@ -23,16 +31,48 @@ type PricePoint struct {
 	MinAmount       float64   `json:"min_amount"`       // Min limit per order
 	MaxAmount       float64   `json:"max_amount"`       // Max limit per order
 	AvailableAmount float64   `json:"available_amount"` // Surplus amount
+	// PayMethodFilter is the pay method the tier was collected with; empty
+	// means the ad list was not filtered by pay method.
+	PayMethodFilter string `json:"pay_method_filter"`
+	// Reputation is the advertiser's track record when the ad was collected.
+	Reputation MerchantReputation `json:"reputation"`
+}
+
+// MerchantReputation is an advertiser's track record as reported by the venue.
+// Nil fields were not reported; they are stored as NULL and never exclude an ad.
+type MerchantReputation struct {
+	CompletionRate    *float64 `json:"completion_rate,omitempty"`     // 30-day order completion, percent 0-100
+	MonthOrders       *int     `json:"month_orders,omitempty"`        // Orders completed in the last 30 days
+	VerifiedMerchant  *bool    `json:"verified_merchant,omitempty"`   // Venue merchant badge
+	AvgReleaseSeconds *float64 `json:"avg_release_seconds,omitempty"` // Average time to release the asset
+}
+
+// RoundTripSpread is the profit per unit of buying on one exchange and selling
+// on another within the same collection round.
+type RoundTripSpread struct {
+	ID            int64     `json:"id"`
+	CreatedAt     time.Time `json:"created_at"`
+	Symbol        string    `json:"symbol"`
+	Fiat          string    `json:"fiat"`
+	TargetAmount  float64   `json:"target_amount"`
+	BuyExchange   string    `json:"buy_exchange"`  // Where the user buys (best BUY ad)
+	SellExchange  string    `json:"sell_exchange"` // Where the user sells (best SELL ad)
+	BuyPrice      float64   `json:"buy_price"`
+	SellPrice     float64   `json:"sell_price"`
+	Spread        float64   `json:"spread"`         // SellPrice - BuyPrice
+	SpreadPercent float64   `json:"spread_percent"` // Spread relative to BuyPrice
 }
 
 // Merchant represents a crypto merchant/advertiser
 type Merchant struct {
-	ID         int64     `json:"id"`
-	Exchange   string    `json:"exchange"`
-	MerchantID string    `json:"merchant_id"` // Unique ID on the exchange
-	NickName   string    `json:"nick_name"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
+	ID         int64  `json:"id"`
+	Exchange   string `json:"exchange"`
+	MerchantID string `json:"merchant_id"` // Unique ID on the exchange
+	NickName   string `json:"nick_name"`
+	// Reputation is the latest track record seen for the merchant.
+	Reputation MerchantReputation `json:"reputation"`
+	CreatedAt  time.Time          `json:"created_at"`
+	UpdatedAt  time.Time          `json:"updated_at"`
 }
 
 // ForexRate represents an exchange rate record
@@ -51,7 +91,21 @@ type PriceQueryFilter struct {
 	Fiat         string
 	Side         string
 	TargetAmount *float64
-	Rank         int
+	// PayMethodFilter selects a pay-method tier; empty selects the unfiltered tier.
+	PayMethodFilter string
+	Rank            int
+	StartTime       time.Time
+	EndTime         time.Time
+	Limit           int
+}
+
+// SpreadQueryFilter defines parameters for querying round-trip spread history
+type SpreadQueryFilter struct {
+	BuyExchange  string
+	SellExchange string
+	Symbol       string
+	Fiat         string
+	TargetAmount *float64
 	StartTime    time.Time
 	EndTime      time.Time
 	Limit        int
@@ -71,21 +125,258 @@ type ServiceStatus struct {
 	Status    string    `json:"status"` // "Pending", "OK", "Degraded", or "Error"
 	Message   string    `json:"message"`
 	LastCheck time.Time `json:"last_check"`
+	// Circuit is the collector's circuit breaker for an exchange service.
+	Circuit *CircuitStatus `json:"circuit,omitempty"`
+	// RateLimit reports the client-side request budget of an exchange.
+	RateLimit *RateLimitStatus `json:"rate_limit,omitempty"`
+}
+
+// ScheduleStatus is when the collection loops next fire; nil while nothing is
+// planned, e.g. during the first C2C run or on a replica that is not the leader.
+type ScheduleStatus struct {
+	Leader       bool       `json:"leader"` // This replica runs the collection loops
+	NextC2CRun   *time.Time `json:"next_c2c_run"`
+	NextForexRun *time.Time `json:"next_forex_run"`
+}
+
+// Digest summarizes the best BUY prices of every configured exchange and
+// amount tier over a period.
+type Digest struct {
+	GeneratedAt time.Time          `json:"generated_at"`
+	Start       time.Time          `json:"start"`
+	End         time.Time          `json:"end"`
+	Granularity HistoryGranularity `json:"granularity"` // Hourly or daily best prices
+	Tiers       []DigestTier       `json:"tiers"`
+}
+
+// DigestTier summarizes one amount tier of a market on one exchange from the
+// best price of each hourly or daily bucket. Benchmark figures are zero when
+// the market has no usable forex reference.
+type DigestTier struct {
+	Exchange     string  `json:"exchange"`
+	Market       string  `json:"market"`
+	TargetAmount float64 `json:"target_amount"`
+	Samples      int     `json:"samples"` // Buckets with a price
+	MinPrice     float64 `json:"min_price"`
+	AvgPrice     float64 `json:"avg_price"`
+	MaxPrice     float64 `json:"max_price"`
+	Benchmark    float64 `json:"benchmark"` // Current effective alert benchmark
+	// HoursBelowBenchmark counts the time of buckets whose best price was
+	// below the benchmark.
+	HoursBelowBenchmark float64          `json:"hours_below_benchmark"`
+	MaxSpreadPercent    *float64         `json:"max_spread_percent,omitempty"` // Largest (forex - price) / forex
+	TopMerchants        []DigestMerchant `json:"top_merchants"`
+}
+
+// DigestMerchant counts the buckets in which a merchant offered the best price.
+type DigestMerchant struct {
+	Merchant string `json:"merchant"`
+	Buckets  int    `json:"buckets"`
+}
+
+// CollectResult is the outcome of a collection run triggered outside the
+// schedule.
+type CollectResult struct {
+	Prices []PricePoint `json:"prices"`
+	// SkippedTargets lists the "exchange market amount" targets that were
+	// already being collected and were left to that run.
+	SkippedTargets []string                  `json:"skipped_targets,omitempty"`
+	Forex          map[string]float64        `json:"forex,omitempty"` // Latest rate per pair
+	Services       map[string]*ServiceStatus `json:"services"`
+}
+
+// RateLimitStatus is the token-bucket limiter of one exchange; the counters
+// accumulate since startup. A zero rate means requests are not limited.
+type RateLimitStatus struct {
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	Requests          int64   `json:"requests"`
+	WaitedRequests    int64   `json:"waited_requests"`
+	LastWaitSeconds   float64 `json:"last_wait_seconds"`
+	MaxWaitSeconds    float64 `json:"max_wait_seconds"`
+	TotalWaitSeconds  float64 `json:"total_wait_seconds"`
+}
+
+// Circuit breaker states.
+const (
+	CircuitClosed   = "closed"
+	CircuitOpen     = "open"
+	CircuitHalfOpen = "half_open"
+)
+
+// CircuitStatus reports whether the collector is currently calling an
+// exchange. An open circuit skips the exchange until OpenUntil, after which
+// a single half-open probe decides whether it closes again.
+type CircuitStatus struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
 }
 
 // AlertState stores dynamic alert thresholds, allowing recovery after restart.
 type AlertState struct {
-	ID           int64     `json:"id"`
-	Exchange     string    `json:"exchange"`
-	Side         string    `json:"side"`
-	TargetAmount float64   `json:"target_amount"`
-	TriggerPrice float64   `json:"trigger_price"`
-	LastAlertAt  time.Time `json:"last_alert_at"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID              int64     `json:"id"`
+	Exchange        string    `json:"exchange"`
+	Symbol          string    `json:"symbol"`
+	Fiat            string    `json:"fiat"`
+	Side            string    `json:"side"`
+	TargetAmount    float64   `json:"target_amount"`
+	PayMethodFilter string    `json:"pay_method_filter"`
+	TriggerPrice    float64   `json:"trigger_price"`
+	LastAlertAt     time.Time `json:"last_alert_at"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// Alert kinds and delivery states of an OutboxAlert.
+const (
+	AlertKindPrice     = "price"
+	AlertKindRoundTrip = "round_trip"
+	AlertKindService   = "service"
+
+	AlertPending = "pending" // Waiting for its next delivery attempt
+	AlertSent    = "sent"
+	AlertDead    = "dead" // Gave up after the configured attempts
+)
+
+// OutboxAlert is a notification queued for delivery. It is written before the
+// first attempt, so alerts survive notifier outages and restarts.
+type OutboxAlert struct {
+	ID            int64      `json:"id"`
+	CreatedAt     time.Time  `json:"created_at"`
+	Kind          string     `json:"kind"`
+	AlertKey      string     `json:"alert_key"` // The alert state, spread or service the alert is about
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	Channel       string     `json:"channel,omitempty"` // Empty for alerts queued before channels were tracked
+}
+
+// AlertEventType names what an AlertEvent reports.
+type AlertEventType string
+
+const (
+	AlertEventPriceOpportunity AlertEventType = "price_opportunity" // First BUY price below the benchmark
+	AlertEventNewLow           AlertEventType = "new_low"           // Lower than the last alerted price
+	AlertEventRoundTrip        AlertEventType = "round_trip"
+	AlertEventServiceDown      AlertEventType = "service_down"
+	AlertEventServiceRecovered AlertEventType = "service_recovered"
+	AlertEventForexStale       AlertEventType = "forex_stale" // No usable forex rate; opportunity alerts are paused
+)
+
+// AlertEvent is what an alert reports. Notification channels render it with
+// their own templates; fields that do not apply to Type are zero. Round trips
+// record the buy side in Exchange and Price.
+type AlertEvent struct {
+	Type      AlertEventType `json:"event_type"`
+	AlertType string         `json:"alert_type,omitempty"` // Initial, Lower or Wider
+	Time      time.Time      `json:"timestamp"`
+
+	Exchange        string  `json:"exchange,omitempty"`
+	SellExchange    string  `json:"sell_exchange,omitempty"`
+	Symbol          string  `json:"symbol,omitempty"`
+	Fiat            string  `json:"fiat,omitempty"`
+	Side            string  `json:"side,omitempty"`
+	TargetAmount    float64 `json:"target_amount,omitempty"`
+	PayMethodFilter string  `json:"pay_method_filter,omitempty"`
+	PayMethods      string  `json:"pay_methods,omitempty"`
+	Merchant        string  `json:"merchant,omitempty"`
+	MinAmount       float64 `json:"min_amount,omitempty"`
+	MaxAmount       float64 `json:"max_amount,omitempty"`
+	Price           float64 `json:"price,omitempty"`
+	SellPrice       float64 `json:"sell_price,omitempty"`
+	Benchmark       float64 `json:"benchmark,omitempty"` // Effective benchmark, or the round-trip threshold
+	ForexPair       string  `json:"forex_pair,omitempty"`
+	ForexRate       float64 `json:"forex_rate,omitempty"`
+	Spread          float64 `json:"spread,omitempty"`         // Round trips: fiat per unit
+	SpreadPercent   float64 `json:"spread_percent,omitempty"` // Versus forex, or versus the buy price for round trips
+
+	Service string `json:"service,omitempty"`
+	Status  string `json:"status,omitempty"` // Service status after a recovery
+	Details string `json:"details,omitempty"`
+}
+
+// Market returns the event's market key, or "" for service events.
+func (e AlertEvent) Market() string {
+	if e.Symbol == "" {
+		return ""
+	}
+	return MarketKey(e.Symbol, e.Fiat)
 }
 
-// AlertBenchmark stores the global C2C alert reference price.
+// Kind returns the outbox and history kind of the event.
+func (e AlertEvent) Kind() string {
+	switch e.Type {
+	case AlertEventPriceOpportunity, AlertEventNewLow:
+		return AlertKindPrice
+	case AlertEventRoundTrip:
+		return AlertKindRoundTrip
+	default:
+		return AlertKindService
+	}
+}
+
+// AlertOutboxFilter selects outbox alerts, newest first. An empty Status
+// selects every state.
+type AlertOutboxFilter struct {
+	Status string
+	Limit  int
+}
+
+// AlertRecord is the history entry of one alert: what fired, why, and how its
+// delivery went. Round-trip alerts record the buy side in Exchange and Price;
+// service alerts record the service name in Exchange and the error in Details.
+type AlertRecord struct {
+	ID              int64     `json:"id"`
+	CreatedAt       time.Time `json:"created_at"`
+	OutboxID        int64     `json:"outbox_id"`
+	Kind            string    `json:"kind"`
+	AlertType       string    `json:"alert_type,omitempty"` // Initial, Lower or Wider; the event type of service alerts
+	Subject         string    `json:"subject"`
+	Exchange        string    `json:"exchange"`
+	SellExchange    string    `json:"sell_exchange,omitempty"`
+	Symbol          string    `json:"symbol,omitempty"`
+	Fiat            string    `json:"fiat,omitempty"`
+	Side            string    `json:"side,omitempty"`
+	TargetAmount    float64   `json:"target_amount"`
+	PayMethodFilter string    `json:"pay_method_filter,omitempty"`
+	Merchant        string    `json:"merchant,omitempty"`
+	Price           float64   `json:"price,omitempty"`
+	SellPrice       float64   `json:"sell_price,omitempty"`
+	Benchmark       float64   `json:"benchmark,omitempty"` // Effective benchmark, or the round-trip threshold
+	ForexRate       float64   `json:"forex_rate,omitempty"`
+	SpreadPercent   float64   `json:"spread_percent,omitempty"` // Versus forex, or versus the buy price for round trips
+	Details         string    `json:"details,omitempty"`
+	Channel         string    `json:"channel"`
+
+	// Delivery result, read from the outbox.
+	DeliveryStatus string     `json:"delivery_status"`
+	Attempts       int        `json:"attempts"`
+	DeliveryError  string     `json:"delivery_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// AlertHistoryFilter selects alert records, newest first. Exchange matches
+// either side of a round trip; zero values select everything.
+type AlertHistoryFilter struct {
+	Kind         string
+	Exchange     string
+	Symbol       string
+	Fiat         string
+	TargetAmount *float64
+	StartTime    time.Time
+	EndTime      time.Time
+	Limit        int
+	Offset       int
+}
+
+// AlertBenchmark stores a market's default C2C alert reference price. Pair
+// holds the market key (see MarketKey).
 type AlertBenchmark struct {
 	Pair      string    `json:"pair"`
 	Price     float64   `json:"benchmark_price"`
@@ -102,8 +393,9 @@ type AlertBenchmarkOverride struct {
 	UpdatedAt    time.Time `json:"updated_at"`
 }
 
-// AlertBenchmarkStatus is the resolved benchmark for a global or amount-tier scope.
+// AlertBenchmarkStatus is the resolved benchmark for a market or amount-tier scope.
 type AlertBenchmarkStatus struct {
+	Market               string   `json:"market"`
 	BenchmarkPrice       float64  `json:"benchmark_price"`
 	GlobalBenchmarkPrice float64  `json:"global_benchmark_price"`
 	ForexRate            float64  `json:"forex_rate"`
@@ -111,15 +403,116 @@ type AlertBenchmarkStatus struct {
 	OverridePrice        *float64 `json:"override_price"`
 }
 
-func AlertStateKey(exchange, side string, amount float64) string {
-	return exchange + "-" + side + "-" + strconv.FormatFloat(amount, 'f', -1, 64)
+// MarketKey identifies a symbol/fiat market, e.g. "USDT/CNY".
+func MarketKey(symbol, fiat string) string {
+	return symbol + "/" + fiat
+}
+
+// ParseMarketKey splits a key produced by MarketKey.
+func ParseMarketKey(key string) (symbol, fiat string, ok bool) {
+	symbol, fiat, ok = strings.Cut(key, "/")
+	if !ok || symbol == "" || fiat == "" {
+		return "", "", false
+	}
+	return symbol, fiat, true
+}
+
+func RoundTripKey(market, sellExchange, buyExchange string, amount float64) string {
+	return sellExchange + ">" + buyExchange + "-" + market + "-" + strconv.FormatFloat(amount, 'f', -1, 64)
+}
+
+// AlertStateKey identifies an alert tier, e.g. "Gate-USDT/CNY-BUY-30". A
+// pay-method filter is appended ("Gate-USDT/CNY-BUY-30-银行卡") so filtered
+// tiers keep their own thresholds; unfiltered keys are unchanged.
+func AlertStateKey(exchange, market, side string, amount float64, payMethod string) string {
+	key := exchange + "-" + market + "-" + side + "-" + strconv.FormatFloat(amount, 'f', -1, 64)
+	if payMethod != "" {
+		key += "-" + payMethod
+	}
+	return key
+}
+
+// MaxDepth is the deepest order-book rank collected per amount tier. Every
+// adapter requests a single page of at least this many ads.
+const MaxDepth = 20
+
+// ExchangeStatusError is returned by adapters when a venue answers with a
+// non-200 HTTP status. RetryAfter is the venue's Retry-After hint (0 if none).
+type ExchangeStatusError struct {
+	Exchange   string
+	StatusCode int
+	RetryAfter time.Duration
+	Snippet    string
+}
+
+func (e *ExchangeStatusError) Error() string {
+	if e.Snippet == "" {
+		return fmt.Sprintf("%s api returned status: %d", e.Exchange, e.StatusCode)
+	}
+	return fmt.Sprintf("%s api returned status: %d: %s", e.Exchange, e.StatusCode, e.Snippet)
+}
+
+// UnsupportedQueryError is returned by adapters for a query the venue cannot
+// serve, such as a symbol, fiat, side or pay-method filter it does not list.
+// Retrying cannot help, so the monitor neither retries it nor counts it
+// against the exchange's circuit breaker.
+type UnsupportedQueryError struct {
+	Exchange string
+	Reason   string // What is unsupported, e.g. `pay method filter "QQ 钱包"`
+}
+
+func (e *UnsupportedQueryError) Error() string {
+	return fmt.Sprintf("%s does not support %s", e.Exchange, e.Reason)
 }
 
 // Interfaces define the behavior of the system's dependencies
 
 type IExchange interface {
-	// GetTopPrices returns the top N prices for a specific amount tier
-	GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64) ([]PricePoint, error)
+	// GetTopPrices returns the matching ads for a specific amount tier, best
+	// price first and ranked from 1. Callers truncate to the configured depth.
+	// A non-empty payMethod is a canonical pay-method name the venue should
+	// filter on; adapters return an error for methods they cannot filter.
+	GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]PricePoint, error)
+}
+
+// IOrderBookExchange is implemented by exchanges whose ad list does not depend
+// on the amount. The monitor fetches the book once per market, side and pay
+// method and derives every amount tier from it with TierPrices.
+type IOrderBookExchange interface {
+	IExchange
+	// GetOrderBook returns every listed ad, best price first and ranked from
+	// 1, with a zero TargetAmount and one CreatedAt for the whole book.
+	GetOrderBook(ctx context.Context, symbol, fiat, side, payMethod string) ([]PricePoint, error)
+}
+
+// IPayMethodFilterer is implemented by exchanges that know which pay-method
+// filters they can apply. The monitor skips filtered tiers an exchange cannot
+// serve instead of failing them every round.
+type IPayMethodFilterer interface {
+	// SupportsPayMethod reports whether payMethod, a canonical pay-method
+	// name, can be filtered on; an empty payMethod is always supported.
+	SupportsPayMethod(payMethod string) bool
+}
+
+// WithinAmountLimits is the amount-tier filter shared by all adapters: tier 0
+// accepts every ad, other tiers need the amount inside the ad's order limits.
+func WithinAmountLimits(targetAmount, minAmount, maxAmount float64) bool {
+	return targetAmount <= 0 || (targetAmount >= minAmount && targetAmount <= maxAmount)
+}
+
+// TierPrices returns the ads of an order book whose per-order limits accept
+// amount, tagged with the tier and ranked from 1. A zero amount keeps all ads.
+func TierPrices(book []PricePoint, amount float64) []PricePoint {
+	var prices []PricePoint
+	for _, price := range book {
+		if !WithinAmountLimits(amount, price.MinAmount, price.MaxAmount) {
+			continue
+		}
+		price.TargetAmount = amount
+		price.Rank = len(prices) + 1
+		prices = append(prices, price)
+	}
+	return prices
 }
 
 type IForex interface {
@@ -130,12 +523,44 @@ type INotifier interface {
 	Send(ctx context.Context, subject, body string) error
 }
 
+// Alert body formats. A notifier declares the format it sends through a
+// Format method; alerts are rendered as HTML for notifiers that declare none.
+const (
+	FormatHTML     = "html"
+	FormatMarkdown = "markdown"
+	FormatText     = "text"
+	FormatJSON     = "json"
+)
+
+// IMultiNotifier fans out to several channels, keyed by channel name. Alerts
+// are queued once per channel, so a failing channel is retried without
+// resending to the others.
+type IMultiNotifier interface {
+	INotifier
+	Channels() map[string]INotifier
+}
+
+// ILeaderLease is a named lease that at most one holder owns at a time, used to
+// elect the replica that collects and alerts.
+type ILeaderLease interface {
+	// AcquireLease takes or renews the lease for holder for ttl and reports
+	// whether holder owns it. Implementations shared by replicas should judge
+	// expiry by their own clock rather than now, which is the caller's.
+	AcquireLease(ctx context.Context, name, holder string, now time.Time, ttl time.Duration) (bool, error)
+	// ReleaseLease gives the lease up early if holder owns it.
+	ReleaseLease(ctx context.Context, name, holder string) error
+}
+
 type IRepository interface {
 	// Price operations
 	SavePricePoints(ctx context.Context, points []*PricePoint) error
 	GetPriceHistory(ctx context.Context, filter PriceQueryFilter) ([]*PricePoint, error)
 	GetPriceHistoryByGranularity(ctx context.Context, filter PriceQueryFilter, granularity HistoryGranularity) ([]*PricePoint, error)
 
+	// Round-trip spread operations
+	SaveRoundTripSpreads(ctx context.Context, spreads []*RoundTripSpread) error
+	GetRoundTripSpreadHistory(ctx context.Context, filter SpreadQueryFilter) ([]*RoundTripSpread, error)
+
 	// Merchant operations
 	SaveMerchant(ctx context.Context, merchant *Merchant) error
 
@@ -147,10 +572,30 @@ type IRepository interface {
 
 	// Alert state operations
 	UpsertAlertState(ctx context.Context, state *AlertState) error
-	DeleteAlertState(ctx context.Context, exchange, side string, amount float64) error
+	DeleteAlertState(ctx context.Context, exchange, symbol, fiat, side string, amount float64, payMethod string) error
 	GetAlertStates(ctx context.Context) ([]*AlertState, error)
 	UpsertAlertBenchmark(ctx context.Context, benchmark *AlertBenchmark) error
 	GetAlertBenchmark(ctx context.Context, pair string) (*AlertBenchmark, error)
 	UpsertAlertBenchmarkOverride(ctx context.Context, override *AlertBenchmarkOverride) error
 	GetAlertBenchmarkOverrides(ctx context.Context, pair string) ([]*AlertBenchmarkOverride, error)
+
+	// Alert outbox operations
+	// EnqueueAlert stores alert, its history record and state, when it is not
+	// nil, in one transaction, and sets alert.ID and record.OutboxID.
+	EnqueueAlert(ctx context.Context, alert *OutboxAlert, record *AlertRecord, state *AlertState) error
+	// GetDueAlerts returns pending alerts whose next attempt is at or before now, oldest first.
+	GetDueAlerts(ctx context.Context, now time.Time, limit int) ([]*OutboxAlert, error)
+	// ClaimAlert moves the next attempt of a pending alert that is due at now
+	// to until and reports whether it did, so that one delivery attempt at a
+	// time owns the alert.
+	ClaimAlert(ctx context.Context, id int64, now, until time.Time) (bool, error)
+	// UpdateAlertDelivery saves the delivery fields of alert.
+	UpdateAlertDelivery(ctx context.Context, alert *OutboxAlert) error
+	GetAlertOutbox(ctx context.Context, filter AlertOutboxFilter) ([]*OutboxAlert, error)
+	// RequeueAlert makes a dead alert pending again with a fresh attempt budget
+	// and reports whether one was found.
+	RequeueAlert(ctx context.Context, id int64, now time.Time) (bool, error)
+	// GetAlertHistory returns one page of alert records with their delivery
+	// results, and the number of records matching filter.
+	GetAlertHistory(ctx context.Context, filter AlertHistoryFilter) ([]*AlertRecord, int64, error)
 }
@@ -152,3 +152,20 @@ func sortedPayMethodAliases() []string {
 
 	return aliases
 }
+
+// CanonicalPayMethodName resolves an alias to its canonical name and reports
+// whether it is a known method, which is what collection filters accept.
+func CanonicalPayMethodName(raw string) (string, bool) {
+	normalized, ok := payMethodAliases[strings.ToLower(strings.TrimSpace(raw))]
+	return normalized, ok
+}
+
+// PayMethodsInclude reports whether a stored pay-method list contains method.
+func PayMethodsInclude(payMethods, method string) bool {
+	for _, candidate := range strings.Split(NormalizePayMethodsString(payMethods), ",") {
+		if strings.TrimSpace(candidate) == method {
+			return true
+		}
+	}
+	return false
+}
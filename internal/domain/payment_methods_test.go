@@ -48,3 +48,18 @@ func TestNormalizePayMethodsString(t *testing.T) {
 		})
 	}
 }
+
+func TestPayMethodsInclude(t *testing.T) {
+	if !PayMethodsInclude("Bank Transfer, Alipay", "银行卡") {
+		t.Fatal("expected bank alias to match 银行卡")
+	}
+	if PayMethodsInclude("支付宝, 微信", "银行卡") {
+		t.Fatal("did not expect 银行卡 in alipay/wechat ad")
+	}
+	if _, ok := CanonicalPayMethodName("paypal"); ok {
+		t.Fatal("expected unknown pay method to be rejected")
+	}
+	if got, ok := CanonicalPayMethodName("bank card"); !ok || got != "银行卡" {
+		t.Fatalf("CanonicalPayMethodName(bank card) = %q, %v", got, ok)
+	}
+}
@@ -14,14 +14,31 @@ import (
 
 type BinanceAdapter struct {
 	client   *http.Client
+	headers  http.Header
 	endpoint string
 }
 
 const binanceC2CEndpoint = "https://p2p.binance.com/bapi/c2c/v2/friendly/c2c/adv/search"
 
-func NewBinanceAdapter() *BinanceAdapter {
+var binanceDefaultHeaders = http.Header{
+	"Content-Type": {"application/json"},
+	"User-Agent":   {"Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"},
+	"Clienttype":   {"web"},
+	"Lang":         {"zh-CN"},
+}
+
+func init() {
+	Register(Registration{
+		Info:           domain.ExchangeInfo{Name: domain.ExchangeBinance},
+		DefaultHeaders: binanceDefaultHeaders,
+		New:            func(transport Transport) domain.IExchange { return NewBinanceAdapter(transport) },
+	})
+}
+
+func NewBinanceAdapter(transport Transport) *BinanceAdapter {
 	return &BinanceAdapter{
-		client:   &http.Client{Timeout: 10 * time.Second},
+		client:   transport.client(),
+		headers:  transport.Headers,
 		endpoint: binanceC2CEndpoint,
 	}
 }
@@ -55,19 +72,44 @@ type BinanceResponse struct {
 			} `json:"tradeMethods"`
 		} `json:"adv"`
 		Advertiser struct {
-			NickName string `json:"nickName"`
-			UserNo   string `json:"userNo"`
+			NickName        string   `json:"nickName"`
+			UserNo          string   `json:"userNo"`
+			UserType        string   `json:"userType"`
+			MonthOrderCount *int     `json:"monthOrderCount"`
+			MonthFinishRate *float64 `json:"monthFinishRate"`
+			AvgReleaseTime  *float64 `json:"avgReleaseTimeOfLatest30day"`
 		} `json:"advertiser"`
 	} `json:"data"`
 }
 
-func (a *BinanceAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64) ([]domain.PricePoint, error) {
+// binancePayTypeMap lists the payTypes identifiers of the CNY pay methods.
+var binancePayTypeMap = map[string]string{
+	"BANK":   "银行卡",
+	"WECHAT": "微信",
+	"ALIPAY": "支付宝",
+}
+
+// SupportsPayMethod implements domain.IPayMethodFilterer.
+func (a *BinanceAdapter) SupportsPayMethod(payMethod string) bool {
+	return supportsPayMethod(payMethod, binancePayTypeMap)
+}
+
+func (a *BinanceAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
 	// Map "BUY" (User buys) -> "BUY" tradeType in Binance API (Advertiser Sells? No, Binance API "BUY" means user buys)
 	// Actually check: If I want to Buy USDT, I search for Ads where tradeType="BUY".
 	// Wait, usually if I want to BUY, the advertiser is SELLING.
 	// In Binance P2P Web API:
 	// If I click "Buy", the payload sends "tradeType": "BUY".
 
+	payTypes := []string{}
+	payType, err := payMethodFilterCode("binance", payMethod, binancePayTypeMap)
+	if err != nil {
+		return nil, err
+	}
+	if payType != "" {
+		payTypes = append(payTypes, payType)
+	}
+
 	payload := BinanceRequest{
 		Asset:       symbol,
 		Fiat:        fiat,
@@ -75,8 +117,8 @@ func (a *BinanceAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side st
 		TransAmount: amount,
 		Order:       "",
 		Page:        1,
-		Rows:        10,
-		PayTypes:    []string{},
+		Rows:        domain.MaxDepth,
+		PayTypes:    payTypes,
 	}
 
 	bodyBytes, err := json.Marshal(payload)
@@ -89,10 +131,8 @@ func (a *BinanceAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side st
 		return nil, err
 	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Clienttype", "web")
-	req.Header.Set("Lang", "zh-CN") // Add language to get CN ads
+	setHeaders(req, binanceDefaultHeaders)
+	setHeaders(req, a.headers)
 
 	resp, err := a.client.Do(req)
 	if err != nil {
@@ -101,7 +141,7 @@ func (a *BinanceAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side st
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("binance api returned status: %d", resp.StatusCode)
+		return nil, exchangeStatusError("binance", resp, nil)
 	}
 
 	var data BinanceResponse
@@ -132,7 +172,7 @@ func (a *BinanceAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side st
 		if err != nil || availableAmount < 0 {
 			continue
 		}
-		if amount > 0 && (amount < minAmount || amount > maxAmount) {
+		if !domain.WithinAmountLimits(amount, minAmount, maxAmount) {
 			continue
 		}
 
@@ -157,6 +197,7 @@ func (a *BinanceAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side st
 			MaxAmount:       maxAmount,
 			AvailableAmount: availableAmount,
 			PayMethods:      payMethodsStr,
+			Reputation:      binanceReputation(item.Advertiser.UserType, item.Advertiser.MonthOrderCount, item.Advertiser.MonthFinishRate, item.Advertiser.AvgReleaseTime),
 		})
 	}
 
@@ -176,10 +217,21 @@ func (a *BinanceAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side st
 		points[i].Rank = i + 1
 	}
 
-	// Return top 1 as per default
-	if len(points) > 1 {
-		points = points[:1]
-	}
-
 	return points, nil
 }
+
+// binanceReputation maps the advertiser stats; Binance reports the finish rate
+// as a fraction and tags merchants with userType "merchant".
+func binanceReputation(userType string, monthOrders *int, finishRate, avgReleaseSeconds *float64) domain.MerchantReputation {
+	reputation := domain.MerchantReputation{
+		MonthOrders:       monthOrders,
+		AvgReleaseSeconds: avgReleaseSeconds,
+	}
+	if finishRate != nil {
+		reputation.CompletionRate = completionPercent(*finishRate)
+	}
+	if userType != "" {
+		reputation.VerifiedMerchant = reportedBadge(userType == "merchant")
+	}
+	return reputation
+}
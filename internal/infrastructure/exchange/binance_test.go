@@ -2,11 +2,15 @@ package exchange
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
+
+	"c2c_monitor/internal/domain"
 )
 
 func TestBinanceAdapterParsesAndSortsPrices(t *testing.T) {
@@ -26,7 +30,7 @@ func TestBinanceAdapterParsesAndSortsPrices(t *testing.T) {
 						"maxSingleTransAmount": "5000",
 						"tradeMethods": [{"tradeMethodName": "Bank Transfer"}]
 					},
-					"advertiser": {"nickName": "Merchant A", "userNo": "a"}
+					"advertiser": {"nickName": "Merchant A", "userNo": "a", "userType": "merchant", "monthOrderCount": 812, "monthFinishRate": 0.987}
 				},
 				{
 					"adv": {
@@ -47,16 +51,82 @@ func TestBinanceAdapterParsesAndSortsPrices(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500)
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "")
 	if err != nil {
 		t.Fatalf("GetTopPrices returned error: %v", err)
 	}
-	if len(points) != 1 || points[0].Price != 7.08 || points[0].Merchant != "Merchant B" {
+	if len(points) != 2 || points[0].Price != 7.08 || points[0].Merchant != "Merchant B" {
 		t.Fatalf("unexpected points: %#v", points)
 	}
+	if points[0].Rank != 1 || points[1].Rank != 2 || points[1].Merchant != "Merchant A" {
+		t.Fatalf("expected full depth ranked by price, got %#v", points)
+	}
 	if points[0].PayMethods != "支付宝" {
 		t.Fatalf("expected normalized pay method, got %q", points[0].PayMethods)
 	}
+	reputation := points[1].Reputation
+	if reputation.CompletionRate == nil || *reputation.CompletionRate < 98.69 || *reputation.CompletionRate > 98.71 {
+		t.Fatalf("expected completion rate as a percentage, got %#v", reputation)
+	}
+	if reputation.MonthOrders == nil || *reputation.MonthOrders != 812 || reputation.VerifiedMerchant == nil || !*reputation.VerifiedMerchant {
+		t.Fatalf("expected advertiser stats, got %#v", reputation)
+	}
+	if points[0].Reputation != (domain.MerchantReputation{}) {
+		t.Fatalf("expected unreported stats to stay nil, got %#v", points[0].Reputation)
+	}
+}
+
+func TestBinanceAdapterRequestsFullDepthPage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload BinanceRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if payload.Rows != domain.MaxDepth {
+			t.Fatalf("expected rows=%d, got %d", domain.MaxDepth, payload.Rows)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":"000000","data":[]}`))
+	}))
+	defer server.Close()
+
+	adapter := &BinanceAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, ""); err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+}
+
+func TestBinanceAdapterSendsPayMethodFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload BinanceRequest
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if len(payload.PayTypes) != 1 || payload.PayTypes[0] != "BANK" {
+			t.Fatalf("expected payTypes=[BANK], got %v", payload.PayTypes)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":"000000","data":[]}`))
+	}))
+	defer server.Close()
+
+	adapter := &BinanceAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "银行卡"); err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+	if !adapter.SupportsPayMethod("") || !adapter.SupportsPayMethod("银行卡") || adapter.SupportsPayMethod("QQ 钱包") {
+		t.Fatal("expected SupportsPayMethod to follow the pay type map")
+	}
+	var unsupported *domain.UnsupportedQueryError
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "QQ 钱包"); !errors.As(err, &unsupported) {
+		t.Fatalf("expected unsupported pay method filter error, got %v", err)
+	}
 }
 
 func TestBinanceAdapterReturnsAPIError(t *testing.T) {
@@ -70,7 +140,7 @@ func TestBinanceAdapterReturnsAPIError(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500)
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "")
 	if err == nil || !strings.Contains(err.Error(), "rate limited") {
 		t.Fatalf("expected Binance API error, got %v", err)
 	}
@@ -86,7 +156,7 @@ func TestBinanceAdapterRejectsMalformedResponse(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500); err == nil {
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, ""); err == nil {
 		t.Fatal("expected malformed response error")
 	}
 }
@@ -126,7 +196,7 @@ func TestBinanceAdapterSkipsNonFiniteCandidate(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500)
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "")
 	if err != nil {
 		t.Fatalf("GetTopPrices returned error: %v", err)
 	}
@@ -170,7 +240,7 @@ func TestBinanceAdapterFindsLaterAmountMatch(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500)
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "")
 	if err != nil {
 		t.Fatalf("GetTopPrices returned error: %v", err)
 	}
@@ -0,0 +1,243 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+const bitgetOTCEndpoint = "https://www.bitget.com/v1/p2p/pub/adv/queryAdvList"
+
+type BitgetAdapter struct {
+	client   *http.Client
+	headers  http.Header
+	endpoint string
+}
+
+var bitgetDefaultHeaders = http.Header{
+	"Accept":          {"application/json, text/plain, */*"},
+	"Content-Type":    {"application/json;charset=UTF-8"},
+	"Origin":          {"https://www.bitget.com"},
+	"Referer":         {"https://www.bitget.com/zh-CN/p2p-trade"},
+	"User-Agent":      {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/146.0.0.0 Safari/537.36"},
+	"Accept-Language": {"zh-CN,zh;q=0.9"},
+}
+
+func init() {
+	Register(Registration{
+		Info:           domain.ExchangeInfo{Name: domain.ExchangeBitget},
+		DefaultHeaders: bitgetDefaultHeaders,
+		New:            func(transport Transport) domain.IExchange { return NewBitgetAdapter(transport) },
+	})
+}
+
+func NewBitgetAdapter(transport Transport) *BitgetAdapter {
+	return &BitgetAdapter{
+		client:   transport.client(),
+		headers:  transport.Headers,
+		endpoint: bitgetOTCEndpoint,
+	}
+}
+
+type BitgetRequest struct {
+	Side         int    `json:"side"`
+	PageNo       int    `json:"pageNo"`
+	PageSize     int    `json:"pageSize"`
+	CoinCode     string `json:"coinCode"`
+	FiatCode     string `json:"fiatCode"`
+	LanguageType int    `json:"languageType"`
+	PayMethodID  string `json:"paymethodId"`
+	Amount       string `json:"amount"`
+}
+
+type BitgetResponse struct {
+	Code string `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		DataList []BitgetAd `json:"dataList"`
+	} `json:"data"`
+}
+
+type BitgetAd struct {
+	AdNo              string              `json:"adNo"`
+	UserID            string              `json:"userId"`
+	NickName          string              `json:"nickName"`
+	Price             string              `json:"price"`
+	LastAmount        string              `json:"lastAmount"`
+	MinAmount         string              `json:"minAmount"`
+	MaxAmount         string              `json:"maxAmount"`
+	PaymentMethodList []BitgetPaymentInfo `json:"paymentMethodList"`
+}
+
+type BitgetPaymentInfo struct {
+	PayMethodID   string `json:"paymethodId"`
+	PayMethodName string `json:"paymethodName"`
+}
+
+const bitgetSuccessCode = "00000"
+
+var bitgetCNYPayMethodCodeMap = map[string]string{
+	"1":  "银行卡",
+	"2":  "支付宝",
+	"3":  "微信",
+	"41": "QQ 钱包",
+}
+
+// SupportsPayMethod implements domain.IPayMethodFilterer.
+func (a *BitgetAdapter) SupportsPayMethod(payMethod string) bool {
+	return supportsPayMethod(payMethod, bitgetCNYPayMethodCodeMap)
+}
+
+func (a *BitgetAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	bitgetSide, err := bitgetSide(side)
+	if err != nil {
+		return nil, err
+	}
+
+	payMethodID, err := payMethodFilterCode("bitget", payMethod, bitgetCNYPayMethodCodeMap)
+	if err != nil {
+		return nil, err
+	}
+
+	request := BitgetRequest{
+		Side:        bitgetSide,
+		PageNo:      1,
+		PageSize:    domain.MaxDepth,
+		CoinCode:    symbol,
+		FiatCode:    fiat,
+		PayMethodID: payMethodID,
+	}
+	if amount > 0 {
+		request.Amount = strconv.FormatFloat(amount, 'f', -1, 64)
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	setHeaders(req, bitgetDefaultHeaders)
+	setHeaders(req, a.headers)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	payload, err := readExchangeResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, exchangeStatusError("bitget", resp, payload)
+	}
+
+	var data BitgetResponse
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Code != bitgetSuccessCode {
+		return nil, fmt.Errorf("bitget api error: %s - %s", data.Code, data.Msg)
+	}
+
+	now := time.Now()
+	points := make([]domain.PricePoint, 0, len(data.Data.DataList))
+	for _, ad := range data.Data.DataList {
+		point, ok := bitgetAdToPoint(ad, symbol, fiat, side, amount, now)
+		if ok {
+			points = append(points, point)
+		}
+	}
+
+	if side == "BUY" {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Price < points[j].Price
+		})
+	} else {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Price > points[j].Price
+		})
+	}
+
+	for i := range points {
+		points[i].Rank = i + 1
+	}
+
+	return points, nil
+}
+
+// bitgetSide maps the user side to Bitget's list side: 1 lists sell ads the
+// user buys from, 2 lists buy ads the user sells to.
+func bitgetSide(side string) (int, error) {
+	switch side {
+	case "BUY":
+		return 1, nil
+	case "SELL":
+		return 2, nil
+	default:
+		return 0, &domain.UnsupportedQueryError{Exchange: "bitget", Reason: "side " + side}
+	}
+}
+
+func bitgetAdToPoint(ad BitgetAd, symbol, fiat, side string, targetAmount float64, now time.Time) (domain.PricePoint, bool) {
+	price, err := parseFiniteFloat(ad.Price)
+	if err != nil || price <= 0 {
+		return domain.PricePoint{}, false
+	}
+
+	minAmount, maxAmount, err := parseLimitRange(ad.MinAmount, ad.MaxAmount)
+	if err != nil {
+		return domain.PricePoint{}, false
+	}
+
+	if !domain.WithinAmountLimits(targetAmount, minAmount, maxAmount) {
+		return domain.PricePoint{}, false
+	}
+
+	availableAmount, err := parseFiniteFloat(ad.LastAmount)
+	if err != nil || availableAmount < 0 {
+		return domain.PricePoint{}, false
+	}
+
+	merchantID := strings.TrimSpace(ad.UserID)
+	merchant := strings.TrimSpace(ad.NickName)
+	if merchant == "" {
+		merchant = merchantID
+	}
+
+	codes := make([]string, 0, len(ad.PaymentMethodList))
+	for _, method := range ad.PaymentMethodList {
+		codes = append(codes, method.PayMethodID)
+	}
+
+	return domain.PricePoint{
+		Exchange:        domain.ExchangeBitget,
+		Symbol:          symbol,
+		Fiat:            fiat,
+		Side:            side,
+		TargetAmount:    targetAmount,
+		Price:           price,
+		Merchant:        merchant,
+		MerchantID:      merchantID,
+		CreatedAt:       now,
+		MinAmount:       minAmount,
+		MaxAmount:       maxAmount,
+		AvailableAmount: availableAmount,
+		PayMethods:      normalizeCodedPayMethods(codes, bitgetCNYPayMethodCodeMap),
+	}, true
+}
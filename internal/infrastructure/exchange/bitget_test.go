@@ -0,0 +1,118 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+func TestBitgetAdapterGetTopPricesFiltersByAmount(t *testing.T) {
+	fixture := readFixture(t, "bitget_adv_list_buy_usdt_cny.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("expected POST request, got %s", r.Method)
+		}
+
+		var request BitgetRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if request.CoinCode != "USDT" || request.FiatCode != "CNY" {
+			t.Fatalf("expected USDT/CNY request, got %s/%s", request.CoinCode, request.FiatCode)
+		}
+		if request.Side != 1 {
+			t.Fatalf("expected side=1 for user BUY, got %d", request.Side)
+		}
+		if request.Amount != "100" {
+			t.Fatalf("expected amount=100, got %q", request.Amount)
+		}
+		if request.PageSize != domain.MaxDepth {
+			t.Fatalf("expected pageSize=%d, got %d", domain.MaxDepth, request.PageSize)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	adapter := &BitgetAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	if err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+
+	if len(points) != 1 {
+		t.Fatalf("expected 1 price point, got %d", len(points))
+	}
+
+	point := points[0]
+	if point.Exchange != "Bitget" {
+		t.Fatalf("expected exchange Bitget, got %q", point.Exchange)
+	}
+	if point.Merchant != "Matched Merchant" || point.MerchantID != "8812007734" {
+		t.Fatalf("unexpected merchant %q (%q)", point.Merchant, point.MerchantID)
+	}
+	if point.PayMethods != "支付宝, QQ 钱包" {
+		t.Fatalf("expected pay methods 支付宝, QQ 钱包, got %q", point.PayMethods)
+	}
+	assertCloseFloat(t, point.Price, 6.98)
+	assertCloseFloat(t, point.MinAmount, 100)
+	assertCloseFloat(t, point.MaxAmount, 2879.25)
+	assertCloseFloat(t, point.AvailableAmount, 412.5)
+}
+
+func TestBitgetAdapterSellSideRanksHighestFirst(t *testing.T) {
+	fixture := readFixture(t, "bitget_adv_list_buy_usdt_cny.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request BitgetRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if request.Side != 2 {
+			t.Fatalf("expected side=2 for user SELL, got %d", request.Side)
+		}
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	adapter := &BitgetAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "SELL", 0, "")
+	if err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+
+	if len(points) != 3 || points[0].Merchant != "Too High" || points[2].Rank != 3 {
+		t.Fatalf("expected descending SELL ranking, got %#v", points)
+	}
+}
+
+func TestBitgetAdapterReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code": "40034", "msg": "Parameter does not exist", "data": null}`))
+	}))
+	defer server.Close()
+
+	adapter := &BitgetAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	if err == nil || !strings.Contains(err.Error(), "40034") {
+		t.Fatalf("expected Bitget API error, got %v", err)
+	}
+}
@@ -0,0 +1,264 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+const bybitC2CEndpoint = "https://api2.bybit.com/fiat/otc/item/online"
+
+type BybitAdapter struct {
+	client   *http.Client
+	headers  http.Header
+	endpoint string
+}
+
+var bybitDefaultHeaders = http.Header{
+	"Accept":          {"application/json"},
+	"Content-Type":    {"application/json"},
+	"Origin":          {"https://www.bybit.com"},
+	"Referer":         {"https://www.bybit.com/fiat/trade/otc"},
+	"User-Agent":      {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/146.0.0.0 Safari/537.36"},
+	"Accept-Language": {"zh-CN,zh;q=0.9"},
+}
+
+func init() {
+	Register(Registration{
+		Info:           domain.ExchangeInfo{Name: domain.ExchangeBybit},
+		DefaultHeaders: bybitDefaultHeaders,
+		New:            func(transport Transport) domain.IExchange { return NewBybitAdapter(transport) },
+	})
+}
+
+func NewBybitAdapter(transport Transport) *BybitAdapter {
+	return &BybitAdapter{
+		client:   transport.client(),
+		headers:  transport.Headers,
+		endpoint: bybitC2CEndpoint,
+	}
+}
+
+type BybitRequest struct {
+	UserID     string   `json:"userId"`
+	TokenID    string   `json:"tokenId"`
+	CurrencyID string   `json:"currencyId"`
+	Payment    []string `json:"payment"`
+	Side       string   `json:"side"`
+	Size       string   `json:"size"`
+	Page       string   `json:"page"`
+	Amount     string   `json:"amount"`
+	AuthMaker  bool     `json:"authMaker"`
+	CanTrade   bool     `json:"canTrade"`
+}
+
+type BybitResponse struct {
+	RetCode int    `json:"ret_code"`
+	RetMsg  string `json:"ret_msg"`
+	Result  struct {
+		Count int       `json:"count"`
+		Items []BybitAd `json:"items"`
+	} `json:"result"`
+}
+
+type BybitAd struct {
+	ID           string   `json:"id"`
+	UserID       string   `json:"userId"`
+	AccountID    string   `json:"accountId"`
+	NickName     string   `json:"nickName"`
+	Price        string   `json:"price"`
+	LastQuantity string   `json:"lastQuantity"`
+	MinAmount    string   `json:"minAmount"`
+	MaxAmount    string   `json:"maxAmount"`
+	Payments     []string `json:"payments"`
+}
+
+// bybitPayMethodIDMap translates the payment type ids seen on CNY ads into
+// names understood by domain.NormalizePayMethodName.
+var bybitPayMethodIDMap = map[string]string{
+	"14":  "Bank Transfer",
+	"62":  "WeChat",
+	"75":  "Alipay",
+	"382": "QQ Wallet",
+}
+
+// SupportsPayMethod implements domain.IPayMethodFilterer.
+func (a *BybitAdapter) SupportsPayMethod(payMethod string) bool {
+	return supportsPayMethod(payMethod, bybitPayMethodIDMap)
+}
+
+func (a *BybitAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	bybitSide, err := bybitSide(side)
+	if err != nil {
+		return nil, err
+	}
+
+	paymentID, err := payMethodFilterCode("bybit", payMethod, bybitPayMethodIDMap)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(buildBybitRequest(symbol, fiat, amount, bybitSide, paymentID))
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	setHeaders(req, bybitDefaultHeaders)
+	setHeaders(req, a.headers)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	payload, err := readExchangeResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, exchangeStatusError("bybit", resp, payload)
+	}
+
+	var data BybitResponse
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	if data.RetCode != 0 {
+		return nil, fmt.Errorf("bybit api error: %d - %s", data.RetCode, data.RetMsg)
+	}
+
+	now := time.Now()
+	points := make([]domain.PricePoint, 0, len(data.Result.Items))
+	for _, ad := range data.Result.Items {
+		point, ok := bybitAdToPoint(ad, symbol, fiat, side, amount, now)
+		if ok {
+			points = append(points, point)
+		}
+	}
+
+	if side == "BUY" {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Price < points[j].Price
+		})
+	} else {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Price > points[j].Price
+		})
+	}
+
+	for i := range points {
+		points[i].Rank = i + 1
+	}
+
+	return points, nil
+}
+
+// bybitSide maps the user side to Bybit's list side: "1" lists sell ads the
+// user buys from, "0" lists buy ads the user sells to.
+func bybitSide(side string) (string, error) {
+	switch side {
+	case "BUY":
+		return "1", nil
+	case "SELL":
+		return "0", nil
+	default:
+		return "", &domain.UnsupportedQueryError{Exchange: "bybit", Reason: "side " + side}
+	}
+}
+
+func buildBybitRequest(symbol, fiat string, amount float64, bybitSide, paymentID string) BybitRequest {
+	request := BybitRequest{
+		TokenID:    symbol,
+		CurrencyID: fiat,
+		Payment:    []string{},
+		Side:       bybitSide,
+		Size:       strconv.Itoa(domain.MaxDepth),
+		Page:       "1",
+	}
+	if amount > 0 {
+		request.Amount = strconv.FormatFloat(amount, 'f', -1, 64)
+	}
+	if paymentID != "" {
+		request.Payment = []string{paymentID}
+	}
+	return request
+}
+
+func bybitAdToPoint(ad BybitAd, symbol, fiat, side string, targetAmount float64, now time.Time) (domain.PricePoint, bool) {
+	price, err := parseFiniteFloat(ad.Price)
+	if err != nil || price <= 0 {
+		return domain.PricePoint{}, false
+	}
+
+	minAmount, err := parseFiniteFloat(ad.MinAmount)
+	if err != nil {
+		return domain.PricePoint{}, false
+	}
+	maxAmount, err := parseFiniteFloat(ad.MaxAmount)
+	if err != nil || minAmount < 0 || maxAmount < minAmount {
+		return domain.PricePoint{}, false
+	}
+
+	if !domain.WithinAmountLimits(targetAmount, minAmount, maxAmount) {
+		return domain.PricePoint{}, false
+	}
+
+	availableAmount, err := parseFiniteFloat(ad.LastQuantity)
+	if err != nil || availableAmount < 0 {
+		return domain.PricePoint{}, false
+	}
+
+	merchant := strings.TrimSpace(ad.NickName)
+	merchantID := strings.TrimSpace(ad.UserID)
+	if merchantID == "" {
+		merchantID = strings.TrimSpace(ad.AccountID)
+	}
+	if merchant == "" {
+		merchant = merchantID
+	}
+
+	return domain.PricePoint{
+		Exchange:        domain.ExchangeBybit,
+		Symbol:          symbol,
+		Fiat:            fiat,
+		Side:            side,
+		TargetAmount:    targetAmount,
+		Price:           price,
+		Merchant:        merchant,
+		MerchantID:      merchantID,
+		CreatedAt:       now,
+		MinAmount:       minAmount,
+		MaxAmount:       maxAmount,
+		AvailableAmount: availableAmount,
+		PayMethods:      normalizeBybitPayMethods(ad.Payments),
+	}, true
+}
+
+func normalizeBybitPayMethods(ids []string) string {
+	methods := make([]string, 0, len(ids))
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if name, ok := bybitPayMethodIDMap[id]; ok {
+			methods = append(methods, name)
+			continue
+		}
+		methods = append(methods, id)
+	}
+	return domain.JoinNormalizedPayMethods(methods)
+}
@@ -0,0 +1,196 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+func TestBybitAdapterGetTopPricesFiltersByAmount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("expected POST request, got %s", r.Method)
+		}
+
+		var request BybitRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if request.TokenID != "USDT" || request.CurrencyID != "CNY" {
+			t.Fatalf("expected USDT/CNY request, got %s/%s", request.TokenID, request.CurrencyID)
+		}
+		if request.Side != "1" {
+			t.Fatalf("expected side=1 for user BUY, got %q", request.Side)
+		}
+		if request.Amount != "100" {
+			t.Fatalf("expected amount=100, got %q", request.Amount)
+		}
+		if request.Size != strconv.Itoa(domain.MaxDepth) {
+			t.Fatalf("expected size=%d, got %q", domain.MaxDepth, request.Size)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{
+			"ret_code": 0,
+			"ret_msg": "SUCCESS",
+			"result": {
+				"count": 3,
+				"items": [
+					{
+						"id": "ad-1",
+						"userId": "uid-too-high",
+						"nickName": "Too High",
+						"price": "7.01",
+						"lastQuantity": "500.00",
+						"minAmount": "500.00",
+						"maxAmount": "3500.00",
+						"payments": ["14"]
+					},
+					{
+						"id": "ad-2",
+						"userId": "uid-match",
+						"nickName": "Matched Merchant",
+						"price": "6.98",
+						"lastQuantity": "331.96",
+						"minAmount": "50.00",
+						"maxAmount": "2300.00",
+						"payments": ["75", "62", "75"]
+					},
+					{
+						"id": "ad-3",
+						"userId": "uid-too-low",
+						"nickName": "Too Low",
+						"price": "6.97",
+						"lastQuantity": "1000.00",
+						"minAmount": "10.00",
+						"maxAmount": "50.00",
+						"payments": ["14"]
+					}
+				]
+			}
+		}`)
+	}))
+	defer server.Close()
+
+	adapter := &BybitAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	if err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+
+	if len(points) != 1 {
+		t.Fatalf("expected 1 price point, got %d", len(points))
+	}
+
+	point := points[0]
+	if point.Exchange != "Bybit" {
+		t.Fatalf("expected exchange Bybit, got %q", point.Exchange)
+	}
+	if point.Merchant != "Matched Merchant" || point.MerchantID != "uid-match" {
+		t.Fatalf("unexpected merchant %q (%q)", point.Merchant, point.MerchantID)
+	}
+	if point.PayMethods != "支付宝, 微信" {
+		t.Fatalf("expected pay methods 支付宝, 微信, got %q", point.PayMethods)
+	}
+	if point.Rank != 1 {
+		t.Fatalf("expected rank 1, got %d", point.Rank)
+	}
+	assertCloseFloat(t, point.Price, 6.98)
+	assertCloseFloat(t, point.MinAmount, 50)
+	assertCloseFloat(t, point.MaxAmount, 2300)
+	assertCloseFloat(t, point.AvailableAmount, 331.96)
+}
+
+func TestBybitAdapterSellSideRanksHighestFirst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request BybitRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Fatalf("failed to decode request body: %v", err)
+		}
+		if request.Side != "0" {
+			t.Fatalf("expected side=0 for user SELL, got %q", request.Side)
+		}
+		if request.Amount != "" {
+			t.Fatalf("expected empty amount for the lowest tier, got %q", request.Amount)
+		}
+
+		_, _ = io.WriteString(w, `{
+			"ret_code": 0,
+			"ret_msg": "SUCCESS",
+			"result": {
+				"items": [
+					{"userId": "uid-1", "nickName": "Merchant 1", "price": "7.10", "lastQuantity": "100", "minAmount": "100", "maxAmount": "1000", "payments": ["999"]},
+					{"userId": "uid-2", "nickName": "Merchant 2", "price": "7.15", "lastQuantity": "100", "minAmount": "100", "maxAmount": "1000", "payments": []},
+					{"userId": "uid-3", "nickName": "Broken", "price": "NaN", "lastQuantity": "100", "minAmount": "100", "maxAmount": "1000"}
+				]
+			}
+		}`)
+	}))
+	defer server.Close()
+
+	adapter := &BybitAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "SELL", 0, "")
+	if err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+
+	if len(points) != 2 {
+		t.Fatalf("expected 2 price points, got %d", len(points))
+	}
+	if points[0].Merchant != "Merchant 2" || points[1].Rank != 2 {
+		t.Fatalf("expected highest SELL price first, got %#v", points)
+	}
+	if points[1].PayMethods != "999" {
+		t.Fatalf("expected unknown payment id to be kept, got %q", points[1].PayMethods)
+	}
+}
+
+func TestBybitAdapterReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"ret_code": 10001, "ret_msg": "params error", "result": null}`)
+	}))
+	defer server.Close()
+
+	adapter := &BybitAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	if err == nil || !strings.Contains(err.Error(), "params error") {
+		t.Fatalf("expected Bybit API error, got %v", err)
+	}
+}
+
+func TestBybitAdapterReturnsHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "upstream unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	adapter := &BybitAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	if err == nil || !strings.Contains(err.Error(), "503") {
+		t.Fatalf("expected Bybit HTTP error, got %v", err)
+	}
+}
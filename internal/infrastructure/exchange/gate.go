@@ -18,12 +18,34 @@ const gateC2CEndpoint = "https://www.gate.com/api/web/v1/c2c/advertisements"
 
 type GateAdapter struct {
 	client   *http.Client
+	headers  http.Header
 	endpoint string
 }
 
-func NewGateAdapter() *GateAdapter {
+var gateDefaultHeaders = http.Header{
+	"Accept":          {"application/json, text/plain, */*"},
+	"Content-Type":    {"application/x-www-form-urlencoded"},
+	"Origin":          {"https://www.gate.com"},
+	"Referer":         {"https://www.gate.com/zh/p2p"},
+	"User-Agent":      {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/146.0.0.0 Safari/537.36"},
+	"Accept-Language": {"zh-CN,zh;q=0.9"},
+	"X-Page-Host":     {"www.gate.com"},
+	"Csrftoken":       {"1"},
+	"Sub_website_id":  {""},
+}
+
+func init() {
+	Register(Registration{
+		Info:           domain.ExchangeInfo{Name: domain.ExchangeGate},
+		DefaultHeaders: gateDefaultHeaders,
+		New:            func(transport Transport) domain.IExchange { return NewGateAdapter(transport) },
+	})
+}
+
+func NewGateAdapter(transport Transport) *GateAdapter {
 	return &GateAdapter{
-		client:   &http.Client{Timeout: 10 * time.Second},
+		client:   transport.client(),
+		headers:  transport.Headers,
 		endpoint: gateC2CEndpoint,
 	}
 }
@@ -50,6 +72,10 @@ type GateAd struct {
 	Username    string `json:"username"`
 	Nick        string `json:"nick"`
 	HidePayment string `json:"hide_payment"`
+
+	CompleteRateMonth string `json:"complete_rate_month"`
+	CompleteNumber    string `json:"complete_number"`
+	IsBlue            string `json:"is_blue"`
 }
 
 var gateCNYPayMethodCodeMap = map[string]string{
@@ -59,28 +85,31 @@ var gateCNYPayMethodCodeMap = map[string]string{
 	"339": "QQ 钱包",
 }
 
-func (a *GateAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64) ([]domain.PricePoint, error) {
+// SupportsPayMethod implements domain.IPayMethodFilterer.
+func (a *GateAdapter) SupportsPayMethod(payMethod string) bool {
+	return supportsPayMethod(payMethod, gateCNYPayMethodCodeMap)
+}
+
+func (a *GateAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
 	pushType, err := gatePushType(side)
 	if err != nil {
 		return nil, err
 	}
 
-	form := buildGateForm(symbol, fiat, amount, pushType)
+	payType, err := payMethodFilterCode("gate", payMethod, gateCNYPayMethodCodeMap)
+	if err != nil {
+		return nil, err
+	}
+
+	form := buildGateForm(symbol, fiat, amount, pushType, payType)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.endpoint, strings.NewReader(form.Encode()))
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Accept", "application/json, text/plain, */*")
-	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
-	req.Header.Set("Origin", "https://www.gate.com")
-	req.Header.Set("Referer", "https://www.gate.com/zh/p2p")
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/146.0.0.0 Safari/537.36")
-	req.Header.Set("Accept-Language", "zh-CN,zh;q=0.9")
-	req.Header.Set("X-Page-Host", "www.gate.com")
-	req.Header.Set("csrftoken", "1")
-	req.Header.Set("sub_website_id", "")
+	setHeaders(req, gateDefaultHeaders)
 	req.Header.Set("Cookie", fmt.Sprintf("lang=cn; seo_lang=%%2Fzh; lasturl=%%2Fp2p; defaultP2PFiat=%s", fiat))
+	setHeaders(req, a.headers)
 
 	resp, err := a.client.Do(req)
 	if err != nil {
@@ -94,7 +123,7 @@ func (a *GateAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side strin
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("gate api returned status: %d: %s", resp.StatusCode, gateResponseSnippet(payload))
+		return nil, exchangeStatusError("gate", resp, payload)
 	}
 
 	var data GateResponse
@@ -129,10 +158,6 @@ func (a *GateAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side strin
 		points[i].Rank = i + 1
 	}
 
-	if len(points) > 1 {
-		points = points[:1]
-	}
-
 	return points, nil
 }
 
@@ -143,17 +168,17 @@ func gatePushType(side string) (string, error) {
 	case "SELL":
 		return "buy", nil
 	default:
-		return "", fmt.Errorf("invalid side: %s", side)
+		return "", &domain.UnsupportedQueryError{Exchange: "gate", Reason: "side " + side}
 	}
 }
 
-func buildGateForm(symbol, fiat string, amount float64, pushType string) url.Values {
+func buildGateForm(symbol, fiat string, amount float64, pushType, payType string) url.Values {
 	form := url.Values{}
 	form.Set("type", "push_order_list")
 	form.Set("asset_pair", fmt.Sprintf("%s_%s", symbol, fiat))
 	form.Set("big_trade", "0")
 	form.Set("amount", "")
-	form.Set("pay_type", "")
+	form.Set("pay_type", payType)
 	form.Set("is_blue", "0")
 	form.Set("is_crown", "0")
 	form.Set("is_shield", "0")
@@ -188,7 +213,7 @@ func gateAdToPoint(ad GateAd, symbol, fiat, side string, targetAmount float64, n
 		return domain.PricePoint{}, false
 	}
 
-	if targetAmount > 0 && (targetAmount < minFiat || targetAmount > maxFiat) {
+	if !domain.WithinAmountLimits(targetAmount, minFiat, maxFiat) {
 		return domain.PricePoint{}, false
 	}
 
@@ -224,9 +249,23 @@ func gateAdToPoint(ad GateAd, symbol, fiat, side string, targetAmount float64, n
 		MaxAmount:       maxFiat,
 		AvailableAmount: availableAmount,
 		PayMethods:      normalizeGatePayMethods(ad.PayTypeNum),
+		Reputation:      gateReputation(ad),
 	}, true
 }
 
+// gateReputation maps the ad's merchant stats; is_blue is Gate's verified
+// merchant badge.
+func gateReputation(ad GateAd) domain.MerchantReputation {
+	reputation := domain.MerchantReputation{
+		CompletionRate: parseCompletionPercent(ad.CompleteRateMonth),
+		MonthOrders:    parseOrderCount(ad.CompleteNumber),
+	}
+	if badge := strings.TrimSpace(ad.IsBlue); badge != "" {
+		reputation.VerifiedMerchant = reportedBadge(badge == "1")
+	}
+	return reputation
+}
+
 func gateFiatRange(ad GateAd, price float64) (float64, float64, error) {
 	if strings.TrimSpace(ad.LimitFiat) != "" {
 		return parseGateLimitFiat(ad.LimitFiat)
@@ -310,16 +349,3 @@ func normalizeGatePayMethods(payTypeNum string) string {
 
 	return domain.JoinNormalizedPayMethods(methods)
 }
-
-func gateResponseSnippet(payload []byte) string {
-	snippet := strings.TrimSpace(string(payload))
-	snippet = strings.ReplaceAll(snippet, "\n", " ")
-	snippet = strings.ReplaceAll(snippet, "\t", " ")
-	if len(snippet) > 180 {
-		return snippet[:180] + "..."
-	}
-	if snippet == "" {
-		return "empty response"
-	}
-	return snippet
-}
@@ -2,6 +2,7 @@ package exchange
 
 import (
 	"context"
+	"errors"
 	"io"
 	"math"
 	"net/http"
@@ -10,6 +11,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"c2c_monitor/internal/domain"
 )
 
 func TestGateAdapterGetTopPricesFiltersByAmount(t *testing.T) {
@@ -99,7 +102,7 @@ func TestGateAdapterGetTopPricesFiltersByAmount(t *testing.T) {
 		endpoint: server.URL,
 	}
 
-	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100)
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
 	if err != nil {
 		t.Fatalf("GetTopPrices returned error: %v", err)
 	}
@@ -190,13 +193,13 @@ func TestGateAdapterGetTopPricesLowestAmountNoFilter(t *testing.T) {
 		endpoint: server.URL,
 	}
 
-	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 0)
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 0, "")
 	if err != nil {
 		t.Fatalf("GetTopPrices returned error: %v", err)
 	}
 
-	if len(points) != 1 {
-		t.Fatalf("expected 1 price point, got %d", len(points))
+	if len(points) != 2 {
+		t.Fatalf("expected 2 price points, got %d", len(points))
 	}
 
 	point := points[0]
@@ -204,6 +207,32 @@ func TestGateAdapterGetTopPricesLowestAmountNoFilter(t *testing.T) {
 	if point.Merchant != "Merchant 2" {
 		t.Fatalf("expected Merchant 2, got %q", point.Merchant)
 	}
+	if points[1].Rank != 2 || points[1].Merchant != "Merchant 1" {
+		t.Fatalf("expected Merchant 1 at rank 2, got %#v", points[1])
+	}
+}
+
+func TestGateAdapterSendsPayMethodFilter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("failed to parse request body: %v", err)
+		}
+		if got := r.PostForm.Get("pay_type"); got != "2" {
+			t.Fatalf("expected pay_type=2 for 银行卡, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"code":0,"data":{"lists":[]}}`)
+	}))
+	defer server.Close()
+
+	adapter := &GateAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "银行卡"); err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
 }
 
 func TestGateAdapterReturnsHTTPError(t *testing.T) {
@@ -217,12 +246,51 @@ func TestGateAdapterReturnsHTTPError(t *testing.T) {
 		endpoint: server.URL,
 	}
 
-	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100)
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
 	if err == nil || !strings.Contains(err.Error(), "503") {
 		t.Fatalf("expected Gate HTTP error, got %v", err)
 	}
 }
 
+func TestGateAdapterReportsRetryAfter(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		http.Error(w, "too many requests", http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	adapter := &GateAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	var statusErr *domain.ExchangeStatusError
+	if !errors.As(err, &statusErr) {
+		t.Fatalf("expected ExchangeStatusError, got %v", err)
+	}
+	if statusErr.StatusCode != http.StatusTooManyRequests || statusErr.RetryAfter != 2*time.Minute {
+		t.Fatalf("unexpected status error: %#v", statusErr)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":                              0,
+		"30":                            30 * time.Second,
+		"-5":                            0,
+		"soon":                          0,
+		"Fri, 16 Oct 2026 08:01:30 GMT": 90 * time.Second,
+		"Fri, 16 Oct 2026 07:59:00 GMT": 0,
+	}
+	for raw, want := range cases {
+		if got := parseRetryAfter(raw, now); got != want {
+			t.Fatalf("parseRetryAfter(%q) = %s, want %s", raw, got, want)
+		}
+	}
+}
+
 func TestGateAdapterRejectsOversizedResponse(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, _ = io.WriteString(w, strings.Repeat("x", int(maxExchangeResponseBytes)+1))
@@ -234,7 +302,7 @@ func TestGateAdapterRejectsOversizedResponse(t *testing.T) {
 		endpoint: server.URL,
 	}
 
-	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100)
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
 	if err == nil || !strings.Contains(err.Error(), "exceeds") {
 		t.Fatalf("expected oversized response error, got %v", err)
 	}
@@ -0,0 +1,333 @@
+package exchange
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+// GenericJSONSpec describes a venue whose ad list is a plain JSON document.
+//
+// Endpoint and Body may contain the placeholders {symbol}, {fiat}, {side},
+// {amount} and {pay_method}; {side} is the venue's value from Sides, {amount}
+// is empty for the lowest tier and {pay_method} is empty without a filter,
+// otherwise the PayMethodCodes code of the filter (or the canonical name). Paths are dot separated, where a number selects an array
+// element and "*" expands every element.
+type GenericJSONSpec struct {
+	Name           string
+	Aliases        []string
+	Endpoint       string
+	Method         string
+	Headers        map[string]string
+	Body           string
+	Sides          map[string]string
+	ItemsPath      string
+	SuccessPath    string
+	SuccessValue   string
+	Fields         GenericJSONFields
+	PayMethodCodes map[string]string
+}
+
+// GenericJSONFields are the paths of ad fields relative to one list item.
+type GenericJSONFields struct {
+	Price           string
+	MinAmount       string
+	MaxAmount       string
+	AvailableAmount string
+	Merchant        string
+	MerchantID      string
+	PayMethods      string
+}
+
+type GenericJSONAdapter struct {
+	client  *http.Client
+	headers http.Header
+	spec    GenericJSONSpec
+}
+
+func NewGenericJSONAdapter(spec GenericJSONSpec, transport Transport) *GenericJSONAdapter {
+	return &GenericJSONAdapter{
+		client:  transport.client(),
+		headers: transport.Headers,
+		spec:    spec,
+	}
+}
+
+// RegisterGenericJSON registers a configured venue. Unlike Register it reports
+// errors, because the spec comes from configuration rather than code.
+func RegisterGenericJSON(spec GenericJSONSpec) error {
+	headers := make(http.Header, len(spec.Headers))
+	for key, value := range spec.Headers {
+		headers.Set(key, value)
+	}
+	return register(Registration{
+		Info:           domain.ExchangeInfo{Name: spec.Name, Aliases: spec.Aliases},
+		DefaultHeaders: headers,
+		New:            func(transport Transport) domain.IExchange { return NewGenericJSONAdapter(spec, transport) },
+	}, true)
+}
+
+// SupportsPayMethod implements domain.IPayMethodFilterer.
+func (a *GenericJSONAdapter) SupportsPayMethod(payMethod string) bool {
+	return supportsPayMethod(payMethod, a.spec.PayMethodCodes)
+}
+
+func (a *GenericJSONAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	venueSide, ok := a.spec.Sides[side]
+	if !ok {
+		return nil, &domain.UnsupportedQueryError{Exchange: a.spec.Name, Reason: "side " + side}
+	}
+
+	amountValue := ""
+	if amount > 0 {
+		amountValue = strconv.FormatFloat(amount, 'f', -1, 64)
+	}
+	payMethodValue, err := payMethodFilterCode(a.spec.Name, payMethod, a.spec.PayMethodCodes)
+	if err != nil {
+		// Without a code for the method, send the canonical name itself.
+		payMethodValue = payMethod
+	}
+	placeholders := map[string]string{
+		"{symbol}":     symbol,
+		"{fiat}":       fiat,
+		"{side}":       venueSide,
+		"{amount}":     amountValue,
+		"{pay_method}": payMethodValue,
+	}
+
+	req, err := a.newRequest(ctx, placeholders)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	payload, err := readExchangeResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, exchangeStatusError(a.spec.Name, resp, payload)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	decoder.UseNumber()
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	if a.spec.SuccessPath != "" {
+		got, _ := firstJSONString(document, a.spec.SuccessPath)
+		if got != a.spec.SuccessValue {
+			return nil, fmt.Errorf("%s api error: %s=%q", a.spec.Name, a.spec.SuccessPath, got)
+		}
+	}
+
+	now := time.Now()
+	items := lookupJSONPath(document, a.spec.ItemsPath)
+	if len(items) == 1 {
+		if list, ok := items[0].([]any); ok {
+			items = list
+		}
+	}
+	points := make([]domain.PricePoint, 0, len(items))
+	for _, item := range items {
+		point, ok := a.itemToPoint(item, symbol, fiat, side, amount, now)
+		if ok {
+			points = append(points, point)
+		}
+	}
+
+	if side == "BUY" {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Price < points[j].Price
+		})
+	} else {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Price > points[j].Price
+		})
+	}
+
+	for i := range points {
+		points[i].Rank = i + 1
+	}
+
+	return points, nil
+}
+
+func (a *GenericJSONAdapter) newRequest(ctx context.Context, placeholders map[string]string) (*http.Request, error) {
+	queryValues := make([]string, 0, len(placeholders)*2)
+	bodyValues := make([]string, 0, len(placeholders)*2)
+	for placeholder, value := range placeholders {
+		queryValues = append(queryValues, placeholder, url.QueryEscape(value))
+		bodyValues = append(bodyValues, placeholder, value)
+	}
+
+	endpoint := strings.NewReplacer(queryValues...).Replace(a.spec.Endpoint)
+	var body io.Reader
+	if a.spec.Body != "" {
+		body = strings.NewReader(strings.NewReplacer(bodyValues...).Replace(a.spec.Body))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, a.spec.Method, endpoint, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	for key, value := range a.spec.Headers {
+		req.Header.Set(key, value)
+	}
+	setHeaders(req, a.headers)
+	return req, nil
+}
+
+func (a *GenericJSONAdapter) itemToPoint(item any, symbol, fiat, side string, targetAmount float64, now time.Time) (domain.PricePoint, bool) {
+	fields := a.spec.Fields
+
+	price, err := jsonFloat(item, fields.Price)
+	if err != nil || price <= 0 {
+		return domain.PricePoint{}, false
+	}
+
+	rawMin, _ := firstJSONString(item, fields.MinAmount)
+	rawMax, _ := firstJSONString(item, fields.MaxAmount)
+	minAmount, maxAmount, err := parseLimitRange(rawMin, rawMax)
+	if err != nil {
+		return domain.PricePoint{}, false
+	}
+
+	if !domain.WithinAmountLimits(targetAmount, minAmount, maxAmount) {
+		return domain.PricePoint{}, false
+	}
+
+	var availableAmount float64
+	if fields.AvailableAmount != "" {
+		availableAmount, err = jsonFloat(item, fields.AvailableAmount)
+		if err != nil || availableAmount < 0 {
+			return domain.PricePoint{}, false
+		}
+	}
+
+	merchantID, _ := firstJSONString(item, fields.MerchantID)
+	merchant, _ := firstJSONString(item, fields.Merchant)
+	merchantID = strings.TrimSpace(merchantID)
+	merchant = strings.TrimSpace(merchant)
+	if merchant == "" {
+		merchant = merchantID
+	}
+
+	var payMethods []string
+	if fields.PayMethods != "" {
+		for _, value := range lookupJSONPath(item, fields.PayMethods) {
+			for _, method := range jsonStrings(value) {
+				payMethods = append(payMethods, strings.Split(method, ",")...)
+			}
+		}
+	}
+
+	return domain.PricePoint{
+		Exchange:        a.spec.Name,
+		Symbol:          symbol,
+		Fiat:            fiat,
+		Side:            side,
+		TargetAmount:    targetAmount,
+		Price:           price,
+		Merchant:        merchant,
+		MerchantID:      merchantID,
+		CreatedAt:       now,
+		MinAmount:       minAmount,
+		MaxAmount:       maxAmount,
+		AvailableAmount: availableAmount,
+		PayMethods:      normalizeCodedPayMethods(payMethods, a.spec.PayMethodCodes),
+	}, true
+}
+
+// lookupJSONPath resolves a dot-separated path; an empty path is the value
+// itself. Missing keys yield no values rather than an error.
+func lookupJSONPath(value any, path string) []any {
+	current := []any{value}
+	if path == "" {
+		return current
+	}
+
+	for _, segment := range strings.Split(path, ".") {
+		next := make([]any, 0, len(current))
+		for _, node := range current {
+			switch typed := node.(type) {
+			case map[string]any:
+				if child, ok := typed[segment]; ok {
+					next = append(next, child)
+				}
+			case []any:
+				if segment == "*" {
+					next = append(next, typed...)
+					continue
+				}
+				if index, err := strconv.Atoi(segment); err == nil && index >= 0 && index < len(typed) {
+					next = append(next, typed[index])
+				}
+			}
+		}
+		current = next
+	}
+	return current
+}
+
+// jsonStrings flattens a scalar or array value into strings; objects are skipped.
+func jsonStrings(value any) []string {
+	switch typed := value.(type) {
+	case string:
+		return []string{typed}
+	case json.Number:
+		return []string{typed.String()}
+	case bool:
+		return []string{strconv.FormatBool(typed)}
+	case []any:
+		var result []string
+		for _, item := range typed {
+			result = append(result, jsonStrings(item)...)
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+func firstJSONString(value any, path string) (string, bool) {
+	if path == "" {
+		return "", false
+	}
+	for _, found := range lookupJSONPath(value, path) {
+		if values := jsonStrings(found); len(values) > 0 {
+			return values[0], true
+		}
+	}
+	return "", false
+}
+
+func jsonFloat(value any, path string) (float64, error) {
+	raw, ok := firstJSONString(value, path)
+	if !ok {
+		return 0, fmt.Errorf("missing numeric value at %s", path)
+	}
+	return parseFiniteFloat(strings.ReplaceAll(raw, ",", ""))
+}
@@ -0,0 +1,150 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGenericJSONAdapterMapsConfiguredPaths(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Fatalf("expected GET request, got %s", r.Method)
+		}
+		query := r.URL.Query()
+		if query.Get("coin") != "USDT" || query.Get("currency") != "CNY" || query.Get("type") != "sell" || query.Get("amount") != "100" {
+			t.Fatalf("unexpected templated query: %s", r.URL.RawQuery)
+		}
+		if got := r.Header.Get("X-Desk"); got != "monitor" {
+			t.Fatalf("expected configured header, got %q", got)
+		}
+
+		_, _ = io.WriteString(w, `{
+			"status": {"ok": true},
+			"data": {
+				"ads": [
+					{"price": "7.01", "limits": {"min": 500, "max": 5000}, "stock": 900, "owner": {"name": "Too High", "id": 11}, "payments": [{"code": 1}]},
+					{"price": 6.98, "limits": {"min": "50", "max": "1,000"}, "stock": "320.5", "owner": {"name": "Matched Merchant", "id": 22}, "payments": [{"code": 2}, {"code": "wechat"}]},
+					{"price": "6.95", "limits": {"min": 10, "max": 50}, "stock": 10, "owner": {"name": "Too Low", "id": 33}, "payments": [{"code": 1}]},
+					{"price": "broken", "limits": {"min": 10, "max": 5000}, "stock": 10, "owner": {"name": "Broken", "id": 44}}
+				]
+			}
+		}`)
+	}))
+	defer server.Close()
+
+	adapter := NewGenericJSONAdapter(GenericJSONSpec{
+		Name:         "DeskOTC",
+		Endpoint:     server.URL + "/ads?coin={symbol}&currency={fiat}&type={side}&amount={amount}",
+		Method:       http.MethodGet,
+		Headers:      map[string]string{"X-Desk": "monitor"},
+		Sides:        map[string]string{"BUY": "sell", "SELL": "buy"},
+		ItemsPath:    "data.ads",
+		SuccessPath:  "status.ok",
+		SuccessValue: "true",
+		Fields: GenericJSONFields{
+			Price:           "price",
+			MinAmount:       "limits.min",
+			MaxAmount:       "limits.max",
+			AvailableAmount: "stock",
+			Merchant:        "owner.name",
+			MerchantID:      "owner.id",
+			PayMethods:      "payments.*.code",
+		},
+		PayMethodCodes: map[string]string{"1": "银行卡", "2": "支付宝"},
+	}, DefaultTransport())
+	adapter.client = &http.Client{Timeout: 2 * time.Second}
+
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	if err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+
+	if len(points) != 1 {
+		t.Fatalf("expected 1 price point, got %d", len(points))
+	}
+
+	point := points[0]
+	if point.Exchange != "DeskOTC" || point.Merchant != "Matched Merchant" || point.MerchantID != "22" {
+		t.Fatalf("unexpected point identity: %#v", point)
+	}
+	if point.PayMethods != "支付宝, 微信" {
+		t.Fatalf("expected pay methods 支付宝, 微信, got %q", point.PayMethods)
+	}
+	if point.Rank != 1 {
+		t.Fatalf("expected rank 1, got %d", point.Rank)
+	}
+	assertCloseFloat(t, point.Price, 6.98)
+	assertCloseFloat(t, point.MinAmount, 50)
+	assertCloseFloat(t, point.MaxAmount, 1000)
+	assertCloseFloat(t, point.AvailableAmount, 320.5)
+}
+
+func TestGenericJSONAdapterPostsBodyTemplate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Fatalf("expected POST request, got %s", r.Method)
+		}
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			t.Fatalf("failed to read request body: %v", err)
+		}
+		if got := string(body); got != `{"asset":"USDT","fiat":"CNY","side":"1","amount":""}` {
+			t.Fatalf("unexpected request body: %s", got)
+		}
+
+		_, _ = io.WriteString(w, `[
+			{"p": "7.10", "min": "100", "max": "1000"},
+			{"p": "7.15", "min": "100", "max": "1000"}
+		]`)
+	}))
+	defer server.Close()
+
+	adapter := NewGenericJSONAdapter(GenericJSONSpec{
+		Name:     "DeskOTC",
+		Endpoint: server.URL,
+		Method:   http.MethodPost,
+		Body:     `{"asset":"{symbol}","fiat":"{fiat}","side":"{side}","amount":"{amount}"}`,
+		Sides:    map[string]string{"BUY": "0", "SELL": "1"},
+		Fields:   GenericJSONFields{Price: "p", MinAmount: "min", MaxAmount: "max"},
+	}, DefaultTransport())
+
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "SELL", 0, "")
+	if err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+
+	if len(points) != 2 || points[0].Price != 7.15 || points[1].Rank != 2 {
+		t.Fatalf("expected descending SELL ranking from a root array, got %#v", points)
+	}
+}
+
+func TestGenericJSONAdapterReportsUnsuccessfulResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"code": "429", "data": []}`)
+	}))
+	defer server.Close()
+
+	adapter := NewGenericJSONAdapter(GenericJSONSpec{
+		Name:         "DeskOTC",
+		Endpoint:     server.URL,
+		Method:       http.MethodGet,
+		Sides:        map[string]string{"BUY": "BUY"},
+		ItemsPath:    "data",
+		SuccessPath:  "code",
+		SuccessValue: "0",
+		Fields:       GenericJSONFields{Price: "p", MinAmount: "min", MaxAmount: "max"},
+	}, DefaultTransport())
+
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 0, "")
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Fatalf("expected unsuccessful response error, got %v", err)
+	}
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "SELL", 0, ""); err == nil {
+		t.Fatal("expected an unmapped side to be rejected")
+	}
+}
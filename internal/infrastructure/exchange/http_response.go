@@ -5,8 +5,12 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"net/http"
 	"strconv"
 	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
 )
 
 const maxExchangeResponseBytes int64 = 2 << 20
@@ -41,3 +45,146 @@ func parseFiniteFloat(raw string) (float64, error) {
 	}
 	return value, nil
 }
+
+// exchangeStatusError describes a non-200 response and keeps the venue's
+// Retry-After hint for the collector. A nil payload omits the body snippet.
+func exchangeStatusError(exchangeName string, resp *http.Response, payload []byte) error {
+	statusErr := &domain.ExchangeStatusError{
+		Exchange:   exchangeName,
+		StatusCode: resp.StatusCode,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+	if payload != nil {
+		statusErr.Snippet = exchangeResponseSnippet(payload)
+	}
+	return statusErr
+}
+
+// parseRetryAfter accepts both Retry-After forms: delay seconds and an HTTP
+// date. Missing, malformed or past values yield 0.
+func parseRetryAfter(raw string, now time.Time) time.Duration {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(raw); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(raw); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+func exchangeResponseSnippet(payload []byte) string {
+	snippet := strings.TrimSpace(string(payload))
+	snippet = strings.ReplaceAll(snippet, "\n", " ")
+	snippet = strings.ReplaceAll(snippet, "\t", " ")
+	if len(snippet) > 180 {
+		return snippet[:180] + "..."
+	}
+	if snippet == "" {
+		return "empty response"
+	}
+	return snippet
+}
+
+// parseLimitRange parses an ad's per-order fiat limits.
+func parseLimitRange(rawMin, rawMax string) (float64, float64, error) {
+	minAmount, err := parseFiniteFloat(strings.ReplaceAll(rawMin, ",", ""))
+	if err != nil {
+		return 0, 0, err
+	}
+	maxAmount, err := parseFiniteFloat(strings.ReplaceAll(rawMax, ",", ""))
+	if err != nil {
+		return 0, 0, err
+	}
+	if minAmount < 0 || maxAmount < minAmount {
+		return 0, 0, fmt.Errorf("invalid limit range %s~%s", rawMin, rawMax)
+	}
+	return minAmount, maxAmount, nil
+}
+
+// normalizeCodedPayMethods maps numeric pay-method codes to display names,
+// keeping unknown codes as-is.
+func normalizeCodedPayMethods(codes []string, codeMap map[string]string) string {
+	methods := make([]string, 0, len(codes))
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		if code == "" || code == "0" {
+			continue
+		}
+		if name, ok := codeMap[code]; ok {
+			methods = append(methods, name)
+			continue
+		}
+		methods = append(methods, code)
+	}
+	return domain.JoinNormalizedPayMethods(methods)
+}
+
+// payMethodFilterCode is the inverse of a venue's code map: it returns the
+// code whose name normalizes to the canonical payMethod. An empty payMethod
+// means no filter and yields an empty code.
+func payMethodFilterCode(exchangeName, payMethod string, codeMap map[string]string) (string, error) {
+	if payMethod == "" {
+		return "", nil
+	}
+	for code, name := range codeMap {
+		if domain.NormalizePayMethodName(name) == payMethod {
+			return code, nil
+		}
+	}
+	return "", &domain.UnsupportedQueryError{Exchange: exchangeName, Reason: fmt.Sprintf("pay method filter %q", payMethod)}
+}
+
+// supportsPayMethod reports whether payMethodFilterCode finds a code for
+// payMethod in codeMap.
+func supportsPayMethod(payMethod string, codeMap map[string]string) bool {
+	_, err := payMethodFilterCode("", payMethod, codeMap)
+	return err == nil
+}
+
+// completionPercent normalizes a completion rate to a percentage. Venues
+// report either a fraction ("0.987") or a percentage ("98.7"); a rate of 1% or
+// less is not a realistic merchant, so values up to 1 are treated as fractions.
+func completionPercent(rate float64) *float64 {
+	if rate < 0 {
+		return nil
+	}
+	if rate <= 1 {
+		rate *= 100
+	}
+	return &rate
+}
+
+// parseCompletionPercent is completionPercent for string fields; empty or
+// malformed values are not reported.
+func parseCompletionPercent(raw string) *float64 {
+	if strings.TrimSpace(raw) == "" {
+		return nil
+	}
+	rate, err := parseFiniteFloat(raw)
+	if err != nil {
+		return nil
+	}
+	return completionPercent(rate)
+}
+
+// parseOrderCount parses an order counter; empty or malformed values are not
+// reported.
+func parseOrderCount(raw string) *int {
+	count, err := strconv.Atoi(strings.TrimSpace(raw))
+	if err != nil || count < 0 {
+		return nil
+	}
+	return &count
+}
+
+// reportedBadge converts a badge flag into a reputation field.
+func reportedBadge(value bool) *bool {
+	return &value
+}
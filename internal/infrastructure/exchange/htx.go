@@ -0,0 +1,247 @@
+package exchange
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+const htxOTCEndpoint = "https://www.htx.com/-/x/otc/v1/data/trade-market"
+
+type HTXAdapter struct {
+	client   *http.Client
+	headers  http.Header
+	endpoint string
+}
+
+var htxDefaultHeaders = http.Header{
+	"Accept":          {"application/json, text/plain, */*"},
+	"Referer":         {"https://www.htx.com/zh-cn/fiat-crypto/trade/buy-usdt-cny/"},
+	"User-Agent":      {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/146.0.0.0 Safari/537.36"},
+	"Accept-Language": {"zh-CN,zh;q=0.9"},
+}
+
+func init() {
+	Register(Registration{
+		Info:           domain.ExchangeInfo{Name: domain.ExchangeHTX},
+		DefaultHeaders: htxDefaultHeaders,
+		New:            func(transport Transport) domain.IExchange { return NewHTXAdapter(transport) },
+	})
+}
+
+func NewHTXAdapter(transport Transport) *HTXAdapter {
+	return &HTXAdapter{
+		client:   transport.client(),
+		headers:  transport.Headers,
+		endpoint: htxOTCEndpoint,
+	}
+}
+
+type HTXResponse struct {
+	Code    int     `json:"code"`
+	Message string  `json:"message"`
+	Success bool    `json:"success"`
+	Data    []HTXAd `json:"data"`
+}
+
+type HTXAd struct {
+	ID            int64  `json:"id"`
+	UID           int64  `json:"uid"`
+	UserName      string `json:"userName"`
+	PayMethod     string `json:"payMethod"`
+	MinTradeLimit string `json:"minTradeLimit"`
+	MaxTradeLimit string `json:"maxTradeLimit"`
+	Price         string `json:"price"`
+	TradeCount    string `json:"tradeCount"`
+}
+
+// HTX identifies coins and fiat currencies by numeric ids.
+var (
+	htxCoinIDs = map[string]string{
+		"BTC":  "1",
+		"USDT": "2",
+		"ETH":  "3",
+	}
+	htxCurrencyIDs = map[string]string{
+		"CNY": "172",
+	}
+)
+
+var htxCNYPayMethodCodeMap = map[string]string{
+	"1": "银行卡",
+	"2": "支付宝",
+	"3": "微信",
+}
+
+// SupportsPayMethod implements domain.IPayMethodFilterer.
+func (a *HTXAdapter) SupportsPayMethod(payMethod string) bool {
+	return supportsPayMethod(payMethod, htxCNYPayMethodCodeMap)
+}
+
+func (a *HTXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	tradeType, err := htxTradeType(side)
+	if err != nil {
+		return nil, err
+	}
+	coinID, ok := htxCoinIDs[symbol]
+	if !ok {
+		return nil, &domain.UnsupportedQueryError{Exchange: "htx", Reason: "symbol " + symbol}
+	}
+	currencyID, ok := htxCurrencyIDs[fiat]
+	if !ok {
+		return nil, &domain.UnsupportedQueryError{Exchange: "htx", Reason: "fiat " + fiat}
+	}
+
+	payMethodCode, err := payMethodFilterCode("htx", payMethod, htxCNYPayMethodCodeMap)
+	if err != nil {
+		return nil, err
+	}
+	if payMethodCode == "" {
+		payMethodCode = "0"
+	}
+
+	requestURL, err := url.Parse(a.endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("parse HTX endpoint: %w", err)
+	}
+	query := requestURL.Query()
+	query.Set("coinId", coinID)
+	query.Set("currency", currencyID)
+	query.Set("tradeType", tradeType)
+	query.Set("currPage", "1")
+	query.Set("payMethod", payMethodCode)
+	query.Set("acceptOrder", "0")
+	query.Set("blockType", "general")
+	query.Set("online", "1")
+	query.Set("range", "0")
+	query.Set("onlyTradable", "false")
+	query.Set("isFollowed", "false")
+	if amount > 0 {
+		query.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+	} else {
+		query.Set("amount", "")
+	}
+	requestURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	setHeaders(req, htxDefaultHeaders)
+	setHeaders(req, a.headers)
+
+	resp, err := a.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	payload, err := readExchangeResponse(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, exchangeStatusError("htx", resp, payload)
+	}
+
+	var data HTXResponse
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, err
+	}
+
+	if data.Code != http.StatusOK || !data.Success {
+		return nil, fmt.Errorf("htx api error: %d - %s", data.Code, data.Message)
+	}
+
+	now := time.Now()
+	points := make([]domain.PricePoint, 0, len(data.Data))
+	for _, ad := range data.Data {
+		point, ok := htxAdToPoint(ad, symbol, fiat, side, amount, now)
+		if ok {
+			points = append(points, point)
+		}
+	}
+
+	if side == "BUY" {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Price < points[j].Price
+		})
+	} else {
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Price > points[j].Price
+		})
+	}
+
+	for i := range points {
+		points[i].Rank = i + 1
+	}
+
+	return points, nil
+}
+
+func htxTradeType(side string) (string, error) {
+	switch side {
+	case "BUY":
+		return "sell", nil
+	case "SELL":
+		return "buy", nil
+	default:
+		return "", &domain.UnsupportedQueryError{Exchange: "htx", Reason: "side " + side}
+	}
+}
+
+func htxAdToPoint(ad HTXAd, symbol, fiat, side string, targetAmount float64, now time.Time) (domain.PricePoint, bool) {
+	price, err := parseFiniteFloat(ad.Price)
+	if err != nil || price <= 0 {
+		return domain.PricePoint{}, false
+	}
+
+	minAmount, maxAmount, err := parseLimitRange(ad.MinTradeLimit, ad.MaxTradeLimit)
+	if err != nil {
+		return domain.PricePoint{}, false
+	}
+
+	if !domain.WithinAmountLimits(targetAmount, minAmount, maxAmount) {
+		return domain.PricePoint{}, false
+	}
+
+	availableAmount, err := parseFiniteFloat(ad.TradeCount)
+	if err != nil || availableAmount < 0 {
+		return domain.PricePoint{}, false
+	}
+
+	merchantID := ""
+	if ad.UID > 0 {
+		merchantID = strconv.FormatInt(ad.UID, 10)
+	}
+	merchant := strings.TrimSpace(ad.UserName)
+	if merchant == "" {
+		merchant = merchantID
+	}
+
+	return domain.PricePoint{
+		Exchange:        domain.ExchangeHTX,
+		Symbol:          symbol,
+		Fiat:            fiat,
+		Side:            side,
+		TargetAmount:    targetAmount,
+		Price:           price,
+		Merchant:        merchant,
+		MerchantID:      merchantID,
+		CreatedAt:       now,
+		MinAmount:       minAmount,
+		MaxAmount:       maxAmount,
+		AvailableAmount: availableAmount,
+		PayMethods:      normalizeCodedPayMethods(strings.Split(ad.PayMethod, ","), htxCNYPayMethodCodeMap),
+	}, true
+}
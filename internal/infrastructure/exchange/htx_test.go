@@ -0,0 +1,137 @@
+package exchange
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTXAdapterGetTopPricesFiltersByAmount(t *testing.T) {
+	fixture := readFixture(t, "htx_trade_market_sell_usdt_cny.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Fatalf("expected GET request, got %s", r.Method)
+		}
+
+		query := r.URL.Query()
+		if got := query.Get("coinId"); got != "2" {
+			t.Fatalf("expected coinId=2 for USDT, got %q", got)
+		}
+		if got := query.Get("currency"); got != "172" {
+			t.Fatalf("expected currency=172 for CNY, got %q", got)
+		}
+		if got := query.Get("tradeType"); got != "sell" {
+			t.Fatalf("expected tradeType=sell for user BUY, got %q", got)
+		}
+		if got := query.Get("amount"); got != "100" {
+			t.Fatalf("expected amount=100, got %q", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	adapter := &HTXAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	if err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+
+	if len(points) != 1 {
+		t.Fatalf("expected 1 price point, got %d", len(points))
+	}
+
+	point := points[0]
+	if point.Exchange != "HTX" {
+		t.Fatalf("expected exchange HTX, got %q", point.Exchange)
+	}
+	if point.Merchant != "Matched Merchant" || point.MerchantID != "40183377" {
+		t.Fatalf("unexpected merchant %q (%q)", point.Merchant, point.MerchantID)
+	}
+	if point.PayMethods != "支付宝, 微信, 银行卡" {
+		t.Fatalf("expected pay methods 支付宝, 微信, 银行卡, got %q", point.PayMethods)
+	}
+	if point.Rank != 1 {
+		t.Fatalf("expected rank 1, got %d", point.Rank)
+	}
+	assertCloseFloat(t, point.Price, 6.99)
+	assertCloseFloat(t, point.MinAmount, 100)
+	assertCloseFloat(t, point.MaxAmount, 2000)
+	assertCloseFloat(t, point.AvailableAmount, 286)
+}
+
+func TestHTXAdapterLowestAmountRanksAllAds(t *testing.T) {
+	fixture := readFixture(t, "htx_trade_market_sell_usdt_cny.json")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("amount"); got != "" {
+			t.Fatalf("expected empty amount, got %q", got)
+		}
+		_, _ = w.Write(fixture)
+	}))
+	defer server.Close()
+
+	adapter := &HTXAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 0, "")
+	if err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+
+	if len(points) != 3 {
+		t.Fatalf("expected 3 price points, got %d", len(points))
+	}
+	if points[0].Merchant != "Too Low" || points[2].Merchant != "Too High" || points[2].Rank != 3 {
+		t.Fatalf("expected ascending BUY ranking, got %#v", points)
+	}
+}
+
+func TestHTXAdapterRejectsUnsupportedFiat(t *testing.T) {
+	adapter := &HTXAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: "http://127.0.0.1:0",
+	}
+
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "XYZ", "BUY", 0, "")
+	if err == nil || !strings.Contains(err.Error(), "fiat XYZ") {
+		t.Fatalf("expected unsupported fiat error, got %v", err)
+	}
+}
+
+func TestHTXAdapterReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"code": 40001, "message": "system busy", "success": false, "data": null}`)
+	}))
+	defer server.Close()
+
+	adapter := &HTXAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 100, "")
+	if err == nil || !strings.Contains(err.Error(), "system busy") {
+		t.Fatalf("expected HTX API error, got %v", err)
+	}
+}
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	payload, err := os.ReadFile("testdata/" + name)
+	if err != nil {
+		t.Fatalf("read fixture %s: %v", name, err)
+	}
+	return payload
+}
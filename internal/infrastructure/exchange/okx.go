@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"sort"
+	"strings"
 	"time"
 
 	"c2c_monitor/internal/domain"
@@ -13,14 +14,29 @@ import (
 
 type OKXAdapter struct {
 	client   *http.Client
+	headers  http.Header
 	endpoint string
 }
 
 const okxC2CEndpoint = "https://www.okx.com/v3/c2c/tradingOrders/books"
 
-func NewOKXAdapter() *OKXAdapter {
+var okxDefaultHeaders = http.Header{
+	"User-Agent": {"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"},
+	"Accept":     {"application/json"},
+}
+
+func init() {
+	Register(Registration{
+		Info:           domain.ExchangeInfo{Name: domain.ExchangeOKX},
+		DefaultHeaders: okxDefaultHeaders,
+		New:            func(transport Transport) domain.IExchange { return NewOKXAdapter(transport) },
+	})
+}
+
+func NewOKXAdapter(transport Transport) *OKXAdapter {
 	return &OKXAdapter{
-		client:   &http.Client{Timeout: 10 * time.Second},
+		client:   transport.client(),
+		headers:  transport.Headers,
 		endpoint: okxC2CEndpoint,
 	}
 }
@@ -43,9 +59,34 @@ type OKXAd struct {
 	NickName               string   `json:"nickName"`
 	MerchantId             string   `json:"merchantId"`
 	PaymentMethods         []string `json:"paymentMethods"`
+	CompletedOrderQuantity string   `json:"completedOrderQuantity"`
+	CompletedRate          string   `json:"completedRate"`
+	CreatorType            string   `json:"creatorType"`
+}
+
+// okxPaymentMethodMap lists the paymentMethod query values of the CNY pay methods.
+var okxPaymentMethodMap = map[string]string{
+	"bank":   "银行卡",
+	"wxPay":  "微信",
+	"aliPay": "支付宝",
+}
+
+// SupportsPayMethod implements domain.IPayMethodFilterer.
+func (a *OKXAdapter) SupportsPayMethod(payMethod string) bool {
+	return supportsPayMethod(payMethod, okxPaymentMethodMap)
+}
+
+func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	book, err := a.GetOrderBook(ctx, symbol, fiat, side, payMethod)
+	if err != nil {
+		return nil, err
+	}
+	return domain.TierPrices(book, amount), nil
 }
 
-func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64) ([]domain.PricePoint, error) {
+// GetOrderBook fetches the books endpoint, which lists the same page of ads
+// whatever the amount, so every tier can be derived from one request.
+func (a *OKXAdapter) GetOrderBook(ctx context.Context, symbol, fiat, side, payMethod string) ([]domain.PricePoint, error) {
 	// Map User Side to OKX Advertiser Side
 	// User BUY -> Advertiser SELL
 	// User SELL -> Advertiser BUY
@@ -55,7 +96,15 @@ func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string
 	} else if side == "SELL" {
 		okxSide = "buy"
 	} else {
-		return nil, fmt.Errorf("invalid side: %s", side)
+		return nil, &domain.UnsupportedQueryError{Exchange: "okx", Reason: "side " + side}
+	}
+
+	paymentMethod, err := payMethodFilterCode("okx", payMethod, okxPaymentMethodMap)
+	if err != nil {
+		return nil, err
+	}
+	if paymentMethod == "" {
+		paymentMethod = "all"
 	}
 
 	requestURL, err := url.Parse(a.endpoint)
@@ -66,7 +115,7 @@ func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string
 	query.Set("quoteCurrency", fiat)
 	query.Set("baseCurrency", symbol)
 	query.Set("side", okxSide)
-	query.Set("paymentMethod", "all")
+	query.Set("paymentMethod", paymentMethod)
 	query.Set("userType", "all")
 	query.Set("showTrade", "false")
 	query.Set("showFollow", "false")
@@ -80,8 +129,8 @@ func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string
 		return nil, err
 	}
 
-	req.Header.Set("User-Agent", "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
-	req.Header.Set("Accept", "application/json")
+	setHeaders(req, okxDefaultHeaders)
+	setHeaders(req, a.headers)
 
 	resp, err := a.client.Do(req)
 	if err != nil {
@@ -90,7 +139,7 @@ func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("okx api returned status: %d", resp.StatusCode)
+		return nil, exchangeStatusError("okx", resp, nil)
 	}
 
 	var data OKXResponse
@@ -110,6 +159,7 @@ func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string
 	}
 
 	var points []domain.PricePoint
+	now := time.Now()
 
 	for _, ad := range ads {
 		price, err := parseFiniteFloat(ad.Price)
@@ -136,23 +186,16 @@ func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string
 			Symbol:          symbol,
 			Fiat:            fiat,
 			Side:            side,
-			TargetAmount:    amount,
 			Rank:            0, // Will be assigned later
 			Price:           price,
 			Merchant:        ad.NickName,
 			MerchantID:      ad.MerchantId,
-			CreatedAt:       time.Now(),
+			CreatedAt:       now,
 			MinAmount:       minAmount,
 			MaxAmount:       maxAmount,
 			AvailableAmount: availableAmount,
 			PayMethods:      payMethodsStr,
-		}
-
-		// Filter by amount (CNY)
-		if amount > 0 {
-			if amount < minAmount || amount > maxAmount {
-				continue
-			}
+			Reputation:      okxReputation(ad),
 		}
 		points = append(points, point)
 	}
@@ -170,14 +213,23 @@ func (a *OKXAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string
 		})
 	}
 
-	// Assign Rank and Top N
+	// Assign Rank
 	for i := range points {
 		points[i].Rank = i + 1
 	}
 
-	if len(points) > 1 {
-		points = points[:1]
-	}
-
 	return points, nil
 }
+
+// okxReputation maps the ad's creator stats. creatorType is "common" for
+// ordinary users and a certification tier for merchants.
+func okxReputation(ad OKXAd) domain.MerchantReputation {
+	reputation := domain.MerchantReputation{
+		CompletionRate: parseCompletionPercent(ad.CompletedRate),
+		MonthOrders:    parseOrderCount(ad.CompletedOrderQuantity),
+	}
+	if creatorType := strings.TrimSpace(ad.CreatorType); creatorType != "" {
+		reputation.VerifiedMerchant = reportedBadge(creatorType != "common")
+	}
+	return reputation
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"c2c_monitor/internal/domain"
 )
 
 func TestOKXAdapterFindsLaterAmountMatch(t *testing.T) {
@@ -35,7 +37,10 @@ func TestOKXAdapterFindsLaterAmountMatch(t *testing.T) {
 						"quoteMaxAmountPerOrder": "5000",
 						"nickName": "Matched",
 						"merchantId": "matched",
-						"paymentMethods": ["alipay"]
+						"paymentMethods": ["alipay"],
+						"completedOrderQuantity": "35",
+						"completedRate": "0.9143",
+						"creatorType": "common"
 					}
 				],
 				"buy": []
@@ -48,13 +53,20 @@ func TestOKXAdapterFindsLaterAmountMatch(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500)
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "")
 	if err != nil {
 		t.Fatalf("GetTopPrices returned error: %v", err)
 	}
 	if len(points) != 1 || points[0].Merchant != "Matched" || points[0].Price != 7.05 {
 		t.Fatalf("unexpected points: %#v", points)
 	}
+	reputation := points[0].Reputation
+	if reputation.CompletionRate == nil || *reputation.CompletionRate < 91.42 || *reputation.CompletionRate > 91.44 {
+		t.Fatalf("expected completion rate as a percentage, got %#v", reputation)
+	}
+	if reputation.MonthOrders == nil || *reputation.MonthOrders != 35 || reputation.VerifiedMerchant == nil || *reputation.VerifiedMerchant {
+		t.Fatalf("expected creator stats of a common user, got %#v", reputation)
+	}
 }
 
 func TestOKXAdapterReturnsHTTPError(t *testing.T) {
@@ -67,7 +79,7 @@ func TestOKXAdapterReturnsHTTPError(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500)
+	_, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "")
 	if err == nil || !strings.Contains(err.Error(), "503") {
 		t.Fatalf("expected OKX HTTP error, got %v", err)
 	}
@@ -83,7 +95,7 @@ func TestOKXAdapterRejectsMalformedResponse(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500); err == nil {
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, ""); err == nil {
 		t.Fatal("expected malformed response error")
 	}
 }
@@ -124,7 +136,7 @@ func TestOKXAdapterSkipsInvalidAmountRange(t *testing.T) {
 		client:   &http.Client{Timeout: 2 * time.Second},
 		endpoint: server.URL,
 	}
-	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500)
+	points, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 500, "")
 	if err != nil {
 		t.Fatalf("GetTopPrices returned error: %v", err)
 	}
@@ -132,3 +144,34 @@ func TestOKXAdapterSkipsInvalidAmountRange(t *testing.T) {
 		t.Fatalf("expected valid amount range candidate, got %#v", points)
 	}
 }
+
+func TestOKXAdapterOrderBookKeepsEveryAmount(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":0,"data":{"buy":[],"sell":[
+			{"price":"7.05","availableAmount":"1000","quoteMinAmountPerOrder":"100","quoteMaxAmountPerOrder":"5000","nickName":"Small","merchantId":"small"},
+			{"price":"7.01","availableAmount":"9000","quoteMinAmountPerOrder":"1000","quoteMaxAmountPerOrder":"50000","nickName":"Large","merchantId":"large"}
+		]}}`))
+	}))
+	defer server.Close()
+
+	adapter := &OKXAdapter{
+		client:   &http.Client{Timeout: 2 * time.Second},
+		endpoint: server.URL,
+	}
+	book, err := adapter.GetOrderBook(context.Background(), "USDT", "CNY", "BUY", "")
+	if err != nil {
+		t.Fatalf("GetOrderBook returned error: %v", err)
+	}
+	if len(book) != 2 || book[0].Merchant != "Large" || book[0].Rank != 1 || book[1].Rank != 2 {
+		t.Fatalf("expected the whole book ranked by price, got %#v", book)
+	}
+	if book[0].TargetAmount != 0 || !book[0].CreatedAt.Equal(book[1].CreatedAt) {
+		t.Fatalf("expected an untiered snapshot, got %#v", book)
+	}
+
+	tier := domain.TierPrices(book, 500)
+	if len(tier) != 1 || tier[0].Merchant != "Small" || tier[0].Rank != 1 || tier[0].TargetAmount != 500 {
+		t.Fatalf("unexpected 500 CNY tier: %#v", tier)
+	}
+}
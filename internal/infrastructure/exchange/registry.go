@@ -0,0 +1,108 @@
+package exchange
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+
+	"c2c_monitor/internal/domain"
+)
+
+// Registration wires one venue into the monitor. Info is added to the domain
+// registry unless the venue is already known there (built-in venues are), so a
+// private venue only needs a single Register call from its adapter's init.
+// New receives the venue's configured transport.
+type Registration struct {
+	Info           domain.ExchangeInfo
+	DefaultHeaders http.Header
+	New            func(transport Transport) domain.IExchange
+}
+
+var (
+	registryMu    sync.RWMutex
+	registrations = make(map[string]Registration)
+)
+
+// Register records an adapter constructor. It panics on invalid or duplicate
+// registrations because it is meant to be called from init.
+func Register(registration Registration) {
+	if err := register(registration, false); err != nil {
+		panic(err)
+	}
+}
+
+// register records a registration; a newVenue must not already exist in the
+// domain registry, which keeps configured venues from shadowing built-in ones.
+func register(registration Registration, newVenue bool) error {
+	name := registration.Info.Name
+	if registration.New == nil {
+		return fmt.Errorf("exchange %q registered without a constructor", name)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, exists := registrations[name]; exists {
+		return fmt.Errorf("exchange %q registered twice", name)
+	}
+	if _, known := domain.LookupExchange(name); !known || newVenue {
+		if err := domain.RegisterExchange(registration.Info); err != nil {
+			return err
+		}
+	}
+	registrations[name] = registration
+	return nil
+}
+
+// DefaultHeaders returns a copy of the headers a venue sends on every request.
+func DefaultHeaders(name string) http.Header {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registrations[name].DefaultHeaders.Clone()
+}
+
+// NewRegisteredAdapters constructs every venue known to the domain registry,
+// each with the transport built from its entry in options (defaults when
+// absent). A venue without a registered constructor is an error so that a
+// fork cannot silently ship a name that never collects.
+func NewRegisteredAdapters(options map[string]TransportOptions) (map[string]domain.IExchange, error) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	adapters := make(map[string]domain.IExchange, len(registrations))
+	var missing []string
+	for _, name := range domain.SupportedExchangeNames() {
+		registration, ok := registrations[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		transport := DefaultTransport()
+		if venueOptions, configured := options[name]; configured {
+			var err error
+			transport, err = NewTransport(venueOptions)
+			if err != nil {
+				return nil, fmt.Errorf("exchange %s transport: %w", name, err)
+			}
+		}
+		adapters[name] = registration.New(transport)
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("no adapter registered for exchanges: %v", missing)
+	}
+	return adapters, nil
+}
+
+// setHeaders replaces every listed header; empty values are skipped, so a
+// header listed only with empty values is removed.
+func setHeaders(req *http.Request, headers http.Header) {
+	for key, values := range headers {
+		req.Header.Del(key)
+		for _, value := range values {
+			if value != "" {
+				req.Header.Add(key, value)
+			}
+		}
+	}
+}
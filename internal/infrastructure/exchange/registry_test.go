@@ -0,0 +1,49 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"c2c_monitor/internal/domain"
+)
+
+type privateVenueAdapter struct{}
+
+func (privateVenueAdapter) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	return nil, nil
+}
+
+func TestRegisterPrivateVenue(t *testing.T) {
+	Register(Registration{
+		Info:           domain.ExchangeInfo{Name: "PrivateDesk", Aliases: []string{"desk"}},
+		DefaultHeaders: http.Header{"X-Desk": {"1"}},
+		New:            func(Transport) domain.IExchange { return privateVenueAdapter{} },
+	})
+
+	if name, err := domain.NormalizeExchangeName("desk"); err != nil || name != "PrivateDesk" {
+		t.Fatalf("expected private venue alias to be registered, got %q err=%v", name, err)
+	}
+
+	adapters, err := NewRegisteredAdapters(nil)
+	if err != nil {
+		t.Fatalf("NewRegisteredAdapters returned error: %v", err)
+	}
+	for _, name := range domain.SupportedExchangeNames() {
+		if adapters[name] == nil {
+			t.Fatalf("expected adapter for %s", name)
+		}
+	}
+	if _, ok := adapters["PrivateDesk"].(privateVenueAdapter); !ok {
+		t.Fatalf("expected private venue constructor to be used, got %T", adapters["PrivateDesk"])
+	}
+
+	headers := DefaultHeaders("PrivateDesk")
+	headers.Set("X-Desk", "changed")
+	if got := DefaultHeaders("PrivateDesk").Get("X-Desk"); got != "1" {
+		t.Fatalf("expected default headers to be copied, got %q", got)
+	}
+	if got := DefaultHeaders(domain.ExchangeGate).Get("X-Page-Host"); got != "www.gate.com" {
+		t.Fatalf("expected built-in default headers, got %q", got)
+	}
+}
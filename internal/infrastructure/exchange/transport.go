@@ -0,0 +1,84 @@
+package exchange
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const defaultRequestTimeout = 10 * time.Second
+
+// TransportOptions are the per-exchange HTTP settings from configuration.
+type TransportOptions struct {
+	// Proxy is an http, https or socks5 URL; empty falls back to the
+	// HTTP_PROXY/HTTPS_PROXY environment.
+	Proxy string
+	// Headers override the venue's default headers; an empty value removes
+	// the header.
+	Headers            http.Header
+	Timeout            time.Duration
+	InsecureSkipVerify bool
+	TLSServerName      string
+	TLSMinVersion      uint16
+}
+
+// Transport is the client an adapter sends requests with, plus the header
+// overrides applied after the venue defaults.
+type Transport struct {
+	Client  *http.Client
+	Headers http.Header
+}
+
+// DefaultTransport is the transport of a venue without configured options.
+func DefaultTransport() Transport {
+	return Transport{Client: &http.Client{Timeout: defaultRequestTimeout}}
+}
+
+// NewTransport builds the HTTP client described by options.
+func NewTransport(options TransportOptions) (Transport, error) {
+	base, ok := http.DefaultTransport.(*http.Transport)
+	if !ok {
+		return Transport{}, fmt.Errorf("default http transport has unexpected type %T", http.DefaultTransport)
+	}
+	roundTripper := base.Clone()
+
+	if options.Proxy != "" {
+		proxyURL, err := url.Parse(options.Proxy)
+		if err != nil {
+			return Transport{}, fmt.Errorf("parse proxy url: %w", err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return Transport{}, fmt.Errorf("unsupported proxy scheme %q", proxyURL.Scheme)
+		}
+		roundTripper.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if options.InsecureSkipVerify || options.TLSServerName != "" || options.TLSMinVersion != 0 {
+		roundTripper.TLSClientConfig = &tls.Config{
+			InsecureSkipVerify: options.InsecureSkipVerify,
+			ServerName:         options.TLSServerName,
+			MinVersion:         options.TLSMinVersion,
+		}
+	}
+
+	timeout := options.Timeout
+	if timeout <= 0 {
+		timeout = defaultRequestTimeout
+	}
+
+	return Transport{
+		Client:  &http.Client{Timeout: timeout, Transport: roundTripper},
+		Headers: options.Headers.Clone(),
+	}, nil
+}
+
+func (t Transport) client() *http.Client {
+	if t.Client == nil {
+		return DefaultTransport().Client
+	}
+	return t.Client
+}
@@ -0,0 +1,76 @@
+package exchange
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTransportHeaderOverridesReplaceVenueDefaults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("User-Agent"); got != "Monitor/2.0" {
+			t.Fatalf("expected overridden User-Agent, got %q", got)
+		}
+		if got := r.Header.Get("Cookie"); got != "" {
+			t.Fatalf("expected empty override to remove Cookie, got %q", got)
+		}
+		if got := r.Header.Get("X-Page-Host"); got != "www.gate.com" {
+			t.Fatalf("expected untouched default header, got %q", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":0,"data":{"lists":[]}}`))
+	}))
+	defer server.Close()
+
+	transport, err := NewTransport(TransportOptions{
+		Headers: http.Header{"User-Agent": {"Monitor/2.0"}, "Cookie": {""}},
+		Timeout: 2 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("NewTransport returned error: %v", err)
+	}
+	adapter := NewGateAdapter(transport)
+	adapter.endpoint = server.URL
+
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 0, ""); err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+}
+
+func TestTransportRoutesThroughProxy(t *testing.T) {
+	proxied := make(chan string, 1)
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied <- r.URL.String()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"code":"000000","data":[]}`))
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(TransportOptions{Proxy: proxy.URL, Timeout: 2 * time.Second})
+	if err != nil {
+		t.Fatalf("NewTransport returned error: %v", err)
+	}
+	adapter := NewBinanceAdapter(transport)
+	adapter.endpoint = "http://p2p.binance.invalid/search"
+
+	if _, err := adapter.GetTopPrices(context.Background(), "USDT", "CNY", "BUY", 0, ""); err != nil {
+		t.Fatalf("GetTopPrices returned error: %v", err)
+	}
+	if got := <-proxied; got != adapter.endpoint {
+		t.Fatalf("expected proxied request for %s, got %s", adapter.endpoint, got)
+	}
+	if transport.Client.Timeout != 2*time.Second {
+		t.Fatalf("expected configured timeout, got %s", transport.Client.Timeout)
+	}
+}
+
+func TestNewTransportRejectsUnsupportedProxy(t *testing.T) {
+	if _, err := NewTransport(TransportOptions{Proxy: "ftp://proxy.local:21"}); err == nil {
+		t.Fatal("expected unsupported proxy scheme to be rejected")
+	}
+	if _, err := NewRegisteredAdapters(map[string]TransportOptions{"Gate": {Proxy: "ftp://proxy.local:21"}}); err == nil {
+		t.Fatal("expected NewRegisteredAdapters to report the invalid transport")
+	}
+}
@@ -0,0 +1,92 @@
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"c2c_monitor/internal/domain"
+)
+
+// MultiNotifier implements domain.IMultiNotifier over several channels. The
+// monitor service queues alerts per channel; Send and SendTo are used for
+// messages that are not queued, such as the digest.
+type MultiNotifier struct {
+	channels map[string]domain.INotifier
+}
+
+// NewMultiNotifier keys notifiers by their Channel method, or "notifier".
+// Notifiers that fan out themselves, such as Telegram, add each of their
+// channels.
+func NewMultiNotifier(notifiers ...domain.INotifier) *MultiNotifier {
+	channels := make(map[string]domain.INotifier, len(notifiers))
+	for _, notifier := range notifiers {
+		if multi, ok := notifier.(domain.IMultiNotifier); ok {
+			maps.Copy(channels, multi.Channels())
+			continue
+		}
+		name := "notifier"
+		if channel, ok := notifier.(interface{ Channel() string }); ok {
+			name = channel.Channel()
+		}
+		channels[name] = notifier
+	}
+	return &MultiNotifier{channels: channels}
+}
+
+func (n *MultiNotifier) Enabled() bool {
+	return len(n.channels) > 0
+}
+
+func (n *MultiNotifier) Channel() string {
+	return strings.Join(n.names(), ",")
+}
+
+// Channels implements domain.IMultiNotifier
+func (n *MultiNotifier) Channels() map[string]domain.INotifier {
+	channels := make(map[string]domain.INotifier, len(n.channels))
+	for name, notifier := range n.channels {
+		channels[name] = notifier
+	}
+	return channels
+}
+
+// Send implements domain.INotifier; it tries every channel and reports each
+// failure.
+func (n *MultiNotifier) Send(ctx context.Context, subject, body string) error {
+	return n.SendTo(ctx, nil, subject, body)
+}
+
+// SendTo sends to recipients on channels that address them, such as email,
+// and to the configured destination on the others.
+func (n *MultiNotifier) SendTo(ctx context.Context, to []string, subject, body string) error {
+	type recipientNotifier interface {
+		SendTo(ctx context.Context, to []string, subject, body string) error
+	}
+
+	var errs []error
+	for _, name := range n.names() {
+		var err error
+		if notifier, ok := n.channels[name].(recipientNotifier); ok && len(to) > 0 {
+			err = notifier.SendTo(ctx, to, subject, body)
+		} else {
+			err = n.channels[name].Send(ctx, subject, body)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *MultiNotifier) names() []string {
+	names := make([]string, 0, len(n.channels))
+	for name := range n.channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
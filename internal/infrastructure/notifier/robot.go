@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const defaultRobotTimeout = 10 * time.Second
+
+// RobotError is a message an IM robot webhook refused. RateLimited is set for
+// the platform's rate-limit codes and for HTTP 429.
+type RobotError struct {
+	Platform    string
+	Code        int
+	Message     string
+	RateLimited bool
+}
+
+func (e *RobotError) Error() string {
+	if e.RateLimited {
+		return fmt.Sprintf("%s robot is rate limited: %d %s", e.Platform, e.Code, e.Message)
+	}
+	return fmt.Sprintf("%s robot returned error: %d %s", e.Platform, e.Code, e.Message)
+}
+
+// robotWebhook posts JSON messages to a DingTalk, WeCom or Feishu robot. Once
+// the platform reports a rate limit, sends fail without a request until
+// cooldown has passed, so the alert outbox retries them later instead of
+// extending the limit.
+type robotWebhook struct {
+	platform string
+	cooldown time.Duration
+	client   *http.Client
+	// result extracts the platform's error code and message from a response;
+	// rateLimited reports whether a code is a rate limit.
+	result      func(body []byte) (code int, message string, err error)
+	rateLimited func(code int) bool
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+}
+
+func newRobotWebhook(platform string, timeout, cooldown time.Duration, result func([]byte) (int, string, error), rateLimited func(int) bool) *robotWebhook {
+	if timeout <= 0 {
+		timeout = defaultRobotTimeout
+	}
+	return &robotWebhook{
+		platform:    platform,
+		cooldown:    cooldown,
+		client:      &http.Client{Timeout: timeout},
+		result:      result,
+		rateLimited: rateLimited,
+	}
+}
+
+func (r *robotWebhook) post(ctx context.Context, endpoint string, message any) error {
+	r.mu.Lock()
+	blockedUntil := r.blockedUntil
+	r.mu.Unlock()
+	if time.Now().Before(blockedUntil) {
+		return &RobotError{Platform: r.platform, Message: "waiting until " + blockedUntil.Format(time.RFC3339), RateLimited: true}
+	}
+
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("encode %s message: %w", r.platform, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create %s request: %w", r.platform, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		// Robot URLs carry their access token, so only the cause is reported.
+		return fmt.Errorf("post %s message: %w", r.platform, unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return r.block(&RobotError{Platform: r.platform, Code: resp.StatusCode, Message: http.StatusText(resp.StatusCode), RateLimited: true})
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &RobotError{Platform: r.platform, Code: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+	}
+	code, text, err := r.result(body)
+	if err != nil {
+		return fmt.Errorf("decode %s response: %w", r.platform, err)
+	}
+	if code == 0 {
+		return nil
+	}
+	robotErr := &RobotError{Platform: r.platform, Code: code, Message: text, RateLimited: r.rateLimited(code)}
+	if robotErr.RateLimited {
+		return r.block(robotErr)
+	}
+	return robotErr
+}
+
+func (r *robotWebhook) block(err *RobotError) error {
+	r.mu.Lock()
+	r.blockedUntil = time.Now().Add(r.cooldown)
+	r.mu.Unlock()
+	return err
+}
+
+// errcodeResult reads the {"errcode":0,"errmsg":"ok"} responses of DingTalk
+// and WeCom.
+func errcodeResult(body []byte) (int, string, error) {
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	err := json.Unmarshal(body, &result)
+	return result.ErrCode, result.ErrMsg, err
+}
+
+// markdownMessage returns the Markdown body of an alert, or the text of an
+// HTML message such as the digest under its subject. lineBreak separates the
+// lines of converted HTML, for platforms that join single newlines.
+func markdownMessage(subject, body, lineBreak string) string {
+	if !looksLikeHTML(body) {
+		return body
+	}
+	return "**" + subject + "**\n\n" + strings.ReplaceAll(htmlToText(body), "\n", lineBreak)
+}
+
+// truncateBytes cuts text to at most limit bytes without splitting a rune.
+func truncateBytes(text string, limit int) string {
+	if len(text) <= limit {
+		return text
+	}
+	const ellipsis = "…"
+	cut := limit - len(ellipsis)
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + ellipsis
+}
+
+// unwrapURLError drops the URL from a transport error, for endpoints that
+// carry secrets.
+func unwrapURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
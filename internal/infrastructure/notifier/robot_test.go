@@ -0,0 +1,109 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDingTalkNotifierSignsMarkdownMessages(t *testing.T) {
+	var message struct {
+		MsgType  string `json:"msgtype"`
+		Markdown struct {
+			Title string `json:"title"`
+			Text  string `json:"text"`
+		} `json:"markdown"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("access_token") != "abc" {
+			t.Errorf("expected the access token to be kept, got %q", r.URL.RawQuery)
+		}
+		mac := hmac.New(sha256.New, []byte("SECsecret"))
+		mac.Write([]byte(query.Get("timestamp") + "\nSECsecret"))
+		if got, want := query.Get("sign"), base64.StdEncoding.EncodeToString(mac.Sum(nil)); query.Get("timestamp") == "" || got != want {
+			t.Errorf("expected sign %q, got %q", want, got)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		_, _ = w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer server.Close()
+
+	notifier := NewDingTalkNotifier(server.URL+"/robot/send?access_token=abc", "SECsecret", time.Second)
+	if err := notifier.Send(context.Background(), "Daily digest", "<p>USDT/CNY</p><p>Gate 6.90</p>"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if message.MsgType != "markdown" || message.Markdown.Title != "Daily digest" || message.Markdown.Text != "**Daily digest**\n\nUSDT/CNY  \nGate 6.90" {
+		t.Fatalf("unexpected message: %#v", message)
+	}
+}
+
+func TestWeComNotifierBacksOffAfterRateLimit(t *testing.T) {
+	var calls atomic.Int32
+	var content string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var message struct {
+			Markdown struct {
+				Content string `json:"content"`
+			} `json:"markdown"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		content = message.Markdown.Content
+		_, _ = w.Write([]byte(`{"errcode":45009,"errmsg":"api freq out of limit"}`))
+	}))
+	defer server.Close()
+
+	notifier := NewWeComNotifier(server.URL, time.Second)
+	alert := "**📉 New Low**\n\n" + strings.Repeat("价", 2000)
+	for range 2 {
+		err := notifier.Send(context.Background(), "subject", alert)
+		var robotErr *RobotError
+		if !errors.As(err, &robotErr) || !robotErr.RateLimited {
+			t.Fatalf("expected a rate-limit error, got %v", err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("expected sends to wait out the rate limit, got %d requests", got)
+	}
+	if len(content) > weComMaxMessageBytes || !strings.HasPrefix(content, "**📉 New Low**") || !strings.HasSuffix(content, "价…") {
+		t.Fatalf("expected the alert truncated to %d bytes, got %d bytes", weComMaxMessageBytes, len(content))
+	}
+}
+
+func TestFeishuNotifierSendsSignedCards(t *testing.T) {
+	var message map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		_, _ = w.Write([]byte(`{"code":19021,"msg":"sign match fail or timestamp is not within one hour from current time"}`))
+	}))
+	defer server.Close()
+
+	notifier := NewFeishuNotifier(server.URL, "secret", time.Second)
+	err := notifier.Send(context.Background(), "[C2C] New Low", "**📉 New Low**\n\n- Exchange: Gate")
+
+	var robotErr *RobotError
+	if !errors.As(err, &robotErr) || robotErr.Code != 19021 || robotErr.RateLimited {
+		t.Fatalf("expected the feishu error code, got %v", err)
+	}
+	timestamp, _ := message["timestamp"].(string)
+	mac := hmac.New(sha256.New, []byte(timestamp+"\nsecret"))
+	if timestamp == "" || message["sign"] != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+		t.Fatalf("expected a signed request, got %#v", message)
+	}
+	card := message["card"].(map[string]any)
+	title := card["header"].(map[string]any)["title"].(map[string]any)["content"]
+	element := card["elements"].([]any)[0].(map[string]any)
+	if message["msg_type"] != "interactive" || title != "[C2C] New Low" || element["tag"] != "markdown" || element["content"] != "**📉 New Low**\n\n- Exchange: Gate" {
+		t.Fatalf("unexpected card: %#v", message)
+	}
+}
@@ -43,15 +43,24 @@ func (n *SMTPNotifier) Enabled() bool {
 	return true
 }
 
+func (n *SMTPNotifier) Channel() string {
+	return "email"
+}
+
 // Send implements domain.INotifier
 func (n *SMTPNotifier) Send(ctx context.Context, subject, body string) error {
+	return n.SendTo(ctx, n.To, subject, body)
+}
+
+// SendTo sends to recipients instead of the configured To list.
+func (n *SMTPNotifier) SendTo(ctx context.Context, to []string, subject, body string) error {
 	if err := validateHeaderValue("subject", subject); err != nil {
 		return err
 	}
 	if err := validateHeaderValue("from", n.From); err != nil {
 		return err
 	}
-	for _, recipient := range n.To {
+	for _, recipient := range to {
 		if err := validateHeaderValue("recipient", recipient); err != nil {
 			return err
 		}
@@ -59,7 +68,7 @@ func (n *SMTPNotifier) Send(ctx context.Context, subject, body string) error {
 	if strings.TrimSpace(n.Host) == "" || n.Port <= 0 || n.Port > 65535 {
 		return fmt.Errorf("invalid SMTP address")
 	}
-	if len(n.To) == 0 {
+	if len(to) == 0 {
 		return fmt.Errorf("no SMTP recipients configured")
 	}
 
@@ -117,7 +126,7 @@ func (n *SMTPNotifier) Send(ctx context.Context, subject, body string) error {
 	if err := client.Mail(n.From); err != nil {
 		return smtpContextError(sendCtx, "set SMTP sender", err)
 	}
-	for _, recipient := range n.To {
+	for _, recipient := range to {
 		if err := client.Rcpt(recipient); err != nil {
 			return smtpContextError(sendCtx, "set SMTP recipient", err)
 		}
@@ -127,7 +136,7 @@ func (n *SMTPNotifier) Send(ctx context.Context, subject, body string) error {
 	if err != nil {
 		return smtpContextError(sendCtx, "open SMTP message", err)
 	}
-	if _, err := io.WriteString(writer, buildMessage(n.From, n.To, subject, body)); err != nil {
+	if _, err := io.WriteString(writer, buildMessage(n.From, to, subject, body)); err != nil {
 		_ = writer.Close()
 		return smtpContextError(sendCtx, "write SMTP message", err)
 	}
@@ -21,6 +21,11 @@ func TestSMTPNotifierRejectsHeaderInjection(t *testing.T) {
 	if err == nil || !strings.Contains(err.Error(), "newline") {
 		t.Fatalf("expected header injection error, got %v", err)
 	}
+
+	err = notifier.SendTo(context.Background(), []string{"desk@example.com\r\nBcc: attacker@example.com"}, "digest", "<p>body</p>")
+	if err == nil || !strings.Contains(err.Error(), "newline") {
+		t.Fatalf("expected recipient injection error, got %v", err)
+	}
 }
 
 func TestNotifierEnabledStates(t *testing.T) {
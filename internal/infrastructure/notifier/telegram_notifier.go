@@ -0,0 +1,251 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+const (
+	DefaultTelegramBaseURL = "https://api.telegram.org"
+	defaultTelegramTimeout = 10 * time.Second
+	// telegramMaxMessage is the Bot API limit on message text, in characters.
+	telegramMaxMessage = 4096
+)
+
+// TelegramNotifier implements domain.IMultiNotifier by sending alerts to chats
+// through the Telegram Bot API. Each chat is its own channel, so a chat that
+// fails is retried without resending to the others. Alerts are sent as plain
+// text, so merchant names never break a parse mode. BaseURL points at the Bot API, which tests
+// replace with an httptest server.
+type TelegramNotifier struct {
+	BaseURL string
+	Token   string
+	ChatIDs []string // Numeric ids or @channel names
+	Timeout time.Duration
+	Client  *http.Client
+}
+
+// NewTelegramNotifier creates a TelegramNotifier; an empty baseURL selects the
+// public Bot API.
+func NewTelegramNotifier(baseURL, token string, chatIDs []string, timeout time.Duration) *TelegramNotifier {
+	if baseURL == "" {
+		baseURL = DefaultTelegramBaseURL
+	}
+	if timeout <= 0 {
+		timeout = defaultTelegramTimeout
+	}
+	return &TelegramNotifier{
+		BaseURL: strings.TrimRight(baseURL, "/"),
+		Token:   token,
+		ChatIDs: chatIDs,
+		Timeout: timeout,
+		Client:  &http.Client{},
+	}
+}
+
+func (n *TelegramNotifier) Enabled() bool {
+	return true
+}
+
+func (n *TelegramNotifier) Channel() string {
+	return "telegram"
+}
+
+// Format asks for alerts rendered as plain text.
+func (n *TelegramNotifier) Format() string {
+	return domain.FormatText
+}
+
+// Channels implements domain.IMultiNotifier with one channel per chat, named
+// "telegram:<chat id>".
+func (n *TelegramNotifier) Channels() map[string]domain.INotifier {
+	channels := make(map[string]domain.INotifier, len(n.ChatIDs))
+	for _, chatID := range n.ChatIDs {
+		chat := &TelegramChat{notifier: n, chatID: chatID}
+		channels[chat.Channel()] = chat
+	}
+	return channels
+}
+
+// Send implements domain.INotifier. Text alerts already start with their
+// subject; HTML messages such as the digest are converted to text under it.
+func (n *TelegramNotifier) Send(ctx context.Context, subject, body string) error {
+	text := telegramText(subject, body)
+	var errs []error
+	for _, chatID := range n.ChatIDs {
+		if err := n.SendMessage(ctx, chatID, text); err != nil {
+			errs = append(errs, fmt.Errorf("chat %s: %w", chatID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func telegramText(subject, body string) string {
+	if looksLikeHTML(body) {
+		return subject + "\n\n" + htmlToText(body)
+	}
+	return body
+}
+
+// TelegramChat is the channel of one chat of a TelegramNotifier.
+type TelegramChat struct {
+	notifier *TelegramNotifier
+	chatID   string
+}
+
+func (c *TelegramChat) Enabled() bool {
+	return true
+}
+
+func (c *TelegramChat) Channel() string {
+	return "telegram:" + c.chatID
+}
+
+// Format asks for alerts rendered as plain text.
+func (c *TelegramChat) Format() string {
+	return domain.FormatText
+}
+
+// Send implements domain.INotifier for this chat only.
+func (c *TelegramChat) Send(ctx context.Context, subject, body string) error {
+	return c.notifier.SendMessage(ctx, c.chatID, telegramText(subject, body))
+}
+
+// SendMessage sends text to one chat, truncated to the Bot API limit.
+func (n *TelegramNotifier) SendMessage(ctx context.Context, chatID, text string) error {
+	if runes := []rune(text); len(runes) > telegramMaxMessage {
+		text = string(runes[:telegramMaxMessage-1]) + "…"
+	}
+	request := map[string]any{"chat_id": chatID, "text": text, "disable_web_page_preview": true}
+	ctx, cancel := context.WithTimeout(ctx, n.Timeout)
+	defer cancel()
+	return n.call(ctx, "sendMessage", request, nil)
+}
+
+// TelegramUpdate is an incoming Bot API update; only messages are requested.
+type TelegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// GetUpdates long-polls for messages after offset, waiting up to wait.
+func (n *TelegramNotifier) GetUpdates(ctx context.Context, offset int64, wait time.Duration) ([]TelegramUpdate, error) {
+	request := map[string]any{"offset": offset, "timeout": int(wait.Seconds()), "allowed_updates": []string{"message"}}
+	ctx, cancel := context.WithTimeout(ctx, wait+n.Timeout)
+	defer cancel()
+	var updates []TelegramUpdate
+	if err := n.call(ctx, "getUpdates", request, &updates); err != nil {
+		return nil, err
+	}
+	return updates, nil
+}
+
+// TelegramError is a failed Bot API call. RetryAfter is set when Telegram
+// rate-limits the bot.
+type TelegramError struct {
+	Method      string
+	Code        int
+	Description string
+	RetryAfter  time.Duration
+}
+
+func (e *TelegramError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("telegram %s failed: %d %s (retry after %s)", e.Method, e.Code, e.Description, e.RetryAfter)
+	}
+	return fmt.Sprintf("telegram %s failed: %d %s", e.Method, e.Code, e.Description)
+}
+
+func (n *TelegramNotifier) call(ctx context.Context, method string, request any, result any) error {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("encode telegram %s: %w", method, err)
+	}
+	endpoint := n.BaseURL + "/bot" + n.Token + "/" + method
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("create telegram %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		// The URL carries the bot token, so only the cause is reported.
+		return fmt.Errorf("telegram %s: %w", method, unwrapURLError(err))
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+		Parameters  struct {
+			RetryAfter int `json:"retry_after"`
+		} `json:"parameters"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(&envelope); err != nil {
+		return fmt.Errorf("telegram %s returned status %d with an unreadable body: %w", method, resp.StatusCode, err)
+	}
+	if !envelope.OK {
+		code := envelope.ErrorCode
+		if code == 0 {
+			code = resp.StatusCode
+		}
+		return &TelegramError{
+			Method:      method,
+			Code:        code,
+			Description: envelope.Description,
+			RetryAfter:  time.Duration(envelope.Parameters.RetryAfter) * time.Second,
+		}
+	}
+	if result == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Result, result); err != nil {
+		return fmt.Errorf("decode telegram %s result: %w", method, err)
+	}
+	return nil
+}
+
+var (
+	htmlBreaks = regexp.MustCompile(`(?i)<br\s*/?>|</(p|h[1-6]|tr|li|div)>`)
+	htmlCells  = regexp.MustCompile(`(?i)</t[dh]>`)
+	htmlTags   = regexp.MustCompile(`<[^>]*>`)
+	blankLines = regexp.MustCompile(`\n\s*\n\s*`)
+)
+
+func looksLikeHTML(body string) bool {
+	return strings.HasPrefix(strings.TrimSpace(body), "<")
+}
+
+// htmlToText flattens the HTML of messages such as the digest for chats that
+// only take text: block ends become line breaks and table cells are separated
+// by " | ".
+func htmlToText(body string) string {
+	text := htmlBreaks.ReplaceAllString(body, "\n")
+	text = htmlCells.ReplaceAllString(text, " | ")
+	text = htmlTags.ReplaceAllString(text, "")
+	text = html.UnescapeString(text)
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(strings.TrimSpace(line), " |")
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n"))
+}
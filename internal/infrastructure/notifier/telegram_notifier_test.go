@@ -0,0 +1,85 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTelegramNotifierSendsTextToEveryChat(t *testing.T) {
+	var messages []map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bot123:token/sendMessage" {
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+		var message map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		messages = append(messages, message)
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	notifier := NewTelegramNotifier(server.URL+"/", "123:token", []string{"100", "@alerts"}, time.Second)
+	if err := notifier.Send(context.Background(), "Daily digest", "<h2>USDT/CNY</h2><table><tr><th>Tier</th><th>Low</th></tr><tr><td>1000</td><td>6.90 &amp; up</td></tr></table>"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+
+	if len(messages) != 2 || messages[0]["chat_id"] != "100" || messages[1]["chat_id"] != "@alerts" {
+		t.Fatalf("expected one message per chat, got %#v", messages)
+	}
+	want := "Daily digest\n\nUSDT/CNY\nTier | Low\n1000 | 6.90 & up"
+	if got := messages[0]["text"]; got != want {
+		t.Fatalf("expected HTML converted to text:\n%q\ngot:\n%q", want, got)
+	}
+}
+
+func TestTelegramChatsAreSeparateChannels(t *testing.T) {
+	var chats []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&message)
+		chats = append(chats, message["chat_id"])
+		if message["chat_id"] == "100" {
+			_, _ = w.Write([]byte(`{"ok":false,"error_code":403,"description":"Forbidden: bot was blocked by the user"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	}))
+	defer server.Close()
+
+	telegram := NewTelegramNotifier(server.URL, "123:token", []string{"100", "@alerts"}, time.Second)
+	channels := NewMultiNotifier(NewWebhookNotifier(server.URL, nil, "", "", time.Second, 0), telegram).Channels()
+	if len(channels) != 3 || channels["telegram:100"] == nil || channels["telegram:@alerts"] == nil {
+		t.Fatalf("expected one channel per chat next to the webhook, got %v", channels)
+	}
+
+	if err := channels["telegram:100"].Send(context.Background(), "subject", "plain alert"); err == nil {
+		t.Fatal("expected the blocked chat to fail")
+	}
+	if err := channels["telegram:@alerts"].Send(context.Background(), "subject", "plain alert"); err != nil {
+		t.Fatalf("send to the other chat: %v", err)
+	}
+	if len(chats) != 2 || chats[0] != "100" || chats[1] != "@alerts" {
+		t.Fatalf("expected each channel to send to its own chat only, got %v", chats)
+	}
+}
+
+func TestTelegramNotifierReportsRateLimits(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":429,"description":"Too Many Requests: retry after 7","parameters":{"retry_after":7}}`))
+	}))
+	defer server.Close()
+
+	notifier := NewTelegramNotifier(server.URL, "123:token", []string{"100"}, time.Second)
+	err := notifier.Send(context.Background(), "subject", "plain alert")
+
+	var telegramErr *TelegramError
+	if !errors.As(err, &telegramErr) || telegramErr.Code != http.StatusTooManyRequests || telegramErr.RetryAfter != 7*time.Second {
+		t.Fatalf("expected a rate-limit error with retry_after, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+const (
+	defaultWebhookTimeout         = 10 * time.Second
+	defaultWebhookRetryDelay      = time.Second
+	defaultWebhookSignatureHeader = "X-C2C-Signature"
+
+	// WebhookTimestampHeader carries the Unix time in seconds that a signed
+	// request was made, which receivers check to reject replays.
+	WebhookTimestampHeader = "X-C2C-Timestamp"
+)
+
+// WebhookPayload wraps messages that are not alerts, such as the digest.
+// Alerts are posted as rendered by the json alert template.
+type WebhookPayload struct {
+	EventType string    `json:"event_type"` // Always "message"
+	Subject   string    `json:"subject"`
+	Body      string    `json:"body"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// WebhookNotifier implements domain.INotifier with HTTP POSTs of JSON. When
+// Secret is set, WebhookTimestampHeader carries the Unix time of the request and
+// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of
+// "<timestamp>.<body>". Network errors, 429 and 5xx responses are retried
+// MaxRetries times with doubling delays.
+type WebhookNotifier struct {
+	URL             string
+	Headers         map[string]string
+	Secret          string
+	SignatureHeader string
+	MaxRetries      int
+	RetryDelay      time.Duration
+	Client          *http.Client
+}
+
+// NewWebhookNotifier creates a WebhookNotifier with a timeout per request.
+func NewWebhookNotifier(url string, headers map[string]string, secret, signatureHeader string, timeout time.Duration, maxRetries int) *WebhookNotifier {
+	if timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+	if signatureHeader == "" {
+		signatureHeader = defaultWebhookSignatureHeader
+	}
+	return &WebhookNotifier{
+		URL:             url,
+		Headers:         headers,
+		Secret:          secret,
+		SignatureHeader: signatureHeader,
+		MaxRetries:      maxRetries,
+		RetryDelay:      defaultWebhookRetryDelay,
+		Client:          &http.Client{Timeout: timeout},
+	}
+}
+
+func (n *WebhookNotifier) Enabled() bool {
+	return true
+}
+
+func (n *WebhookNotifier) Channel() string {
+	return "webhook"
+}
+
+// Format asks for alerts rendered as JSON.
+func (n *WebhookNotifier) Format() string {
+	return domain.FormatJSON
+}
+
+// Send implements domain.INotifier. A body that is a JSON object, as rendered
+// for alerts, is posted as is; anything else is wrapped in a WebhookPayload.
+func (n *WebhookNotifier) Send(ctx context.Context, subject, body string) error {
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "{") && json.Valid([]byte(trimmed)) {
+		return n.post(ctx, []byte(trimmed))
+	}
+	payload, err := json.Marshal(WebhookPayload{EventType: "message", Subject: subject, Body: body, Timestamp: time.Now().UTC()})
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+	return n.post(ctx, payload)
+}
+
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) error {
+	delay := n.RetryDelay
+	for attempt := 0; ; attempt++ {
+		retryable, err := n.postOnce(ctx, body)
+		if err == nil || !retryable || attempt >= n.MaxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w (retry cancelled: %v)", err, ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// postOnce sends body once and reports whether a failure may succeed on retry.
+func (n *WebhookNotifier) postOnce(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range n.Headers {
+		req.Header.Set(key, value)
+	}
+	if n.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(WebhookTimestampHeader, timestamp)
+		req.Header.Set(n.SignatureHeader, "sha256="+signWebhook(n.Secret, timestamp, body))
+	}
+
+	client := n.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retryable := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retryable, fmt.Errorf("webhook returned status: %d", resp.StatusCode)
+}
+
+// signWebhook returns the hex HMAC-SHA256 of "<timestamp>.<body>", so a
+// captured request cannot be replayed with a fresh timestamp.
+func signWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,95 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookNotifierPostsSignedPayloads(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get(WebhookTimestampHeader)
+		if sent, err := strconv.ParseInt(timestamp, 10, 64); err != nil || time.Since(time.Unix(sent, 0)).Abs() > time.Minute {
+			t.Errorf("expected the current Unix time in %s, got %q", WebhookTimestampHeader, timestamp)
+		}
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write([]byte(timestamp + "." + string(body)))
+		if got, want := r.Header.Get("X-Signature"), "sha256="+hex.EncodeToString(mac.Sum(nil)); got != want {
+			t.Errorf("expected signature %q, got %q", want, got)
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer token" {
+			t.Errorf("expected configured header, got %q", got)
+		}
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, map[string]string{"Authorization": "Bearer token"}, "secret", "X-Signature", time.Second, 0)
+	alert := `{"event_type":"new_low","exchange":"Gate","price":6.9}`
+	if err := notifier.Send(context.Background(), "subject", alert); err != nil {
+		t.Fatalf("send alert: %v", err)
+	}
+	if err := notifier.Send(context.Background(), "digest", "<p>body</p>"); err != nil {
+		t.Fatalf("send message: %v", err)
+	}
+
+	if len(bodies) != 2 || bodies[0] != alert {
+		t.Fatalf("expected the rendered alert to be posted as is, got %q", bodies)
+	}
+	var message WebhookPayload
+	if err := json.Unmarshal([]byte(bodies[1]), &message); err != nil || message.EventType != "message" || message.Subject != "digest" || message.Body != "<p>body</p>" {
+		t.Fatalf("expected other bodies to be wrapped in a message payload, got %q, %v", bodies[1], err)
+	}
+}
+
+func TestWebhookNotifierRetriesServerErrorsOnly(t *testing.T) {
+	var calls atomic.Int32
+	status := http.StatusInternalServerError
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(status)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, nil, "", "", time.Second, 2)
+	notifier.RetryDelay = time.Millisecond
+	if err := notifier.Send(context.Background(), "subject", "body"); err != nil || calls.Load() != 2 {
+		t.Fatalf("expected the 500 to be retried once, got %d calls, %v", calls.Load(), err)
+	}
+
+	calls.Store(0)
+	status = http.StatusBadRequest
+	err := notifier.Send(context.Background(), "subject", "body")
+	if err == nil || !strings.Contains(err.Error(), "400") || calls.Load() != 1 {
+		t.Fatalf("expected the 400 not to be retried, got %d calls, %v", calls.Load(), err)
+	}
+}
+
+func TestMultiNotifierSendsToEveryChannel(t *testing.T) {
+	webhook := NewWebhookNotifier("http://127.0.0.1:1", nil, "", "", time.Second, 0)
+	multi := NewMultiNotifier(NewDisabledNotifier(), webhook)
+
+	if got := multi.Channel(); got != "notifier,webhook" {
+		t.Fatalf("expected channels in name order, got %q", got)
+	}
+	if multi.Channels()["webhook"] != webhook {
+		t.Fatal("expected the webhook to be keyed by its channel")
+	}
+	err := multi.Send(context.Background(), "subject", "body")
+	if err == nil || !strings.HasPrefix(err.Error(), "webhook: ") {
+		t.Fatalf("expected the webhook failure to be reported, got %v", err)
+	}
+}
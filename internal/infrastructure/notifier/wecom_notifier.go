@@ -0,0 +1,56 @@
+package notifier
+
+import (
+	"context"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+const (
+	// WeCom group robots accept 20 messages a minute and answer faster
+	// senders with 45009.
+	weComRateLimitCooldown = time.Minute
+	weComMaxMessageBytes   = 4096
+)
+
+// WeComNotifier implements domain.INotifier with a WeCom (企业微信) group
+// robot, sending alerts as Markdown messages.
+type WeComNotifier struct {
+	URL   string
+	robot *robotWebhook
+}
+
+// NewWeComNotifier creates a WeComNotifier for a robot webhook URL, which
+// includes its key.
+func NewWeComNotifier(webhookURL string, timeout time.Duration) *WeComNotifier {
+	return &WeComNotifier{
+		URL: webhookURL,
+		robot: newRobotWebhook("wecom", timeout, weComRateLimitCooldown, errcodeResult, func(code int) bool {
+			return code == 45009
+		}),
+	}
+}
+
+func (n *WeComNotifier) Enabled() bool {
+	return true
+}
+
+func (n *WeComNotifier) Channel() string {
+	return "wecom"
+}
+
+// Format asks for alerts rendered as Markdown.
+func (n *WeComNotifier) Format() string {
+	return domain.FormatMarkdown
+}
+
+// Send implements domain.INotifier. WeCom messages have no title, so the
+// subject only heads converted HTML messages; alerts carry their own heading.
+func (n *WeComNotifier) Send(ctx context.Context, subject, body string) error {
+	message := map[string]any{
+		"msgtype":  "markdown",
+		"markdown": map[string]string{"content": truncateBytes(markdownMessage(subject, body, "\n"), weComMaxMessageBytes)},
+	}
+	return n.robot.post(ctx, n.URL, message)
+}
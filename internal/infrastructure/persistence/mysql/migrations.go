@@ -5,14 +5,31 @@ import (
 	"fmt"
 	"time"
 
+	"c2c_monitor/internal/domain"
 	"gorm.io/gorm"
 )
 
 const (
-	initialSchemaMigration    = "2026040401_initial_schema"
-	reliabilityIndexMigration = "2026081301_reliability_indexes"
-	alertBenchmarkMigration   = "2026082001_alert_benchmark"
-	amountBenchmarkMigration  = "2026082201_amount_benchmark_overrides"
+	initialSchemaMigration      = "2026040401_initial_schema"
+	reliabilityIndexMigration   = "2026081301_reliability_indexes"
+	alertBenchmarkMigration     = "2026082001_alert_benchmark"
+	amountBenchmarkMigration    = "2026082201_amount_benchmark_overrides"
+	roundTripSpreadMigration    = "2026101601_round_trip_spreads"
+	marketMatrixMigration       = "2026101602_market_matrix"
+	payMethodFilterMigration    = "2026101603_pay_method_filters"
+	merchantReputationMigration = "2026101604_merchant_reputation"
+	leaderLeaseMigration        = "2026101605_leader_leases"
+	alertOutboxMigration        = "2026101606_alert_outbox"
+	alertEventsMigration        = "2026101607_alert_events"
+	alertOutboxChannelMigration = "2026101608_alert_outbox_channel"
+)
+
+// Rows written before the market matrix belong to the only market collected
+// at the time.
+const (
+	legacyMarketSymbol = "USDT"
+	legacyMarketFiat   = "CNY"
+	legacyForexPair    = "USDCNY"
 )
 
 type SchemaMigrationDAO struct {
@@ -55,6 +72,55 @@ var migrations = []Migration{
 			return tx.AutoMigrate(&AlertBenchmarkOverrideDAO{})
 		},
 	},
+	{
+		Name: roundTripSpreadMigration,
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&RoundTripSpreadDAO{})
+		},
+	},
+	{
+		Name: marketMatrixMigration,
+		Up: func(tx *gorm.DB) error {
+			return migrateToMarketMatrix(tx)
+		},
+	},
+	{
+		Name: payMethodFilterMigration,
+		Up: func(tx *gorm.DB) error {
+			return migrateToPayMethodFilters(tx)
+		},
+	},
+	{
+		Name: merchantReputationMigration,
+		Up: func(tx *gorm.DB) error {
+			// Nullable reputation columns; existing rows have no reported stats.
+			return tx.AutoMigrate(&PricePointDAO{}, &MerchantDAO{})
+		},
+	},
+	{
+		Name: leaderLeaseMigration,
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&LeaderLeaseDAO{})
+		},
+	},
+	{
+		Name: alertOutboxMigration,
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&AlertOutboxDAO{})
+		},
+	},
+	{
+		Name: alertEventsMigration,
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&AlertEventDAO{})
+		},
+	},
+	{
+		Name: alertOutboxChannelMigration,
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&AlertOutboxDAO{})
+		},
+	},
 }
 
 func (r *MySQLRepository) RunMigrations(ctx context.Context) error {
@@ -117,3 +183,62 @@ func createReliabilityIndexes(db *gorm.DB) error {
 	}
 	return nil
 }
+
+// migrateToMarketMatrix scopes alert states and benchmarks to a market: alert
+// states gain symbol/fiat columns, and benchmarks move from the Forex pair key
+// to the USDT/CNY market key.
+func migrateToMarketMatrix(db *gorm.DB) error {
+	if err := db.AutoMigrate(&AlertStateDAO{}); err != nil {
+		return err
+	}
+	if err := db.Model(&AlertStateDAO{}).
+		Where("symbol IS NULL OR symbol = ''").
+		Updates(map[string]any{"symbol": legacyMarketSymbol, "fiat": legacyMarketFiat}).Error; err != nil {
+		return fmt.Errorf("backfill alert state market: %w", err)
+	}
+	if db.Migrator().HasIndex(&AlertStateDAO{}, "idx_alert_state") {
+		if err := db.Migrator().DropIndex(&AlertStateDAO{}, "idx_alert_state"); err != nil {
+			return fmt.Errorf("drop index idx_alert_state: %w", err)
+		}
+	}
+
+	legacyMarket := domain.MarketKey(legacyMarketSymbol, legacyMarketFiat)
+	if err := db.Model(&AlertBenchmarkDAO{}).
+		Where("pair = ?", legacyForexPair).
+		Update("pair", legacyMarket).Error; err != nil {
+		return fmt.Errorf("rename alert benchmark pair: %w", err)
+	}
+	if err := db.Model(&AlertBenchmarkOverrideDAO{}).
+		Where("pair = ?", legacyForexPair).
+		Update("pair", legacyMarket).Error; err != nil {
+		return fmt.Errorf("rename alert benchmark override pair: %w", err)
+	}
+	return nil
+}
+
+// migrateToPayMethodFilters adds the pay_method_filter column to price, bucket
+// and alert state rows. Existing rows become the unfiltered tier (""), and the
+// unique indexes are replaced by ones that include the filter.
+func migrateToPayMethodFilters(db *gorm.DB) error {
+	if err := db.AutoMigrate(&PricePointDAO{}, &C2CPriceHourlyDAO{}, &C2CPriceDailyDAO{}, &AlertStateDAO{}); err != nil {
+		return err
+	}
+
+	legacyIndexes := []struct {
+		model any
+		name  string
+	}{
+		{model: &C2CPriceHourlyDAO{}, name: "idx_c2c_hour"},
+		{model: &C2CPriceDailyDAO{}, name: "idx_c2c_day"},
+		{model: &AlertStateDAO{}, name: "idx_alert_state_market"},
+	}
+	for _, index := range legacyIndexes {
+		if !db.Migrator().HasIndex(index.model, index.name) {
+			continue
+		}
+		if err := db.Migrator().DropIndex(index.model, index.name); err != nil {
+			return fmt.Errorf("drop index %s: %w", index.name, err)
+		}
+	}
+	return nil
+}
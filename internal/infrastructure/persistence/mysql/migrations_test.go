@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"c2c_monitor/internal/domain"
 	"gorm.io/driver/sqlite"
@@ -52,6 +53,7 @@ func TestRunMigrationsCreatesSchemaAndTracksVersion(t *testing.T) {
 	}{
 		{model: &PricePointDAO{}, name: "idx_price_history"},
 		{model: &ForexRateDAO{}, name: "idx_forex_pair_time"},
+		{model: &RoundTripSpreadDAO{}, name: "idx_spread_history"},
 	} {
 		if !db.Migrator().HasIndex(index.model, index.name) {
 			t.Fatalf("expected index %s to exist", index.name)
@@ -59,6 +61,173 @@ func TestRunMigrationsCreatesSchemaAndTracksVersion(t *testing.T) {
 	}
 }
 
+func TestMarketMatrixMigrationScopesLegacyRows(t *testing.T) {
+	db := openMigrationTestDB(t)
+
+	repo := NewMySQLRepository(db)
+	if err := repo.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("RunMigrations returned error: %v", err)
+	}
+
+	// Recreate the pre-matrix state: Forex-pair benchmark keys and alert
+	// states without a market, then replay the migration.
+	if err := db.Create(&AlertBenchmarkDAO{Pair: legacyForexPair, Price: 7.1}).Error; err != nil {
+		t.Fatalf("insert legacy benchmark: %v", err)
+	}
+	if err := db.Create(&AlertBenchmarkOverrideDAO{Pair: legacyForexPair, TargetAmount: 500, Price: 7.0}).Error; err != nil {
+		t.Fatalf("insert legacy override: %v", err)
+	}
+	if err := db.Create(&AlertStateDAO{Exchange: "Gate", Side: "BUY", TargetAmount: 30, TriggerPrice: 7.05}).Error; err != nil {
+		t.Fatalf("insert legacy alert state: %v", err)
+	}
+	if err := db.Where("name = ?", marketMatrixMigration).Delete(&SchemaMigrationDAO{}).Error; err != nil {
+		t.Fatalf("reset migration marker: %v", err)
+	}
+	if err := repo.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("replaying market matrix migration returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	benchmark, err := repo.GetAlertBenchmark(ctx, "USDT/CNY")
+	if err != nil || benchmark == nil || benchmark.Price != 7.1 {
+		t.Fatalf("expected legacy benchmark under USDT/CNY, got %#v err=%v", benchmark, err)
+	}
+	overrides, err := repo.GetAlertBenchmarkOverrides(ctx, "USDT/CNY")
+	if err != nil || len(overrides) != 1 || overrides[0].TargetAmount != 500 {
+		t.Fatalf("expected legacy override under USDT/CNY, got %#v err=%v", overrides, err)
+	}
+	states, err := repo.GetAlertStates(ctx)
+	if err != nil || len(states) != 1 || states[0].Symbol != "USDT" || states[0].Fiat != "CNY" {
+		t.Fatalf("expected legacy alert state to be backfilled, got %#v err=%v", states, err)
+	}
+
+	if err := repo.UpsertAlertState(ctx, &domain.AlertState{Exchange: "Gate", Symbol: "USDC", Fiat: "CNY", Side: "BUY", TargetAmount: 30, TriggerPrice: 7.0}); err != nil {
+		t.Fatalf("expected a second market to coexist with the same exchange/side/amount: %v", err)
+	}
+}
+
+func TestPayMethodFilterMigrationReplacesUniqueIndexes(t *testing.T) {
+	db := openMigrationTestDB(t)
+
+	repo := NewMySQLRepository(db)
+	if err := repo.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("RunMigrations returned error: %v", err)
+	}
+
+	// Recreate the pre-filter unique indexes, then replay the migration.
+	for _, statement := range []string{
+		"CREATE UNIQUE INDEX idx_c2c_hour ON c2c_prices_hourly (bucket_time, exchange, symbol, fiat, side, target_amount, `rank`)",
+		"CREATE UNIQUE INDEX idx_c2c_day ON c2c_prices_daily (bucket_time, exchange, symbol, fiat, side, target_amount, `rank`)",
+		"CREATE UNIQUE INDEX idx_alert_state_market ON alert_states (exchange, symbol, fiat, side, target_amount)",
+	} {
+		if err := db.Exec(statement).Error; err != nil {
+			t.Fatalf("recreate legacy index: %v", err)
+		}
+	}
+	if err := db.Where("name = ?", payMethodFilterMigration).Delete(&SchemaMigrationDAO{}).Error; err != nil {
+		t.Fatalf("reset migration marker: %v", err)
+	}
+	if err := repo.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("replaying pay method filter migration returned error: %v", err)
+	}
+
+	for _, index := range []struct {
+		model any
+		name  string
+	}{
+		{model: &C2CPriceHourlyDAO{}, name: "idx_c2c_hour"},
+		{model: &C2CPriceDailyDAO{}, name: "idx_c2c_day"},
+		{model: &AlertStateDAO{}, name: "idx_alert_state_market"},
+	} {
+		if db.Migrator().HasIndex(index.model, index.name) {
+			t.Fatalf("expected legacy index %s to be dropped", index.name)
+		}
+	}
+
+	ctx := context.Background()
+	for _, payMethod := range []string{"", "银行卡"} {
+		if err := repo.UpsertAlertState(ctx, &domain.AlertState{Exchange: "Gate", Symbol: "USDT", Fiat: "CNY", Side: "BUY", TargetAmount: 30, PayMethodFilter: payMethod, TriggerPrice: 7.0}); err != nil {
+			t.Fatalf("expected pay-method tiers to coexist: %v", err)
+		}
+	}
+	if err := repo.DeleteAlertState(ctx, "Gate", "USDT", "CNY", "BUY", 30, "银行卡"); err != nil {
+		t.Fatalf("DeleteAlertState returned error: %v", err)
+	}
+	states, err := repo.GetAlertStates(ctx)
+	if err != nil || len(states) != 1 || states[0].PayMethodFilter != "" {
+		t.Fatalf("expected only the unfiltered alert state to remain, got %#v err=%v", states, err)
+	}
+}
+
+func TestMerchantReputationMigrationAddsNullableColumns(t *testing.T) {
+	db := openMigrationTestDB(t)
+
+	repo := NewMySQLRepository(db)
+	if err := repo.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("RunMigrations returned error: %v", err)
+	}
+	for _, model := range []any{&PricePointDAO{}, &MerchantDAO{}} {
+		for _, column := range []string{"completion_rate", "month_orders", "verified_merchant", "avg_release_seconds"} {
+			if !db.Migrator().HasColumn(model, column) {
+				t.Fatalf("expected column %s on %T", column, model)
+			}
+		}
+	}
+
+	ctx := context.Background()
+	if err := repo.SaveMerchant(ctx, &domain.Merchant{Exchange: "Gate", MerchantID: "m1", NickName: "new"}); err != nil {
+		t.Fatalf("SaveMerchant returned error: %v", err)
+	}
+	rate, orders := 98.5, 812
+	if err := repo.SaveMerchant(ctx, &domain.Merchant{Exchange: "Gate", MerchantID: "m1", NickName: "established", Reputation: domain.MerchantReputation{CompletionRate: &rate, MonthOrders: &orders}}); err != nil {
+		t.Fatalf("SaveMerchant upsert returned error: %v", err)
+	}
+
+	var merchant MerchantDAO
+	if err := db.Where("exchange = ? AND merchant_id = ?", "Gate", "m1").Take(&merchant).Error; err != nil {
+		t.Fatalf("load merchant: %v", err)
+	}
+	if merchant.CompletionRate == nil || *merchant.CompletionRate != rate || merchant.MonthOrders == nil || *merchant.MonthOrders != orders {
+		t.Fatalf("expected upsert to refresh reputation, got %#v", merchant.ReputationColumns)
+	}
+	if merchant.VerifiedMerchant != nil || merchant.AvgReleaseSeconds != nil {
+		t.Fatalf("expected unreported stats to stay NULL, got %#v", merchant.ReputationColumns)
+	}
+}
+
+func TestRoundTripSpreadPersistence(t *testing.T) {
+	db := openMigrationTestDB(t)
+
+	repo := NewMySQLRepository(db)
+	if err := repo.RunMigrations(context.Background()); err != nil {
+		t.Fatalf("RunMigrations returned error: %v", err)
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(time.Second)
+	if err := repo.SaveRoundTripSpreads(ctx, []*domain.RoundTripSpread{
+		{CreatedAt: now, Symbol: "USDT", Fiat: "CNY", TargetAmount: 30, SellExchange: "OKX", BuyExchange: "Gate", SellPrice: 7.1, BuyPrice: 7.0, Spread: 0.1, SpreadPercent: 1.428571},
+		{CreatedAt: now, Symbol: "USDT", Fiat: "CNY", TargetAmount: 500, SellExchange: "OKX", BuyExchange: "Gate", SellPrice: 7.2, BuyPrice: 7.0, Spread: 0.2, SpreadPercent: 2.857143},
+	}); err != nil {
+		t.Fatalf("SaveRoundTripSpreads returned error: %v", err)
+	}
+
+	amount := 30.0
+	spreads, err := repo.GetRoundTripSpreadHistory(ctx, domain.SpreadQueryFilter{
+		SellExchange: "OKX",
+		Symbol:       "USDT",
+		Fiat:         "CNY",
+		TargetAmount: &amount,
+		StartTime:    now.Add(-time.Minute),
+	})
+	if err != nil {
+		t.Fatalf("GetRoundTripSpreadHistory returned error: %v", err)
+	}
+	if len(spreads) != 1 || spreads[0].BuyExchange != "Gate" || spreads[0].Spread != 0.1 {
+		t.Fatalf("unexpected spread history: %#v", spreads)
+	}
+}
+
 func TestAlertBenchmarkPersistence(t *testing.T) {
 	db := openMigrationTestDB(t)
 
@@ -140,3 +309,131 @@ func openMigrationTestDB(t *testing.T) *gorm.DB {
 	}
 	return db
 }
+
+func TestLeaderLeaseHasOneHolder(t *testing.T) {
+	db := openMigrationTestDB(t)
+
+	repo := NewMySQLRepository(db)
+	ctx := context.Background()
+	if err := repo.RunMigrations(ctx); err != nil {
+		t.Fatalf("RunMigrations returned error: %v", err)
+	}
+
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	ttl := 30 * time.Second
+	for _, step := range []struct {
+		holder string
+		at     time.Time
+		want   bool
+	}{
+		{holder: "replica-a", at: now, want: true},
+		// A renewal that writes unchanged values still holds the lease.
+		{holder: "replica-a", at: now, want: true},
+		{holder: "replica-b", at: now.Add(time.Second), want: false},
+		{holder: "replica-a", at: now.Add(10 * time.Second), want: true},
+		{holder: "replica-b", at: now.Add(39 * time.Second), want: false},
+		{holder: "replica-b", at: now.Add(41 * time.Second), want: true},
+		{holder: "replica-a", at: now.Add(42 * time.Second), want: false},
+	} {
+		acquired, err := repo.AcquireLease(ctx, "c2c-monitor", step.holder, step.at, ttl)
+		if err != nil {
+			t.Fatalf("AcquireLease returned error: %v", err)
+		}
+		if acquired != step.want {
+			t.Fatalf("%s at %s: expected acquired=%v", step.holder, step.at.Sub(now), step.want)
+		}
+	}
+
+	if err := repo.ReleaseLease(ctx, "c2c-monitor", "replica-a"); err != nil {
+		t.Fatalf("ReleaseLease returned error: %v", err)
+	}
+	var lease LeaderLeaseDAO
+	if err := db.Where("name = ?", "c2c-monitor").Take(&lease).Error; err != nil {
+		t.Fatalf("load lease: %v", err)
+	}
+	if lease.Holder != "replica-b" || !lease.ExpiresAt.Equal(now.Add(41*time.Second+ttl)) {
+		t.Fatalf("expected a non-holder release to leave the lease alone, got %#v", lease)
+	}
+	if err := repo.ReleaseLease(ctx, "c2c-monitor", "replica-b"); err != nil {
+		t.Fatalf("ReleaseLease returned error: %v", err)
+	}
+	if acquired, err := repo.AcquireLease(ctx, "c2c-monitor", "replica-a", time.Now().Add(time.Second), ttl); err != nil || !acquired {
+		t.Fatalf("expected a released lease to be free, got %v, %v", acquired, err)
+	}
+}
+
+func TestAlertOutboxPersistence(t *testing.T) {
+	db := openMigrationTestDB(t)
+
+	repo := NewMySQLRepository(db)
+	ctx := context.Background()
+	if err := repo.RunMigrations(ctx); err != nil {
+		t.Fatalf("RunMigrations returned error: %v", err)
+	}
+
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	state := &domain.AlertState{Exchange: domain.ExchangeGate, Symbol: "USDT", Fiat: "CNY", Side: domain.SideBuy, TriggerPrice: 7.0, LastAlertAt: now}
+	alert := &domain.OutboxAlert{CreatedAt: now, Kind: domain.AlertKindPrice, AlertKey: "gate", Subject: "new low", Body: "7.0", Status: domain.AlertPending, NextAttemptAt: now.Add(30 * time.Second), Channel: "email"}
+	record := &domain.AlertRecord{Kind: domain.AlertKindPrice, AlertType: "Initial", Subject: "new low", Exchange: domain.ExchangeGate, Symbol: "USDT", Fiat: "CNY", Merchant: "m1", Price: 7.0, Benchmark: 7.1, ForexRate: 7.2, Channel: "email"}
+	if err := repo.EnqueueAlert(ctx, alert, record, state); err != nil {
+		t.Fatalf("EnqueueAlert returned error: %v", err)
+	}
+	if alert.ID == 0 || record.OutboxID != alert.ID {
+		t.Fatalf("expected EnqueueAlert to assign ids, got alert %d and record outbox %d", alert.ID, record.OutboxID)
+	}
+	states, err := repo.GetAlertStates(ctx)
+	if err != nil || len(states) != 1 || states[0].TriggerPrice != 7.0 {
+		t.Fatalf("expected the alert state to be saved with the alert, got %v, %v", states, err)
+	}
+
+	if due, err := repo.GetDueAlerts(ctx, now, 10); err != nil || len(due) != 0 {
+		t.Fatalf("expected no due alerts before the next attempt, got %v, %v", due, err)
+	}
+	due, err := repo.GetDueAlerts(ctx, now.Add(time.Minute), 10)
+	if err != nil || len(due) != 1 || due[0].Subject != "new low" || due[0].Channel != "email" {
+		t.Fatalf("expected the alert to be due, got %v, %v", due, err)
+	}
+	if claimed, err := repo.ClaimAlert(ctx, alert.ID, now.Add(time.Minute), now.Add(6*time.Minute)); err != nil || !claimed {
+		t.Fatalf("expected the due alert to be claimed, got %v, %v", claimed, err)
+	}
+	if claimed, err := repo.ClaimAlert(ctx, alert.ID, now.Add(time.Minute), now.Add(6*time.Minute)); err != nil || claimed {
+		t.Fatalf("expected a claimed alert not to be claimed twice, got %v, %v", claimed, err)
+	}
+
+	due[0].Status = domain.AlertDead
+	due[0].Attempts = 8
+	due[0].LastError = "SMTP timeout"
+	if err := repo.UpdateAlertDelivery(ctx, due[0]); err != nil {
+		t.Fatalf("UpdateAlertDelivery returned error: %v", err)
+	}
+	dead, err := repo.GetAlertOutbox(ctx, domain.AlertOutboxFilter{Status: domain.AlertDead})
+	if err != nil || len(dead) != 1 || dead[0].Attempts != 8 || dead[0].LastError != "SMTP timeout" {
+		t.Fatalf("expected one dead alert, got %v, %v", dead, err)
+	}
+
+	if requeued, err := repo.RequeueAlert(ctx, alert.ID, now); err != nil || !requeued {
+		t.Fatalf("expected the dead alert to be requeued, got %v, %v", requeued, err)
+	}
+	if requeued, err := repo.RequeueAlert(ctx, alert.ID, now); err != nil || requeued {
+		t.Fatalf("expected a pending alert not to be requeued, got %v, %v", requeued, err)
+	}
+	if due, err := repo.GetDueAlerts(ctx, now, 10); err != nil || len(due) != 1 || due[0].Attempts != 0 {
+		t.Fatalf("expected the requeued alert to be due with a fresh budget, got %v, %v", due, err)
+	}
+	roundTrip := &domain.AlertRecord{Kind: domain.AlertKindRoundTrip, Subject: "round trip", Exchange: domain.ExchangeOKX, SellExchange: domain.ExchangeGate, Symbol: "USDT", Fiat: "CNY", TargetAmount: 1000}
+	if err := repo.EnqueueAlert(ctx, &domain.OutboxAlert{CreatedAt: now.Add(time.Hour), Kind: domain.AlertKindRoundTrip, Status: domain.AlertSent}, roundTrip, nil); err != nil {
+		t.Fatalf("EnqueueAlert returned error: %v", err)
+	}
+
+	history, total, err := repo.GetAlertHistory(ctx, domain.AlertHistoryFilter{Exchange: domain.ExchangeGate, Limit: 1})
+	if err != nil || total != 2 || len(history) != 1 || history[0].Kind != domain.AlertKindRoundTrip || history[0].DeliveryStatus != domain.AlertSent {
+		t.Fatalf("expected the newest of two Gate alerts with its delivery status, got %v, %d, %v", history, total, err)
+	}
+	history, total, err = repo.GetAlertHistory(ctx, domain.AlertHistoryFilter{Kind: domain.AlertKindPrice, EndTime: now.Add(time.Minute)})
+	if err != nil || total != 1 || len(history) != 1 {
+		t.Fatalf("expected one price alert, got %v, %d, %v", history, total, err)
+	}
+	if got := history[0]; got.Merchant != "m1" || got.Benchmark != 7.1 || got.Channel != "email" || got.DeliveryStatus != domain.AlertPending || got.Attempts != 0 || got.DeliveryError != "SMTP timeout" {
+		t.Fatalf("unexpected alert record: %#v", got)
+	}
+}
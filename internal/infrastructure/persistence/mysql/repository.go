@@ -26,22 +26,52 @@ type PricePointDAO struct {
 	MinAmount       float64   `gorm:"type:decimal(18,8)"`
 	MaxAmount       float64   `gorm:"type:decimal(18,8)"`
 	AvailableAmount float64   `gorm:"type:decimal(18,8)"`
+	PayMethodFilter string    `gorm:"type:varchar(32);not null;default:''"`
+	ReputationColumns
+}
+
+// ReputationColumns stores domain.MerchantReputation; NULL means the venue
+// did not report the stat.
+type ReputationColumns struct {
+	CompletionRate    *float64 `gorm:"type:decimal(6,2)"`
+	MonthOrders       *int
+	VerifiedMerchant  *bool
+	AvgReleaseSeconds *float64 `gorm:"type:decimal(10,2)"`
+}
+
+func reputationColumns(reputation domain.MerchantReputation) ReputationColumns {
+	return ReputationColumns{
+		CompletionRate:    reputation.CompletionRate,
+		MonthOrders:       reputation.MonthOrders,
+		VerifiedMerchant:  reputation.VerifiedMerchant,
+		AvgReleaseSeconds: reputation.AvgReleaseSeconds,
+	}
+}
+
+func (c ReputationColumns) toDomain() domain.MerchantReputation {
+	return domain.MerchantReputation{
+		CompletionRate:    c.CompletionRate,
+		MonthOrders:       c.MonthOrders,
+		VerifiedMerchant:  c.VerifiedMerchant,
+		AvgReleaseSeconds: c.AvgReleaseSeconds,
+	}
 }
 
 func (PricePointDAO) TableName() string {
 	return "c2c_prices"
 }
 
-// C2CPriceHourlyDAO stores hourly aggregated lowest prices.
+// C2CPriceHourlyDAO stores hourly aggregated best prices.
 type C2CPriceHourlyDAO struct {
 	ID              int64     `gorm:"primaryKey;autoIncrement"`
-	BucketTime      time.Time `gorm:"uniqueIndex:idx_c2c_hour,priority:1;index"`
-	Exchange        string    `gorm:"type:varchar(32);uniqueIndex:idx_c2c_hour,priority:2;index"`
-	Symbol          string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_hour,priority:3"`
-	Fiat            string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_hour,priority:4"`
-	Side            string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_hour,priority:5;index"`
-	TargetAmount    float64   `gorm:"uniqueIndex:idx_c2c_hour,priority:6;index"`
-	Rank            int       `gorm:"uniqueIndex:idx_c2c_hour,priority:7;index"`
+	BucketTime      time.Time `gorm:"uniqueIndex:idx_c2c_hour_tier,priority:1;index"`
+	Exchange        string    `gorm:"type:varchar(32);uniqueIndex:idx_c2c_hour_tier,priority:2;index"`
+	Symbol          string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_hour_tier,priority:3"`
+	Fiat            string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_hour_tier,priority:4"`
+	Side            string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_hour_tier,priority:5;index"`
+	TargetAmount    float64   `gorm:"uniqueIndex:idx_c2c_hour_tier,priority:6;index"`
+	Rank            int       `gorm:"uniqueIndex:idx_c2c_hour_tier,priority:7;index"`
+	PayMethodFilter string    `gorm:"type:varchar(32);not null;default:'';uniqueIndex:idx_c2c_hour_tier,priority:8"`
 	RawID           int64     `gorm:"index"`
 	Price           float64   `gorm:"type:decimal(18,8)"`
 	Merchant        string    `gorm:"type:varchar(128)"`
@@ -58,16 +88,17 @@ func (C2CPriceHourlyDAO) TableName() string {
 	return "c2c_prices_hourly"
 }
 
-// C2CPriceDailyDAO stores daily aggregated lowest prices.
+// C2CPriceDailyDAO stores daily aggregated best prices.
 type C2CPriceDailyDAO struct {
 	ID              int64     `gorm:"primaryKey;autoIncrement"`
-	BucketTime      time.Time `gorm:"uniqueIndex:idx_c2c_day,priority:1;index"`
-	Exchange        string    `gorm:"type:varchar(32);uniqueIndex:idx_c2c_day,priority:2;index"`
-	Symbol          string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_day,priority:3"`
-	Fiat            string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_day,priority:4"`
-	Side            string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_day,priority:5;index"`
-	TargetAmount    float64   `gorm:"uniqueIndex:idx_c2c_day,priority:6;index"`
-	Rank            int       `gorm:"uniqueIndex:idx_c2c_day,priority:7;index"`
+	BucketTime      time.Time `gorm:"uniqueIndex:idx_c2c_day_tier,priority:1;index"`
+	Exchange        string    `gorm:"type:varchar(32);uniqueIndex:idx_c2c_day_tier,priority:2;index"`
+	Symbol          string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_day_tier,priority:3"`
+	Fiat            string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_day_tier,priority:4"`
+	Side            string    `gorm:"type:varchar(10);uniqueIndex:idx_c2c_day_tier,priority:5;index"`
+	TargetAmount    float64   `gorm:"uniqueIndex:idx_c2c_day_tier,priority:6;index"`
+	Rank            int       `gorm:"uniqueIndex:idx_c2c_day_tier,priority:7;index"`
+	PayMethodFilter string    `gorm:"type:varchar(32);not null;default:'';uniqueIndex:idx_c2c_day_tier,priority:8"`
 	RawID           int64     `gorm:"index"`
 	Price           float64   `gorm:"type:decimal(18,8)"`
 	Merchant        string    `gorm:"type:varchar(128)"`
@@ -84,14 +115,34 @@ func (C2CPriceDailyDAO) TableName() string {
 	return "c2c_prices_daily"
 }
 
+// RoundTripSpreadDAO stores cross-exchange SELL-minus-BUY spreads per round.
+type RoundTripSpreadDAO struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement"`
+	CreatedAt     time.Time `gorm:"index:idx_spread_history,priority:6"`
+	SellExchange  string    `gorm:"type:varchar(32);index:idx_spread_history,priority:1"`
+	BuyExchange   string    `gorm:"type:varchar(32);index:idx_spread_history,priority:2"`
+	Symbol        string    `gorm:"type:varchar(10);index:idx_spread_history,priority:3"`
+	Fiat          string    `gorm:"type:varchar(10);index:idx_spread_history,priority:4"`
+	TargetAmount  float64   `gorm:"index:idx_spread_history,priority:5"`
+	SellPrice     float64   `gorm:"type:decimal(18,8)"`
+	BuyPrice      float64   `gorm:"type:decimal(18,8)"`
+	Spread        float64   `gorm:"type:decimal(18,8)"`
+	SpreadPercent float64   `gorm:"type:decimal(12,6)"`
+}
+
+func (RoundTripSpreadDAO) TableName() string {
+	return "c2c_round_trip_spreads"
+}
+
 // MerchantDAO represents the database schema for merchants
 type MerchantDAO struct {
 	ID         int64  `gorm:"primaryKey;autoIncrement"`
 	Exchange   string `gorm:"type:varchar(32);uniqueIndex:idx_merchant"`
 	MerchantID string `gorm:"type:varchar(64);uniqueIndex:idx_merchant"`
 	NickName   string `gorm:"type:varchar(128)"`
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
+	ReputationColumns
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 func (MerchantDAO) TableName() string {
@@ -143,21 +194,24 @@ func (ForexRateDailyDAO) TableName() string {
 
 // AlertStateDAO stores dynamic alert thresholds for restart recovery.
 type AlertStateDAO struct {
-	ID           int64     `gorm:"primaryKey;autoIncrement"`
-	Exchange     string    `gorm:"type:varchar(32);uniqueIndex:idx_alert_state,priority:1"`
-	Side         string    `gorm:"type:varchar(10);uniqueIndex:idx_alert_state,priority:2"`
-	TargetAmount float64   `gorm:"type:decimal(18,8);uniqueIndex:idx_alert_state,priority:3"`
-	TriggerPrice float64   `gorm:"type:decimal(18,8)"`
-	LastAlertAt  time.Time `gorm:"index"`
-	CreatedAt    time.Time
-	UpdatedAt    time.Time
+	ID              int64     `gorm:"primaryKey;autoIncrement"`
+	Exchange        string    `gorm:"type:varchar(32);uniqueIndex:idx_alert_state_tier,priority:1"`
+	Symbol          string    `gorm:"type:varchar(10);uniqueIndex:idx_alert_state_tier,priority:2"`
+	Fiat            string    `gorm:"type:varchar(10);uniqueIndex:idx_alert_state_tier,priority:3"`
+	Side            string    `gorm:"type:varchar(10);uniqueIndex:idx_alert_state_tier,priority:4"`
+	TargetAmount    float64   `gorm:"type:decimal(18,8);uniqueIndex:idx_alert_state_tier,priority:5"`
+	PayMethodFilter string    `gorm:"type:varchar(32);not null;default:'';uniqueIndex:idx_alert_state_tier,priority:6"`
+	TriggerPrice    float64   `gorm:"type:decimal(18,8)"`
+	LastAlertAt     time.Time `gorm:"index"`
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
 func (AlertStateDAO) TableName() string {
 	return "alert_states"
 }
 
-// AlertBenchmarkDAO stores the global alert benchmark across restarts.
+// AlertBenchmarkDAO stores each market's default alert benchmark across restarts.
 type AlertBenchmarkDAO struct {
 	Pair      string  `gorm:"primaryKey;type:varchar(10)"`
 	Price     float64 `gorm:"type:decimal(18,8)"`
@@ -182,7 +236,115 @@ func (AlertBenchmarkOverrideDAO) TableName() string {
 	return "alert_benchmark_overrides"
 }
 
-// MySQLRepository implements domain.IRepository
+// AlertOutboxDAO stores notifications until they are delivered or given up.
+type AlertOutboxDAO struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement"`
+	CreatedAt     time.Time `gorm:"index"`
+	Kind          string    `gorm:"type:varchar(16)"`
+	AlertKey      string    `gorm:"type:varchar(255)"`
+	Subject       string    `gorm:"type:text"`
+	Body          string    `gorm:"type:mediumtext"`
+	Status        string    `gorm:"type:varchar(16);index:idx_alert_outbox_due,priority:1"`
+	Attempts      int
+	NextAttemptAt time.Time `gorm:"index:idx_alert_outbox_due,priority:2"`
+	LastError     string    `gorm:"type:text"`
+	SentAt        *time.Time
+	Channel       string `gorm:"type:varchar(32)"`
+	UpdatedAt     time.Time
+}
+
+func (AlertOutboxDAO) TableName() string {
+	return "alert_outbox"
+}
+
+func (d AlertOutboxDAO) toDomain() *domain.OutboxAlert {
+	return &domain.OutboxAlert{
+		ID:            d.ID,
+		CreatedAt:     d.CreatedAt,
+		Kind:          d.Kind,
+		AlertKey:      d.AlertKey,
+		Subject:       d.Subject,
+		Body:          d.Body,
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		LastError:     d.LastError,
+		SentAt:        d.SentAt,
+		Channel:       d.Channel,
+	}
+}
+
+// AlertEventDAO is the history of alerts; delivery results live in the outbox
+// row it points to.
+type AlertEventDAO struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement"`
+	CreatedAt       time.Time `gorm:"index"`
+	OutboxID        int64     `gorm:"index"`
+	Kind            string    `gorm:"type:varchar(16);index"`
+	AlertType       string    `gorm:"type:varchar(16)"`
+	Subject         string    `gorm:"type:text"`
+	Exchange        string    `gorm:"type:varchar(64);index"`
+	SellExchange    string    `gorm:"type:varchar(64)"`
+	Symbol          string    `gorm:"type:varchar(10)"`
+	Fiat            string    `gorm:"type:varchar(10)"`
+	Side            string    `gorm:"type:varchar(10)"`
+	TargetAmount    float64
+	PayMethodFilter string  `gorm:"type:varchar(32)"`
+	Merchant        string  `gorm:"type:varchar(128)"`
+	Price           float64 `gorm:"type:decimal(18,8)"`
+	SellPrice       float64 `gorm:"type:decimal(18,8)"`
+	Benchmark       float64 `gorm:"type:decimal(18,8)"`
+	ForexRate       float64 `gorm:"type:decimal(18,8)"`
+	SpreadPercent   float64 `gorm:"type:decimal(12,6)"`
+	Details         string  `gorm:"type:text"`
+	Channel         string  `gorm:"type:varchar(64)"`
+}
+
+func (AlertEventDAO) TableName() string {
+	return "alert_events"
+}
+
+func (d AlertEventDAO) toDomain() *domain.AlertRecord {
+	return &domain.AlertRecord{
+		ID:              d.ID,
+		CreatedAt:       d.CreatedAt,
+		OutboxID:        d.OutboxID,
+		Kind:            d.Kind,
+		AlertType:       d.AlertType,
+		Subject:         d.Subject,
+		Exchange:        d.Exchange,
+		SellExchange:    d.SellExchange,
+		Symbol:          d.Symbol,
+		Fiat:            d.Fiat,
+		Side:            d.Side,
+		TargetAmount:    d.TargetAmount,
+		PayMethodFilter: d.PayMethodFilter,
+		Merchant:        d.Merchant,
+		Price:           d.Price,
+		SellPrice:       d.SellPrice,
+		Benchmark:       d.Benchmark,
+		ForexRate:       d.ForexRate,
+		SpreadPercent:   d.SpreadPercent,
+		Details:         d.Details,
+		Channel:         d.Channel,
+	}
+}
+
+// LeaderLeaseDAO is a named lease shared by replicas; Holder may run the
+// collection loops until ExpiresAt.
+type LeaderLeaseDAO struct {
+	Name      string    `gorm:"primaryKey;type:varchar(64)"`
+	Holder    string    `gorm:"type:varchar(128)"`
+	ExpiresAt time.Time `gorm:"index"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (LeaderLeaseDAO) TableName() string {
+	return "leader_leases"
+}
+
+// MySQLRepository implements domain.IRepository and domain.ILeaderLease
 type MySQLRepository struct {
 	db *gorm.DB
 }
@@ -204,6 +366,10 @@ func schemaModels() []any {
 		&AlertStateDAO{},
 		&AlertBenchmarkDAO{},
 		&AlertBenchmarkOverrideDAO{},
+		&RoundTripSpreadDAO{},
+		&LeaderLeaseDAO{},
+		&AlertOutboxDAO{},
+		&AlertEventDAO{},
 	}
 }
 
@@ -255,19 +421,21 @@ func (r *MySQLRepository) SavePricePoints(ctx context.Context, points []*domain.
 		daos := make([]*PricePointDAO, len(points))
 		for i, p := range points {
 			daos[i] = &PricePointDAO{
-				CreatedAt:       p.CreatedAt,
-				Exchange:        p.Exchange,
-				Symbol:          p.Symbol,
-				Fiat:            p.Fiat,
-				Side:            p.Side,
-				TargetAmount:    p.TargetAmount,
-				Rank:            p.Rank,
-				Price:           p.Price,
-				MerchantID:      p.MerchantID,
-				PayMethods:      p.PayMethods,
-				MinAmount:       p.MinAmount,
-				MaxAmount:       p.MaxAmount,
-				AvailableAmount: p.AvailableAmount,
+				CreatedAt:         p.CreatedAt,
+				Exchange:          p.Exchange,
+				Symbol:            p.Symbol,
+				Fiat:              p.Fiat,
+				Side:              p.Side,
+				TargetAmount:      p.TargetAmount,
+				Rank:              p.Rank,
+				Price:             p.Price,
+				MerchantID:        p.MerchantID,
+				PayMethods:        p.PayMethods,
+				MinAmount:         p.MinAmount,
+				MaxAmount:         p.MaxAmount,
+				AvailableAmount:   p.AvailableAmount,
+				PayMethodFilter:   p.PayMethodFilter,
+				ReputationColumns: reputationColumns(p.Reputation),
 			}
 		}
 		if err := tx.Create(daos).Error; err != nil {
@@ -308,6 +476,7 @@ func upsertC2CAggregate(tx *gorm.DB, p *domain.PricePoint, rawID int64, granular
 			MinAmount:       p.MinAmount,
 			MaxAmount:       p.MaxAmount,
 			AvailableAmount: p.AvailableAmount,
+			PayMethodFilter: p.PayMethodFilter,
 			CreatedAt:       bucket,
 		}
 		return tx.Clauses(clause.OnConflict{
@@ -319,8 +488,9 @@ func upsertC2CAggregate(tx *gorm.DB, p *domain.PricePoint, rawID int64, granular
 				{Name: "side"},
 				{Name: "target_amount"},
 				{Name: "rank"},
+				{Name: "pay_method_filter"},
 			},
-			DoUpdates: clause.Assignments(lowestPriceSnapshotAssignments()),
+			DoUpdates: clause.Assignments(bestPriceSnapshotAssignments()),
 		}).Create(dao).Error
 	case domain.HistoryGranularityDay:
 		dao := &C2CPriceDailyDAO{
@@ -339,6 +509,7 @@ func upsertC2CAggregate(tx *gorm.DB, p *domain.PricePoint, rawID int64, granular
 			MinAmount:       p.MinAmount,
 			MaxAmount:       p.MaxAmount,
 			AvailableAmount: p.AvailableAmount,
+			PayMethodFilter: p.PayMethodFilter,
 			CreatedAt:       bucket,
 		}
 		return tx.Clauses(clause.OnConflict{
@@ -350,24 +521,28 @@ func upsertC2CAggregate(tx *gorm.DB, p *domain.PricePoint, rawID int64, granular
 				{Name: "side"},
 				{Name: "target_amount"},
 				{Name: "rank"},
+				{Name: "pay_method_filter"},
 			},
-			DoUpdates: clause.Assignments(lowestPriceSnapshotAssignments()),
+			DoUpdates: clause.Assignments(bestPriceSnapshotAssignments()),
 		}).Create(dao).Error
 	default:
 		return nil
 	}
 }
 
-func lowestPriceSnapshotAssignments() map[string]interface{} {
+// bestPriceSnapshotAssignments keeps the best ad seen in a bucket: the lowest
+// price for BUY rows and the highest price for SELL rows.
+func bestPriceSnapshotAssignments() map[string]interface{} {
+	const better = "((VALUES(side) = 'SELL' AND VALUES(price) >= price) OR (VALUES(side) <> 'SELL' AND VALUES(price) <= price))"
 	return map[string]interface{}{
-		"price":            gorm.Expr("LEAST(price, VALUES(price))"),
-		"raw_id":           gorm.Expr("IF(VALUES(price) <= price, VALUES(raw_id), raw_id)"),
-		"merchant":         gorm.Expr("IF(VALUES(price) <= price, VALUES(merchant), merchant)"),
-		"merchant_id":      gorm.Expr("IF(VALUES(price) <= price, VALUES(merchant_id), merchant_id)"),
-		"pay_methods":      gorm.Expr("IF(VALUES(price) <= price, VALUES(pay_methods), pay_methods)"),
-		"min_amount":       gorm.Expr("IF(VALUES(price) <= price, VALUES(min_amount), min_amount)"),
-		"max_amount":       gorm.Expr("IF(VALUES(price) <= price, VALUES(max_amount), max_amount)"),
-		"available_amount": gorm.Expr("IF(VALUES(price) <= price, VALUES(available_amount), available_amount)"),
+		"price":            gorm.Expr("IF(VALUES(side) = 'SELL', GREATEST(price, VALUES(price)), LEAST(price, VALUES(price)))"),
+		"raw_id":           gorm.Expr("IF(" + better + ", VALUES(raw_id), raw_id)"),
+		"merchant":         gorm.Expr("IF(" + better + ", VALUES(merchant), merchant)"),
+		"merchant_id":      gorm.Expr("IF(" + better + ", VALUES(merchant_id), merchant_id)"),
+		"pay_methods":      gorm.Expr("IF(" + better + ", VALUES(pay_methods), pay_methods)"),
+		"min_amount":       gorm.Expr("IF(" + better + ", VALUES(min_amount), min_amount)"),
+		"max_amount":       gorm.Expr("IF(" + better + ", VALUES(max_amount), max_amount)"),
+		"available_amount": gorm.Expr("IF(" + better + ", VALUES(available_amount), available_amount)"),
 		"updated_at":       gorm.Expr("NOW()"),
 	}
 }
@@ -398,8 +573,10 @@ func (r *MySQLRepository) getPriceHistoryFromTable(ctx context.Context, filter d
 		MinAmount       float64   `gorm:"column:min_amount"`
 		MaxAmount       float64   `gorm:"column:max_amount"`
 		AvailableAmount float64   `gorm:"column:available_amount"`
+		PayMethodFilter string    `gorm:"column:pay_method_filter"`
 		Merchant        string    `gorm:"column:merchant"`
 		NickName        string    `gorm:"column:nick_name"`
+		ReputationColumns
 	}
 
 	var rows []resultRow
@@ -427,6 +604,7 @@ func (r *MySQLRepository) getPriceHistoryFromTable(ctx context.Context, filter d
 	if filter.TargetAmount != nil {
 		query = query.Where(tableName+".target_amount = ?", *filter.TargetAmount)
 	}
+	query = query.Where(tableName+".pay_method_filter = ?", filter.PayMethodFilter)
 	if filter.Rank > 0 {
 		query = query.Where(tableName+".`rank` = ?", filter.Rank)
 	}
@@ -469,7 +647,87 @@ func (r *MySQLRepository) getPriceHistoryFromTable(ctx context.Context, filter d
 			MinAmount:       row.MinAmount,
 			MaxAmount:       row.MaxAmount,
 			AvailableAmount: row.AvailableAmount,
+			PayMethodFilter: row.PayMethodFilter,
 			Merchant:        merchant,
+			Reputation:      row.ReputationColumns.toDomain(),
+		}
+	}
+	return results, nil
+}
+
+// --- Round-Trip Spread Operations ---
+
+func (r *MySQLRepository) SaveRoundTripSpreads(ctx context.Context, spreads []*domain.RoundTripSpread) error {
+	if len(spreads) == 0 {
+		return nil
+	}
+
+	daos := make([]*RoundTripSpreadDAO, len(spreads))
+	for i, spread := range spreads {
+		daos[i] = &RoundTripSpreadDAO{
+			CreatedAt:     spread.CreatedAt,
+			SellExchange:  spread.SellExchange,
+			BuyExchange:   spread.BuyExchange,
+			Symbol:        spread.Symbol,
+			Fiat:          spread.Fiat,
+			TargetAmount:  spread.TargetAmount,
+			SellPrice:     spread.SellPrice,
+			BuyPrice:      spread.BuyPrice,
+			Spread:        spread.Spread,
+			SpreadPercent: spread.SpreadPercent,
+		}
+	}
+	return r.db.WithContext(ctx).Create(daos).Error
+}
+
+func (r *MySQLRepository) GetRoundTripSpreadHistory(ctx context.Context, filter domain.SpreadQueryFilter) ([]*domain.RoundTripSpread, error) {
+	query := r.db.WithContext(ctx).Model(&RoundTripSpreadDAO{})
+	if filter.SellExchange != "" {
+		query = query.Where("sell_exchange = ?", filter.SellExchange)
+	}
+	if filter.BuyExchange != "" {
+		query = query.Where("buy_exchange = ?", filter.BuyExchange)
+	}
+	if filter.Symbol != "" {
+		query = query.Where("symbol = ?", filter.Symbol)
+	}
+	if filter.Fiat != "" {
+		query = query.Where("fiat = ?", filter.Fiat)
+	}
+	if filter.TargetAmount != nil {
+		query = query.Where("target_amount = ?", *filter.TargetAmount)
+	}
+	if !filter.StartTime.IsZero() {
+		query = query.Where("created_at >= ?", filter.StartTime)
+	}
+	if !filter.EndTime.IsZero() {
+		query = query.Where("created_at <= ?", filter.EndTime)
+	}
+
+	query = query.Order("created_at ASC")
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var daos []RoundTripSpreadDAO
+	if err := query.Find(&daos).Error; err != nil {
+		return nil, err
+	}
+
+	results := make([]*domain.RoundTripSpread, len(daos))
+	for i, dao := range daos {
+		results[i] = &domain.RoundTripSpread{
+			ID:            dao.ID,
+			CreatedAt:     dao.CreatedAt,
+			Symbol:        dao.Symbol,
+			Fiat:          dao.Fiat,
+			TargetAmount:  dao.TargetAmount,
+			BuyExchange:   dao.BuyExchange,
+			SellExchange:  dao.SellExchange,
+			BuyPrice:      dao.BuyPrice,
+			SellPrice:     dao.SellPrice,
+			Spread:        dao.Spread,
+			SpreadPercent: dao.SpreadPercent,
 		}
 	}
 	return results, nil
@@ -479,17 +737,18 @@ func (r *MySQLRepository) getPriceHistoryFromTable(ctx context.Context, filter d
 
 func (r *MySQLRepository) SaveMerchant(ctx context.Context, m *domain.Merchant) error {
 	dao := &MerchantDAO{
-		Exchange:   m.Exchange,
-		MerchantID: m.MerchantID,
-		NickName:   m.NickName,
-		CreatedAt:  m.CreatedAt,
-		UpdatedAt:  m.UpdatedAt,
+		Exchange:          m.Exchange,
+		MerchantID:        m.MerchantID,
+		NickName:          m.NickName,
+		ReputationColumns: reputationColumns(m.Reputation),
+		CreatedAt:         m.CreatedAt,
+		UpdatedAt:         m.UpdatedAt,
 	}
 
 	// Upsert based on (exchange, merchant_id)
 	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
 		Columns:   []clause.Column{{Name: "exchange"}, {Name: "merchant_id"}},
-		DoUpdates: clause.AssignmentColumns([]string{"nick_name", "updated_at"}),
+		DoUpdates: clause.AssignmentColumns([]string{"nick_name", "completion_rate", "month_orders", "verified_merchant", "avg_release_seconds", "updated_at"}),
 	}).Create(dao).Error
 }
 
@@ -630,27 +889,37 @@ func upsertForexAggregate(tx *gorm.DB, rate *domain.ForexRate, granularity domai
 // --- Alert State Operations ---
 
 func (r *MySQLRepository) UpsertAlertState(ctx context.Context, state *domain.AlertState) error {
+	return upsertAlertState(r.db.WithContext(ctx), state)
+}
+
+func upsertAlertState(db *gorm.DB, state *domain.AlertState) error {
 	dao := &AlertStateDAO{
-		Exchange:     state.Exchange,
-		Side:         state.Side,
-		TargetAmount: state.TargetAmount,
-		TriggerPrice: state.TriggerPrice,
-		LastAlertAt:  state.LastAlertAt,
+		Exchange:        state.Exchange,
+		Symbol:          state.Symbol,
+		Fiat:            state.Fiat,
+		Side:            state.Side,
+		TargetAmount:    state.TargetAmount,
+		PayMethodFilter: state.PayMethodFilter,
+		TriggerPrice:    state.TriggerPrice,
+		LastAlertAt:     state.LastAlertAt,
 	}
 
-	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+	return db.Clauses(clause.OnConflict{
 		Columns: []clause.Column{
 			{Name: "exchange"},
+			{Name: "symbol"},
+			{Name: "fiat"},
 			{Name: "side"},
 			{Name: "target_amount"},
+			{Name: "pay_method_filter"},
 		},
 		DoUpdates: clause.AssignmentColumns([]string{"trigger_price", "last_alert_at", "updated_at"}),
 	}).Create(dao).Error
 }
 
-func (r *MySQLRepository) DeleteAlertState(ctx context.Context, exchange, side string, amount float64) error {
+func (r *MySQLRepository) DeleteAlertState(ctx context.Context, exchange, symbol, fiat, side string, amount float64, payMethod string) error {
 	return r.db.WithContext(ctx).
-		Where("exchange = ? AND side = ? AND target_amount = ?", exchange, side, amount).
+		Where("exchange = ? AND symbol = ? AND fiat = ? AND side = ? AND target_amount = ? AND pay_method_filter = ?", exchange, symbol, fiat, side, amount, payMethod).
 		Delete(&AlertStateDAO{}).Error
 }
 
@@ -663,14 +932,17 @@ func (r *MySQLRepository) GetAlertStates(ctx context.Context) ([]*domain.AlertSt
 	results := make([]*domain.AlertState, len(daos))
 	for i, d := range daos {
 		results[i] = &domain.AlertState{
-			ID:           d.ID,
-			Exchange:     d.Exchange,
-			Side:         d.Side,
-			TargetAmount: d.TargetAmount,
-			TriggerPrice: d.TriggerPrice,
-			LastAlertAt:  d.LastAlertAt,
-			CreatedAt:    d.CreatedAt,
-			UpdatedAt:    d.UpdatedAt,
+			ID:              d.ID,
+			Exchange:        d.Exchange,
+			Symbol:          d.Symbol,
+			Fiat:            d.Fiat,
+			Side:            d.Side,
+			TargetAmount:    d.TargetAmount,
+			PayMethodFilter: d.PayMethodFilter,
+			TriggerPrice:    d.TriggerPrice,
+			LastAlertAt:     d.LastAlertAt,
+			CreatedAt:       d.CreatedAt,
+			UpdatedAt:       d.UpdatedAt,
 		}
 	}
 
@@ -741,3 +1013,250 @@ func (r *MySQLRepository) GetAlertBenchmarkOverrides(ctx context.Context, pair s
 	}
 	return results, nil
 }
+
+// --- Alert Outbox Operations ---
+
+func (r *MySQLRepository) EnqueueAlert(ctx context.Context, alert *domain.OutboxAlert, record *domain.AlertRecord, state *domain.AlertState) error {
+	dao := &AlertOutboxDAO{
+		CreatedAt:     alert.CreatedAt,
+		Kind:          alert.Kind,
+		AlertKey:      alert.AlertKey,
+		Subject:       alert.Subject,
+		Body:          alert.Body,
+		Status:        alert.Status,
+		Attempts:      alert.Attempts,
+		NextAttemptAt: alert.NextAttemptAt,
+		LastError:     alert.LastError,
+		SentAt:        alert.SentAt,
+		Channel:       alert.Channel,
+	}
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if state != nil {
+			if err := upsertAlertState(tx, state); err != nil {
+				return err
+			}
+		}
+		if err := tx.Create(dao).Error; err != nil {
+			return err
+		}
+		if record != nil {
+			event := &AlertEventDAO{
+				CreatedAt:       alert.CreatedAt,
+				OutboxID:        dao.ID,
+				Kind:            record.Kind,
+				AlertType:       record.AlertType,
+				Subject:         record.Subject,
+				Exchange:        record.Exchange,
+				SellExchange:    record.SellExchange,
+				Symbol:          record.Symbol,
+				Fiat:            record.Fiat,
+				Side:            record.Side,
+				TargetAmount:    record.TargetAmount,
+				PayMethodFilter: record.PayMethodFilter,
+				Merchant:        record.Merchant,
+				Price:           record.Price,
+				SellPrice:       record.SellPrice,
+				Benchmark:       record.Benchmark,
+				ForexRate:       record.ForexRate,
+				SpreadPercent:   record.SpreadPercent,
+				Details:         record.Details,
+				Channel:         record.Channel,
+			}
+			if err := tx.Create(event).Error; err != nil {
+				return err
+			}
+			record.ID = event.ID
+			record.OutboxID = dao.ID
+		}
+		alert.ID = dao.ID
+		return nil
+	})
+}
+
+func (r *MySQLRepository) GetDueAlerts(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxAlert, error) {
+	var daos []AlertOutboxDAO
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", domain.AlertPending, now).
+		Order("next_attempt_at ASC, id ASC").
+		Limit(limit).
+		Find(&daos).Error; err != nil {
+		return nil, err
+	}
+	return alertOutboxToDomain(daos), nil
+}
+
+func (r *MySQLRepository) ClaimAlert(ctx context.Context, id int64, now, until time.Time) (bool, error) {
+	// until is after now, so a successful claim always changes the row and
+	// MySQL's changed-rows count is reliable here.
+	result := r.db.WithContext(ctx).Model(&AlertOutboxDAO{}).
+		Where("id = ? AND status = ? AND next_attempt_at <= ?", id, domain.AlertPending, now).
+		Update("next_attempt_at", until)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *MySQLRepository) UpdateAlertDelivery(ctx context.Context, alert *domain.OutboxAlert) error {
+	return r.db.WithContext(ctx).Model(&AlertOutboxDAO{}).
+		Where("id = ?", alert.ID).
+		Updates(map[string]any{
+			"status":          alert.Status,
+			"attempts":        alert.Attempts,
+			"next_attempt_at": alert.NextAttemptAt,
+			"last_error":      alert.LastError,
+			"sent_at":         alert.SentAt,
+		}).Error
+}
+
+func (r *MySQLRepository) GetAlertOutbox(ctx context.Context, filter domain.AlertOutboxFilter) ([]*domain.OutboxAlert, error) {
+	query := r.db.WithContext(ctx).Order("id DESC")
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var daos []AlertOutboxDAO
+	if err := query.Find(&daos).Error; err != nil {
+		return nil, err
+	}
+	return alertOutboxToDomain(daos), nil
+}
+
+func (r *MySQLRepository) RequeueAlert(ctx context.Context, id int64, now time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&AlertOutboxDAO{}).
+		Where("id = ? AND status = ?", id, domain.AlertDead).
+		Updates(map[string]any{"status": domain.AlertPending, "attempts": 0, "next_attempt_at": now})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *MySQLRepository) GetAlertHistory(ctx context.Context, filter domain.AlertHistoryFilter) ([]*domain.AlertRecord, int64, error) {
+	query := r.db.WithContext(ctx).Model(&AlertEventDAO{})
+	if filter.Kind != "" {
+		query = query.Where("kind = ?", filter.Kind)
+	}
+	if filter.Exchange != "" {
+		query = query.Where("(exchange = ? OR sell_exchange = ?)", filter.Exchange, filter.Exchange)
+	}
+	if filter.Symbol != "" {
+		query = query.Where("symbol = ? AND fiat = ?", filter.Symbol, filter.Fiat)
+	}
+	if filter.TargetAmount != nil {
+		query = query.Where("target_amount = ?", *filter.TargetAmount)
+	}
+	if !filter.StartTime.IsZero() {
+		query = query.Where("created_at >= ?", filter.StartTime)
+	}
+	if !filter.EndTime.IsZero() {
+		query = query.Where("created_at <= ?", filter.EndTime)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	query = query.Order("id DESC").Offset(filter.Offset)
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	var daos []AlertEventDAO
+	if err := query.Find(&daos).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Delivery results are read from the outbox rather than copied, so the
+	// history never disagrees with the queue.
+	outboxIDs := make([]int64, 0, len(daos))
+	for _, dao := range daos {
+		outboxIDs = append(outboxIDs, dao.OutboxID)
+	}
+	var deliveries []AlertOutboxDAO
+	if len(outboxIDs) > 0 {
+		if err := r.db.WithContext(ctx).
+			Select("id", "status", "attempts", "last_error", "sent_at").
+			Where("id IN ?", outboxIDs).
+			Find(&deliveries).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+	byID := make(map[int64]AlertOutboxDAO, len(deliveries))
+	for _, delivery := range deliveries {
+		byID[delivery.ID] = delivery
+	}
+
+	results := make([]*domain.AlertRecord, len(daos))
+	for i, dao := range daos {
+		record := dao.toDomain()
+		if delivery, ok := byID[dao.OutboxID]; ok {
+			record.DeliveryStatus = delivery.Status
+			record.Attempts = delivery.Attempts
+			record.DeliveryError = delivery.LastError
+			record.DeliveredAt = delivery.SentAt
+		}
+		results[i] = record
+	}
+	return results, total, nil
+}
+
+func alertOutboxToDomain(daos []AlertOutboxDAO) []*domain.OutboxAlert {
+	results := make([]*domain.OutboxAlert, len(daos))
+	for i, dao := range daos {
+		results[i] = dao.toDomain()
+	}
+	return results
+}
+
+// --- Leader Lease Operations ---
+
+// AcquireLease takes the lease when it is free or expired, or renews it when
+// holder already owns it. The conditional update is atomic, so two replicas
+// racing for an expired lease cannot both win. On MySQL, expiry is judged by
+// the database clock, so replicas with skewed clocks agree on it; now is only
+// used by other databases, such as SQLite in tests.
+//
+// The holder is read back instead of trusting RowsAffected, which MySQL
+// reports as changed rows: a renewal that writes the same values changes none.
+func (r *MySQLRepository) AcquireLease(ctx context.Context, name, holder string, now time.Time, ttl time.Duration) (bool, error) {
+	db := r.db.WithContext(ctx)
+	// A new lease starts expired so that the update below takes it.
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&LeaderLeaseDAO{
+		Name:      name,
+		ExpiresAt: time.Unix(0, 0).UTC(),
+	}).Error; err != nil {
+		return false, err
+	}
+
+	if err := db.Model(&LeaderLeaseDAO{}).
+		Where("name = ? AND (holder = ? OR expires_at < ?)", name, holder, r.leaseClock(now, 0)).
+		Updates(map[string]any{"holder": holder, "expires_at": r.leaseClock(now, ttl)}).Error; err != nil {
+		return false, err
+	}
+
+	var lease LeaderLeaseDAO
+	if err := db.Select("holder").Where("name = ?", name).Take(&lease).Error; err != nil {
+		return false, err
+	}
+	return lease.Holder == holder, nil
+}
+
+// leaseClock returns the current time plus offset, taken from the database
+// clock on MySQL.
+func (r *MySQLRepository) leaseClock(now time.Time, offset time.Duration) clause.Expr {
+	if r.db.Dialector.Name() == "mysql" {
+		return gorm.Expr("NOW(3) + INTERVAL ? MICROSECOND", offset.Microseconds())
+	}
+	return gorm.Expr("?", now.Add(offset))
+}
+
+// ReleaseLease expires the lease if holder owns it, so another replica can take
+// over without waiting for the TTL.
+func (r *MySQLRepository) ReleaseLease(ctx context.Context, name, holder string) error {
+	return r.db.WithContext(ctx).Model(&LeaderLeaseDAO{}).
+		Where("name = ? AND holder = ?", name, holder).
+		Update("expires_at", r.leaseClock(time.Now(), 0)).Error
+}
@@ -0,0 +1,51 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCronNext(t *testing.T) {
+	shanghai := time.FixedZone("CST", 8*3600)
+	tests := []struct {
+		expr  string
+		after time.Time
+		want  time.Time
+	}{
+		{"*/2 9-23 * * *", time.Date(2026, 10, 16, 9, 1, 30, 0, shanghai), time.Date(2026, 10, 16, 9, 2, 0, 0, shanghai)},
+		{"*/2 9-23 * * *", time.Date(2026, 10, 16, 23, 58, 0, 0, shanghai), time.Date(2026, 10, 17, 9, 0, 0, 0, shanghai)},
+		{"0 8 * * 1-5", time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC), time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)},
+		{"30 0 1 * 0", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), time.Date(2026, 10, 18, 0, 30, 0, 0, time.UTC)},
+		// A stepped "*" day of month is not a restriction: odd days that are Mondays.
+		{"0 0 */2 * 1", time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC), time.Date(2026, 11, 9, 0, 0, 0, 0, time.UTC)},
+		{"15,45 */6 * 12 7", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), time.Date(2026, 12, 6, 0, 15, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		cron, err := ParseCron(tt.expr)
+		if err != nil {
+			t.Fatalf("ParseCron(%q) returned error: %v", tt.expr, err)
+		}
+		if got := cron.Next(tt.after); !got.Equal(tt.want) {
+			t.Fatalf("%q after %s: expected %s, got %s", tt.expr, tt.after, tt.want, got)
+		}
+	}
+}
+
+func TestCronNeverFiring(t *testing.T) {
+	cron, err := ParseCron("0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseCron returned error: %v", err)
+	}
+	if got := cron.Next(time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)); !got.IsZero() {
+		t.Fatalf("expected February 30th never to fire, got %s", got)
+	}
+}
+
+func TestParseCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{"", "* * * *", "60 * * * *", "* 5-2 * * *", "*/0 * * * *", "* * * * mon"} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Fatalf("expected %q to be rejected", expr)
+		}
+	}
+}
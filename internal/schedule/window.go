@@ -0,0 +1,79 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a daily time range, optionally limited to some weekdays. An end
+// before the start wraps past midnight, so "23:30"-"01:00" on Wednesdays runs
+// from Wednesday 23:30 to Thursday 01:00.
+type Window struct {
+	start, end time.Duration // Offsets from midnight
+	weekdays   uint8         // Bit per time.Weekday; 0 means every day
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseWindow parses "HH:MM" bounds and three-letter weekday names. Windows are
+// independent: overlapping or adjoining ones are merged by the caller.
+func ParseWindow(start, end string, weekdays []string) (Window, error) {
+	var window Window
+	var err error
+	if window.start, err = parseClock(start); err != nil {
+		return Window{}, fmt.Errorf("start: %w", err)
+	}
+	if window.end, err = parseClock(end); err != nil {
+		return Window{}, fmt.Errorf("end: %w", err)
+	}
+	if window.start == window.end {
+		return Window{}, fmt.Errorf("start and end must differ")
+	}
+	for _, name := range weekdays {
+		weekday, ok := weekdayNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok {
+			return Window{}, fmt.Errorf("unknown weekday %q; use mon, tue, ... sun", name)
+		}
+		window.weekdays |= 1 << weekday
+	}
+	return window, nil
+}
+
+func parseClock(raw string) (time.Duration, error) {
+	clock, err := time.Parse("15:04", strings.TrimSpace(raw))
+	if err != nil {
+		return 0, fmt.Errorf("%q must be HH:MM", raw)
+	}
+	return time.Duration(clock.Hour())*time.Hour + time.Duration(clock.Minute())*time.Minute, nil
+}
+
+// Until returns the end of the window occurrence containing t, evaluated in
+// t's location, and false when t is outside the window.
+func (w Window) Until(t time.Time) (time.Time, bool) {
+	year, month, day := t.Date()
+	// An occurrence that wraps past midnight may have started the day before.
+	for _, offset := range []int{0, -1} {
+		midnight := time.Date(year, month, day+offset, 0, 0, 0, 0, t.Location())
+		if w.weekdays != 0 && w.weekdays&(1<<midnight.Weekday()) == 0 {
+			continue
+		}
+		start := midnight.Add(w.start)
+		end := midnight.Add(w.end)
+		if w.end < w.start {
+			end = end.AddDate(0, 0, 1)
+		}
+		if !t.Before(start) && t.Before(end) {
+			return end, true
+		}
+	}
+	return time.Time{}, false
+}
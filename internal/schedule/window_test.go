@@ -0,0 +1,46 @@
+package schedule
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowUntilWrapsPastMidnight(t *testing.T) {
+	window, err := ParseWindow("23:30", "01:00", []string{"Wed"})
+	if err != nil {
+		t.Fatalf("ParseWindow returned error: %v", err)
+	}
+
+	// 2026-10-14 is a Wednesday.
+	tests := []struct {
+		at     time.Time
+		inside bool
+	}{
+		{time.Date(2026, 10, 14, 23, 29, 0, 0, time.UTC), false},
+		{time.Date(2026, 10, 14, 23, 30, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 15, 0, 59, 0, 0, time.UTC), true},
+		{time.Date(2026, 10, 15, 1, 0, 0, 0, time.UTC), false},
+		{time.Date(2026, 10, 15, 23, 45, 0, 0, time.UTC), false},
+	}
+	for _, tt := range tests {
+		until, inside := window.Until(tt.at)
+		if inside != tt.inside {
+			t.Fatalf("%s: expected inside=%v, got %v", tt.at, tt.inside, inside)
+		}
+		if inside && !until.Equal(time.Date(2026, 10, 15, 1, 0, 0, 0, time.UTC)) {
+			t.Fatalf("%s: expected the window to end Thursday 01:00, got %s", tt.at, until)
+		}
+	}
+}
+
+func TestParseWindowRejectsInvalidBounds(t *testing.T) {
+	if _, err := ParseWindow("25:00", "01:00", nil); err == nil {
+		t.Fatal("expected invalid hour to be rejected")
+	}
+	if _, err := ParseWindow("02:00", "02:00", nil); err == nil {
+		t.Fatal("expected empty window to be rejected")
+	}
+	if _, err := ParseWindow("02:00", "03:00", []string{"someday"}); err == nil {
+		t.Fatal("expected unknown weekday to be rejected")
+	}
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"c2c_monitor/internal/alertrender"
+	"c2c_monitor/internal/domain"
+)
+
+var ErrAlertNotDead = errors.New("alert not found or not dead-lettered")
+
+const (
+	alertOutboxPollInterval = 15 * time.Second
+	alertOutboxBatchSize    = 50
+	// alertDeliveryClaim is how long a delivery attempt owns its alert. Sends
+	// are cut off after it, so no other attempt can pick the alert up while
+	// one is still running.
+	alertDeliveryClaim = 5 * time.Minute
+)
+
+// enqueueAlert queues event with its history record for every notification
+// channel, rendered in the channel's format, in the same transaction as state
+// when it is not nil, and starts the first delivery on every channel at once
+// without waiting for them. Once it returns nil the alert is durable: failed
+// deliveries are retried by runAlertOutbox. It fails only when no channel
+// could be queued.
+func (s *MonitorService) enqueueAlert(ctx context.Context, event domain.AlertEvent, alertKey string, state *domain.AlertState) error {
+	now := time.Now()
+	if event.Time.IsZero() {
+		event.Time = now
+	}
+	channels := s.channelNotifiers()
+	names := make([]string, 0, len(channels))
+	for name := range channels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var queued []*domain.OutboxAlert
+	var queueErr error
+	for _, channel := range names {
+		subject, body := s.renderAlert(event, notifierFormat(channels[channel]))
+		record := alertRecord(event, subject, channel)
+		record.CreatedAt = now
+		alert := &domain.OutboxAlert{
+			CreatedAt: now,
+			Kind:      event.Kind(),
+			AlertKey:  alertKey,
+			Subject:   subject,
+			Body:      body,
+			Status:    domain.AlertPending,
+			// Claimed by the first attempt, which keeps the worker away.
+			NextAttemptAt: now.Add(alertDeliveryClaim),
+			Channel:       channel,
+		}
+		if err := s.repo.EnqueueAlert(ctx, alert, record, state); err != nil {
+			slog.Error("failed to queue alert for channel", "event", "alert_enqueue_failed", "channel", channel, "alert_key", alertKey, "error", err)
+			queueErr = err
+			continue
+		}
+		queued = append(queued, alert)
+	}
+	if len(queued) == 0 {
+		return queueErr
+	}
+	// First deliveries outlive the caller, such as a manual collection
+	// request; deliverAlert bounds each of them.
+	deliveryCtx := context.WithoutCancel(ctx)
+	for _, alert := range queued {
+		s.deliveries.Add(1)
+		go func() {
+			defer s.deliveries.Done()
+			s.deliverAlert(deliveryCtx, alert)
+		}()
+	}
+	return nil
+}
+
+// waitForDeliveries blocks until the first deliveries started by enqueueAlert
+// have been made and recorded.
+func (s *MonitorService) waitForDeliveries() {
+	s.deliveries.Wait()
+}
+
+// SetAlertTemplates replaces the built-in alert templates. It must be called
+// before Start.
+func (s *MonitorService) SetAlertTemplates(renderer *alertrender.Renderer) {
+	s.alertRenderer = renderer
+}
+
+// renderAlert renders event in format, falling back to the built-in templates
+// when a custom template fails and to HTML when the format is unknown.
+func (s *MonitorService) renderAlert(event domain.AlertEvent, format string) (subject, body string) {
+	subject, body, err := s.alertRenderer.Render(event, format)
+	if err == nil {
+		return subject, body
+	}
+	slog.Error("failed to render alert; using the built-in template", "event", "alert_render_failed", "alert_event", event.Type, "format", format, "error", err)
+	if subject, body, err = alertrender.Default().Render(event, format); err != nil {
+		subject, body, _ = alertrender.Default().Render(event, domain.FormatHTML)
+	}
+	return subject, body
+}
+
+// notifierFormat returns the body format a notifier sends, HTML by default.
+func notifierFormat(notifier domain.INotifier) string {
+	if formatter, ok := notifier.(interface{ Format() string }); ok {
+		return formatter.Format()
+	}
+	return domain.FormatHTML
+}
+
+// alertRecord is the history entry of event on one channel. Service events
+// record the service in Exchange and their event type in AlertType.
+func alertRecord(event domain.AlertEvent, subject, channel string) *domain.AlertRecord {
+	record := &domain.AlertRecord{
+		Kind:            event.Kind(),
+		AlertType:       event.AlertType,
+		Subject:         subject,
+		Exchange:        event.Exchange,
+		SellExchange:    event.SellExchange,
+		Symbol:          event.Symbol,
+		Fiat:            event.Fiat,
+		Side:            event.Side,
+		TargetAmount:    event.TargetAmount,
+		PayMethodFilter: event.PayMethodFilter,
+		Merchant:        event.Merchant,
+		Price:           event.Price,
+		SellPrice:       event.SellPrice,
+		Benchmark:       event.Benchmark,
+		ForexRate:       event.ForexRate,
+		SpreadPercent:   event.SpreadPercent,
+		Details:         event.Details,
+		Channel:         channel,
+	}
+	if record.Kind == domain.AlertKindService {
+		record.Exchange = event.Service
+		record.AlertType = string(event.Type)
+	}
+	return record
+}
+
+// channelNotifiers returns the notifier of each channel. A notifier that fans
+// out to several channels is split so each channel is queued on its own.
+func (s *MonitorService) channelNotifiers() map[string]domain.INotifier {
+	if multi, ok := s.notifier.(domain.IMultiNotifier); ok {
+		return multi.Channels()
+	}
+	return map[string]domain.INotifier{s.notifierChannel(): s.notifier}
+}
+
+// sendToChannel delivers alert through its channel's notifier. Alerts queued
+// before channels were tracked go to every channel, and alerts queued for a
+// channel that has since been split, such as "telegram" into one channel per
+// chat, go to each of its parts.
+func (s *MonitorService) sendToChannel(ctx context.Context, alert *domain.OutboxAlert) error {
+	if alert.Channel == "" {
+		return s.notifier.Send(ctx, alert.Subject, alert.Body)
+	}
+	channels := s.channelNotifiers()
+	if notifier, ok := channels[alert.Channel]; ok {
+		return notifier.Send(ctx, alert.Subject, alert.Body)
+	}
+	var parts []string
+	for name := range channels {
+		if strings.HasPrefix(name, alert.Channel+":") {
+			parts = append(parts, name)
+		}
+	}
+	if len(parts) == 0 {
+		return fmt.Errorf("notification channel %q is not configured", alert.Channel)
+	}
+	sort.Strings(parts)
+	var errs []error
+	for _, name := range parts {
+		if err := channels[name].Send(ctx, alert.Subject, alert.Body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// deliverAlert makes one delivery attempt of a claimed alert and records its
+// outcome. Alerts that fail are retried with exponential backoff until the
+// configured attempts are used up, then marked dead.
+func (s *MonitorService) deliverAlert(ctx context.Context, alert *domain.OutboxAlert) {
+	delivery := s.getConfigSnapshot().AlertDelivery
+	alert.Attempts++
+	sendCtx, cancel := context.WithTimeout(ctx, alertDeliveryClaim)
+	err := s.sendToChannel(sendCtx, alert)
+	cancel()
+	now := time.Now()
+
+	logger := slog.With("alert_id", alert.ID, "kind", alert.Kind, "channel", alert.Channel, "alert_key", alert.AlertKey, "attempts", alert.Attempts)
+	switch {
+	case err == nil:
+		alert.Status = domain.AlertSent
+		alert.SentAt = &now
+		alert.LastError = ""
+		logger.Info("delivered alert", "event", "alert_delivered")
+	case alert.Attempts >= delivery.Attempts():
+		alert.Status = domain.AlertDead
+		alert.LastError = err.Error()
+		logger.Error("giving up on alert delivery", "event", "alert_dead_lettered", "error", err)
+	default:
+		alert.NextAttemptAt = now.Add(delivery.Backoff(alert.Attempts))
+		alert.LastError = err.Error()
+		logger.Warn("alert delivery failed; will retry", "event", "alert_delivery_failed", "next_attempt_at", alert.NextAttemptAt, "error", err)
+	}
+
+	// Record the attempt even when shutdown interrupted it.
+	if err := s.repo.UpdateAlertDelivery(context.WithoutCancel(ctx), alert); err != nil {
+		logger.Error("failed to record alert delivery", "event", "alert_delivery_record_failed", "status", alert.Status, "error", err)
+	}
+}
+
+// runAlertOutbox retries due alerts until ctx is done.
+func (s *MonitorService) runAlertOutbox(ctx context.Context) {
+	ticker := time.NewTicker(alertOutboxPollInterval)
+	defer ticker.Stop()
+	for {
+		s.deliverDueAlerts(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// deliverDueAlerts retries due alerts, each channel in its own goroutine so a
+// slow or rate-limited channel does not hold up the others. Within a channel
+// alerts go out in the order they fell due, each claimed just before it is
+// sent.
+func (s *MonitorService) deliverDueAlerts(ctx context.Context) {
+	if !s.notifierEnabled() {
+		return
+	}
+	alerts, err := s.repo.GetDueAlerts(ctx, time.Now(), alertOutboxBatchSize)
+	if err != nil {
+		slog.Error("failed to load due alerts", "event", "alert_outbox_load_failed", "error", err)
+		return
+	}
+	byChannel := make(map[string][]*domain.OutboxAlert)
+	for _, alert := range alerts {
+		byChannel[alert.Channel] = append(byChannel[alert.Channel], alert)
+	}
+
+	var channels sync.WaitGroup
+	for _, queue := range byChannel {
+		channels.Add(1)
+		go func() {
+			defer channels.Done()
+			for _, alert := range queue {
+				if ctx.Err() != nil {
+					return
+				}
+				s.claimAndDeliver(ctx, alert)
+			}
+		}()
+	}
+	channels.Wait()
+}
+
+func (s *MonitorService) claimAndDeliver(ctx context.Context, alert *domain.OutboxAlert) {
+	now := time.Now()
+	claimed, err := s.repo.ClaimAlert(ctx, alert.ID, now, now.Add(alertDeliveryClaim))
+	if err != nil {
+		slog.Error("failed to claim alert", "event", "alert_claim_failed", "alert_id", alert.ID, "error", err)
+		return
+	}
+	if !claimed {
+		// Another attempt, such as the first one, is delivering it.
+		return
+	}
+	s.deliverAlert(ctx, alert)
+}
+
+// GetAlertOutbox lists queued, sent and dead alerts, newest first.
+func (s *MonitorService) GetAlertOutbox(ctx context.Context, filter domain.AlertOutboxFilter) ([]*domain.OutboxAlert, error) {
+	return s.repo.GetAlertOutbox(ctx, filter)
+}
+
+// RequeueAlert gives a dead alert a fresh attempt budget; the worker delivers
+// it on its next pass.
+func (s *MonitorService) RequeueAlert(ctx context.Context, id int64) error {
+	found, err := s.repo.RequeueAlert(ctx, id, time.Now())
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrAlertNotDead
+	}
+	slog.Info("requeued dead alert", "event", "alert_requeued", "alert_id", id)
+	return nil
+}
+
+// GetAlertHistory returns one page of past alerts, newest first, and the
+// number of alerts matching filter.
+func (s *MonitorService) GetAlertHistory(ctx context.Context, filter domain.AlertHistoryFilter) ([]*domain.AlertRecord, int64, error) {
+	return s.repo.GetAlertHistory(ctx, filter)
+}
@@ -0,0 +1,325 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+func TestAlertOutboxRetriesFailedDeliveryUntilDead(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.AlertDelivery.MaxAttempts = 3
+	repo := &stubRepository{}
+	notifier := &flakyNotifier{failures: 3}
+	svc := NewMonitorService(cfg, repo, nil, sourceAwareForex{rate: 7.2}, notifier)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkAlert(context.Background(), testPricePoint(7.0, 0))
+	svc.waitForDeliveries()
+
+	// The new low is durable even though SMTP is down.
+	key := domain.AlertStateKey(domain.ExchangeGate, testMarket, domain.SideBuy, 0, "")
+	if got := svc.GetAlertStates()[key]; got != 7.0 || repo.savedAlert == nil {
+		t.Fatalf("expected the queued alert to advance the alert state, got %v", svc.GetAlertStates())
+	}
+	alerts := repo.outboxAlerts()
+	if len(alerts) != 1 || alerts[0].Status != domain.AlertPending || alerts[0].Attempts != 1 || alerts[0].LastError == "" || alerts[0].Kind != domain.AlertKindPrice {
+		t.Fatalf("expected one pending alert after the failed first attempt, got %#v", alerts)
+	}
+	if wait := time.Until(alerts[0].NextAttemptAt); wait < 25*time.Second || wait > 30*time.Second {
+		t.Fatalf("expected the first retry after 30s, got %s", wait)
+	}
+
+	// Nothing is due before the backoff elapses.
+	svc.deliverDueAlerts(context.Background())
+	if got := notifier.sent(); got != 1 {
+		t.Fatalf("expected no retry before the backoff, got %d sends", got)
+	}
+
+	for attempt := 2; attempt <= 3; attempt++ {
+		repo.outbox[0].NextAttemptAt = time.Now()
+		svc.deliverDueAlerts(context.Background())
+	}
+	alerts = repo.outboxAlerts()
+	if alerts[0].Status != domain.AlertDead || alerts[0].Attempts != 3 {
+		t.Fatalf("expected the alert to be dead after 3 attempts, got %#v", alerts[0])
+	}
+
+	if err := svc.RequeueAlert(context.Background(), 99); !errors.Is(err, ErrAlertNotDead) {
+		t.Fatalf("expected unknown alerts not to be requeued, got %v", err)
+	}
+	if err := svc.RequeueAlert(context.Background(), alerts[0].ID); err != nil {
+		t.Fatalf("requeue: %v", err)
+	}
+	svc.deliverDueAlerts(context.Background())
+	alerts = repo.outboxAlerts()
+	if alerts[0].Status != domain.AlertSent || alerts[0].SentAt == nil || alerts[0].LastError != "" || notifier.sent() != 4 {
+		t.Fatalf("expected the requeued alert to be delivered, got %#v", alerts[0])
+	}
+}
+
+func TestAlertHistoryRecordsWhyAnAlertFired(t *testing.T) {
+	repo := &stubRepository{}
+	svc := NewMonitorService(testMonitorConfig(), repo, nil, sourceAwareForex{rate: 7.2}, &recordingNotifier{})
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkAlert(context.Background(), testPricePoint(7.0, 0))
+	svc.waitForDeliveries()
+	svc.checkAlert(context.Background(), testPricePoint(6.9, 0))
+	svc.waitForDeliveries()
+
+	records, total, err := svc.GetAlertHistory(context.Background(), domain.AlertHistoryFilter{})
+	if err != nil || total != 2 {
+		t.Fatalf("expected two alert records, got %d, %v", total, err)
+	}
+	lower, initial := records[0], records[1]
+	if initial.AlertType != "Initial" || lower.AlertType != "Lower" {
+		t.Fatalf("expected an initial alert then a new low, got %q and %q", initial.AlertType, lower.AlertType)
+	}
+	if lower.Kind != domain.AlertKindPrice || lower.Exchange != domain.ExchangeGate || lower.Price != 6.9 || lower.Benchmark != 7.0 || lower.ForexRate != 7.2 || lower.OutboxID != 2 {
+		t.Fatalf("unexpected alert record: %#v", lower)
+	}
+	if lower.Channel != "notifier" || lower.Subject == "" {
+		t.Fatalf("expected the subject and channel to be recorded, got %#v", lower)
+	}
+}
+
+func TestAlertIsQueuedPerChannelAndRetriedOnlyWhereItFailed(t *testing.T) {
+	repo := &stubRepository{}
+	email := &flakyNotifier{failures: 1}
+	webhook := &formatRecordingNotifier{format: domain.FormatJSON}
+	notifier := channelTestNotifier{"email": email, "webhook": webhook}
+	svc := NewMonitorService(testMonitorConfig(), repo, nil, sourceAwareForex{rate: 7.2}, notifier)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkAlert(context.Background(), testPricePoint(7.0, 0))
+	svc.waitForDeliveries()
+
+	alerts := repo.outboxAlerts()
+	if len(alerts) != 2 || alerts[0].Channel != "email" || alerts[1].Channel != "webhook" {
+		t.Fatalf("expected one outbox row per channel, got %#v", alerts)
+	}
+	if alerts[0].Status != domain.AlertPending || alerts[1].Status != domain.AlertSent {
+		t.Fatalf("expected only the email delivery to be pending, got %s and %s", alerts[0].Status, alerts[1].Status)
+	}
+	if len(webhook.bodies) != 1 || !strings.Contains(webhook.bodies[0], `"event_type":"price_opportunity"`) || !strings.Contains(alerts[0].Body, "<h3>") {
+		t.Fatalf("expected each channel to get the alert in its own format, got %q and %q", alerts[0].Body, webhook.bodies)
+	}
+
+	repo.outbox[0].NextAttemptAt = time.Now()
+	svc.deliverDueAlerts(context.Background())
+	alerts = repo.outboxAlerts()
+	if alerts[0].Status != domain.AlertSent || email.sent() != 2 || len(webhook.bodies) != 1 {
+		t.Fatalf("expected only the email to be retried, got %#v and %d webhook sends", alerts[0], len(webhook.bodies))
+	}
+}
+
+func TestAlertQueuedForASplitChannelGoesToEachPart(t *testing.T) {
+	repo := &stubRepository{}
+	first, second, email := &recordingNotifier{}, &recordingNotifier{}, &recordingNotifier{}
+	notifier := channelTestNotifier{"telegram:100": first, "telegram:@alerts": second, "email": email}
+	svc := NewMonitorService(testMonitorConfig(), repo, nil, sourceAwareForex{rate: 7.2}, notifier)
+	alert := &domain.OutboxAlert{Kind: domain.AlertKindService, Subject: "down", Status: domain.AlertPending, NextAttemptAt: time.Now(), Channel: "telegram"}
+	if err := repo.EnqueueAlert(context.Background(), alert, &domain.AlertRecord{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	svc.deliverDueAlerts(context.Background())
+
+	if first.calls != 1 || second.calls != 1 || email.calls != 0 {
+		t.Fatalf("expected the alert to reach every chat and no other channel, got %d, %d and %d sends", first.calls, second.calls, email.calls)
+	}
+	if alerts := repo.outboxAlerts(); alerts[0].Status != domain.AlertSent {
+		t.Fatalf("expected the alert to be sent, got %#v", alerts[0])
+	}
+}
+
+func TestDueAlertIsDeliveredOnceByConcurrentWorkers(t *testing.T) {
+	repo := &stubRepository{}
+	notifier := &blockingNotifier{release: make(chan struct{})}
+	svc := NewMonitorService(testMonitorConfig(), repo, nil, sourceAwareForex{rate: 7.2}, notifier)
+	alert := &domain.OutboxAlert{Kind: domain.AlertKindService, Subject: "down", Status: domain.AlertPending, NextAttemptAt: time.Now()}
+	if err := repo.EnqueueAlert(context.Background(), alert, &domain.AlertRecord{}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var workers sync.WaitGroup
+	for range 2 {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			svc.deliverDueAlerts(context.Background())
+		}()
+	}
+	waitFor(t, "the first send", func() bool { return notifier.sent() == 1 })
+	close(notifier.release)
+	workers.Wait()
+
+	if got := notifier.sent(); got != 1 {
+		t.Fatalf("expected a slow delivery not to be repeated, got %d sends", got)
+	}
+	if alerts := repo.outboxAlerts(); alerts[0].Status != domain.AlertSent {
+		t.Fatalf("expected the alert to be sent, got %#v", alerts[0])
+	}
+}
+
+func TestSlowChannelDoesNotHoldUpOtherChannels(t *testing.T) {
+	repo := &stubRepository{}
+	slow := &blockingNotifier{release: make(chan struct{})}
+	fast := &recordingNotifier{}
+	notifier := channelTestNotifier{"email": slow, "webhook": fast}
+	svc := NewMonitorService(testMonitorConfig(), repo, nil, sourceAwareForex{rate: 7.2}, notifier)
+	for _, channel := range []string{"email", "webhook", "webhook"} {
+		alert := &domain.OutboxAlert{Kind: domain.AlertKindService, Subject: "down", Status: domain.AlertPending, NextAttemptAt: time.Now(), Channel: channel}
+		if err := repo.EnqueueAlert(context.Background(), alert, &domain.AlertRecord{}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.deliverDueAlerts(context.Background())
+	}()
+	waitFor(t, "the webhook alerts", func() bool {
+		alerts := repo.outboxAlerts()
+		return alerts[1].Status == domain.AlertSent && alerts[2].Status == domain.AlertSent
+	})
+	if slow.sent() != 1 {
+		t.Fatalf("expected the email to be in flight, got %d sends", slow.sent())
+	}
+	close(slow.release)
+	<-done
+
+	if alerts := repo.outboxAlerts(); alerts[0].Status != domain.AlertSent {
+		t.Fatalf("expected the email to be sent once released, got %#v", alerts[0])
+	}
+}
+
+func TestServiceAlertsReportRecoveryAndStaleForex(t *testing.T) {
+	repo := &stubRepository{}
+	svc := NewMonitorService(testMonitorConfig(), repo, nil, sourceAwareForex{rate: 7.2}, &recordingNotifier{})
+	ctx := context.Background()
+
+	svc.updateServiceHealth(domain.ExchangeGate, "Error", "gate api returned status: 500")
+	svc.waitForDeliveries()
+	waitFor(t, "the service down alert", func() bool { return len(repo.outboxAlerts()) == 1 })
+	svc.updateServiceHealth(domain.ExchangeGate, "OK", "")
+	svc.waitForDeliveries()
+	waitFor(t, "the recovery alert", func() bool { return len(repo.outboxAlerts()) == 2 })
+
+	stale := errors.New("forex rate USDCNY is stale")
+	svc.checkForexStale(ctx, stale)
+	svc.waitForDeliveries()
+	svc.checkForexStale(ctx, stale)
+	svc.waitForDeliveries()
+	svc.checkForexStale(ctx, nil)
+	svc.waitForDeliveries()
+	svc.checkForexStale(ctx, stale)
+	svc.waitForDeliveries()
+
+	alerts := repo.outboxAlerts()
+	if len(alerts) != 4 {
+		t.Fatalf("expected down, recovered and one stale alert per outage, got %d alerts", len(alerts))
+	}
+	for i, want := range []string{"Service Down: Gate", "Service Recovered: Gate", "Forex Stale", "Forex Stale"} {
+		if !strings.Contains(alerts[i].Subject, want) || alerts[i].Kind != domain.AlertKindService {
+			t.Fatalf("alert %d: expected a service alert about %q, got %q", i, want, alerts[i].Subject)
+		}
+	}
+	if !strings.Contains(alerts[2].Body, "forex rate USDCNY is stale") {
+		t.Fatalf("expected the stale alert to explain why, got %q", alerts[2].Body)
+	}
+
+	records, _, err := svc.GetAlertHistory(ctx, domain.AlertHistoryFilter{})
+	if err != nil || records[2].AlertType != string(domain.AlertEventServiceRecovered) || records[2].Exchange != domain.ExchangeGate {
+		t.Fatalf("expected the recovery in the alert history, got %#v, %v", records, err)
+	}
+}
+
+func TestBackoffDoublesUpToTheMaximum(t *testing.T) {
+	delivery := testMonitorConfig().AlertDelivery
+	delivery.RetrySeconds, delivery.MaxRetrySeconds = 30, 100
+	for attempts, want := range map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 3: 100 * time.Second, 10: 100 * time.Second} {
+		if got := delivery.Backoff(attempts); got != want {
+			t.Fatalf("backoff after %d attempts: expected %s, got %s", attempts, want, got)
+		}
+	}
+}
+
+type flakyNotifier struct {
+	mu       sync.Mutex
+	failures int
+	calls    int
+}
+
+func (n *flakyNotifier) Send(ctx context.Context, subject, body string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.calls++
+	if n.calls <= n.failures {
+		return errors.New("SMTP timeout")
+	}
+	return nil
+}
+
+func (n *flakyNotifier) sent() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.calls
+}
+
+// blockingNotifier holds every send until release is closed.
+type blockingNotifier struct {
+	release chan struct{}
+	calls   atomic.Int32
+}
+
+func (n *blockingNotifier) Send(ctx context.Context, subject, body string) error {
+	n.calls.Add(1)
+	<-n.release
+	return nil
+}
+
+func (n *blockingNotifier) sent() int {
+	return int(n.calls.Load())
+}
+
+// channelTestNotifier fans out to named channels like notifier.MultiNotifier.
+type channelTestNotifier map[string]domain.INotifier
+
+func (n channelTestNotifier) Send(ctx context.Context, subject, body string) error {
+	for _, notifier := range n {
+		if err := notifier.Send(ctx, subject, body); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (n channelTestNotifier) Channels() map[string]domain.INotifier {
+	return n
+}
+
+type formatRecordingNotifier struct {
+	mu     sync.Mutex
+	format string
+	bodies []string
+}
+
+func (n *formatRecordingNotifier) Format() string {
+	return n.format
+}
+
+func (n *formatRecordingNotifier) Send(ctx context.Context, subject, body string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.bodies = append(n.bodies, body)
+	return nil
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math/rand"
+	"sync"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/schedule"
+)
+
+// c2cTarget is the unit of C2C scheduling: one amount tier of a market on one
+// exchange, collected for every configured side and pay method.
+type c2cTarget struct {
+	exchange string
+	market   string
+	amount   float64
+}
+
+// c2cTargets lists every configured target.
+func c2cTargets(cfg config.MonitorConfig) []c2cTarget {
+	var targets []c2cTarget
+	for _, name := range cfg.Exchanges {
+		for _, market := range cfg.CollectedMarkets() {
+			for _, amount := range cfg.AmountsFor(market) {
+				targets = append(targets, c2cTarget{exchange: name, market: market.Key(), amount: amount})
+			}
+		}
+	}
+	return targets
+}
+
+// c2cScheduler decides which targets are due. A target is never started again
+// while its previous collection is still running; it becomes due as soon as
+// that collection finishes instead.
+type c2cScheduler struct {
+	mu       sync.Mutex
+	started  map[c2cTarget]time.Time
+	jitter   map[c2cTarget]time.Duration
+	running  map[c2cTarget]bool
+	fast     map[c2cTarget]bool // Last run found a price close to the benchmark
+	finished chan struct{}      // Wakes the loop when a collection finishes
+}
+
+func newC2CScheduler() *c2cScheduler {
+	return &c2cScheduler{
+		started:  make(map[c2cTarget]time.Time),
+		jitter:   make(map[c2cTarget]time.Duration),
+		running:  make(map[c2cTarget]bool),
+		fast:     make(map[c2cTarget]bool),
+		finished: make(chan struct{}, 1),
+	}
+}
+
+// due marks the targets that are due at now as running and returns them,
+// along with the time the next idle target becomes due (zero if none is).
+// Schedules are read from cfg on every call, so config changes apply to the
+// next run without losing each target's last start time. A target that falls
+// due inside a blackout window of its exchange waits until the window ends.
+func (sc *c2cScheduler) due(cfg config.MonitorConfig, now time.Time) ([]c2cTarget, time.Time) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	var due []c2cTarget
+	var next time.Time
+	configured := make(map[c2cTarget]bool)
+	for _, target := range c2cTargets(cfg) {
+		configured[target] = true
+		if sc.running[target] {
+			continue
+		}
+		at := now
+		if started, ok := sc.started[target]; ok {
+			at = nextC2CRun(cfg, target, started, sc.jitter[target])
+			if sc.fast[target] && cfg.AdaptivePolling.ProximityPercent > 0 {
+				if fastAt := started.Add(cfg.AdaptivePolling.Interval()); at.IsZero() || fastAt.Before(at) {
+					at = fastAt
+				}
+			}
+			if at.IsZero() {
+				continue
+			}
+		}
+		if !at.After(now) {
+			if until, blackout := cfg.BlackoutUntil(target.exchange, now); blackout {
+				at = until
+			}
+		}
+		if !at.After(now) {
+			due = append(due, target)
+			continue
+		}
+		if next.IsZero() || at.Before(next) {
+			next = at
+		}
+	}
+	for target := range sc.started {
+		if !configured[target] {
+			delete(sc.started, target)
+			delete(sc.jitter, target)
+			delete(sc.fast, target)
+		}
+	}
+
+	// One jitter draw per batch keeps targets that were started together and
+	// share an interval in the same run, so round trips can still pair them.
+	draw := rand.Float64()
+	for _, target := range due {
+		sc.running[target] = true
+		sc.started[target] = now
+		sc.jitter[target] = c2cJitter(cfg.C2CInterval(target.exchange, target.market, target.amount), draw)
+	}
+	return due, next
+}
+
+// claim marks the targets that are not running as started now, for a
+// collection outside the regular schedule, and returns them along with the
+// targets that are already being collected.
+func (sc *c2cScheduler) claim(cfg config.MonitorConfig, targets []c2cTarget, now time.Time) (claimed, busy []c2cTarget) {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	draw := rand.Float64()
+	for _, target := range targets {
+		if sc.running[target] {
+			busy = append(busy, target)
+			continue
+		}
+		sc.running[target] = true
+		sc.started[target] = now
+		sc.jitter[target] = c2cJitter(cfg.C2CInterval(target.exchange, target.market, target.amount), draw)
+		claimed = append(claimed, target)
+	}
+	return claimed, busy
+}
+
+// finish marks targets as idle again and wakes the loop. Targets in near are
+// polled at the adaptive interval until a run finds them away from the
+// benchmark again.
+func (sc *c2cScheduler) finish(targets []c2cTarget, near map[c2cTarget]bool) {
+	sc.mu.Lock()
+	for _, target := range targets {
+		delete(sc.running, target)
+		if near[target] == sc.fast[target] {
+			continue
+		}
+		if near[target] {
+			sc.fast[target] = true
+			slog.Info("price is close to the alert benchmark; polling faster", "event", "c2c_adaptive_polling_started", "exchange", target.exchange, "market", target.market, "amount", target.amount)
+		} else {
+			delete(sc.fast, target)
+			slog.Info("price moved away from the alert benchmark; polling normally", "event", "c2c_adaptive_polling_stopped", "exchange", target.exchange, "market", target.market, "amount", target.amount)
+		}
+	}
+	sc.mu.Unlock()
+
+	select {
+	case sc.finished <- struct{}{}:
+	default:
+	}
+}
+
+// nextC2CRun returns when a target last started at started is due again, or
+// the zero time if its cron expression never fires.
+func nextC2CRun(cfg config.MonitorConfig, target c2cTarget, started time.Time, jitter time.Duration) time.Time {
+	if expr := cfg.C2CCronFor(target.exchange, target.market, target.amount); expr != "" {
+		cron, err := schedule.ParseCron(expr)
+		if err == nil {
+			return cron.Next(started.In(cfg.ScheduleLocation()))
+		}
+		slog.Error("invalid c2c cron expression", "event", "c2c_cron_invalid", "exchange", target.exchange, "cron", expr, "error", err)
+	}
+	return started.Add(cfg.C2CInterval(target.exchange, target.market, target.amount) + jitter)
+}
+
+// nextForexRun returns when the Forex loop fires after now.
+func nextForexRun(cfg config.MonitorConfig, now time.Time) time.Time {
+	if cfg.ForexCron != "" {
+		cron, err := schedule.ParseCron(cfg.ForexCron)
+		if err == nil {
+			if next := cron.Next(now.In(cfg.ScheduleLocation())); !next.IsZero() {
+				return next
+			}
+		}
+		slog.Error("forex cron expression never fires; using the interval", "event", "forex_cron_invalid", "cron", cfg.ForexCron, "error", err)
+	}
+	interval := time.Duration(cfg.ForexIntervalHours) * time.Hour
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return now.Add(interval)
+}
+
+// c2cJitter spreads polls by up to a minute, or a third of short intervals.
+func c2cJitter(interval time.Duration, draw float64) time.Duration {
+	maxJitter := interval / 3
+	if maxJitter > time.Minute {
+		maxJitter = time.Minute
+	}
+	return time.Duration(draw * float64(maxJitter)).Round(time.Second)
+}
+
+func (s *MonitorService) runC2CLoop(ctx context.Context) {
+	scheduler := s.c2cScheduler
+	var runs sync.WaitGroup
+	defer runs.Wait()
+
+	for ctx.Err() == nil {
+		configChanged := s.configChangeSignal()
+		due, next := scheduler.due(s.getConfigSnapshot(), time.Now())
+		s.setNextRun(&s.nextC2CRun, next)
+		if len(due) > 0 {
+			runs.Add(1)
+			go func() {
+				defer runs.Done()
+				scheduler.finish(due, s.checkC2CTargets(ctx, due).near)
+			}()
+		}
+
+		var timer *time.Timer
+		var timerC <-chan time.Time
+		if !next.IsZero() {
+			delay := time.Until(next)
+			timer = time.NewTimer(delay)
+			timerC = timer.C
+			slog.Info("scheduled next c2c check", "event", "c2c_check_scheduled", "delay", delay.Round(time.Second).String(), "started_targets", len(due))
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-configChanged:
+		case <-scheduler.finished:
+		case <-timerC:
+		}
+		if timer != nil {
+			stopTimer(timer)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/domain"
+)
+
+func TestC2CSchedulerAppliesPerTargetIntervals(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{0, 1000}
+	cfg.Exchanges = []string{domain.ExchangeBinance, domain.ExchangeOKX}
+	cfg.PollSchedules = []config.PollScheduleConfig{
+		{Exchange: domain.ExchangeBinance, IntervalMinutes: 1},
+		{Exchange: domain.ExchangeOKX, Amounts: []float64{1000}, IntervalMinutes: 15},
+	}
+	scheduler := newC2CScheduler()
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	due, next := scheduler.due(cfg, start)
+	if len(due) != 4 || !next.IsZero() {
+		t.Fatalf("expected every target on the first run, got %v (next %s)", due, next)
+	}
+	scheduler.finish(due, nil)
+
+	// Binance is due within its 1-minute interval plus at most 20s of jitter;
+	// OKX 0 follows the 3-minute default and OKX 1000 the 15-minute schedule.
+	due, _ = scheduler.due(cfg, start.Add(80*time.Second))
+	want := []c2cTarget{
+		{exchange: domain.ExchangeBinance, market: testMarket, amount: 0},
+		{exchange: domain.ExchangeBinance, market: testMarket, amount: 1000},
+	}
+	if !reflect.DeepEqual(due, want) {
+		t.Fatalf("expected only the binance tiers, got %v", due)
+	}
+	scheduler.finish(due, nil)
+
+	due, _ = scheduler.due(cfg, start.Add(4*time.Minute))
+	for _, target := range due {
+		if target.exchange == domain.ExchangeOKX && target.amount == 1000 {
+			t.Fatalf("expected the 15-minute tier to wait, got %v", due)
+		}
+	}
+}
+
+func TestC2CSchedulerNeverOverlapsATarget(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{0}
+	scheduler := newC2CScheduler()
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	running, _ := scheduler.due(cfg, start)
+	if len(running) != 1 {
+		t.Fatalf("expected one target, got %v", running)
+	}
+	if due, next := scheduler.due(cfg, start.Add(time.Hour)); len(due) != 0 || !next.IsZero() {
+		t.Fatalf("expected a running target not to start again, got %v (next %s)", due, next)
+	}
+
+	scheduler.finish(running, nil)
+	select {
+	case <-scheduler.finished:
+	default:
+		t.Fatal("expected finish to wake the loop")
+	}
+	if due, _ := scheduler.due(cfg, start.Add(time.Hour)); len(due) != 1 {
+		t.Fatalf("expected the overdue target to start once finished, got %v", due)
+	}
+}
+
+func TestC2CSchedulerFollowsCronAndBlackouts(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{0}
+	cfg.Exchanges = []string{domain.ExchangeBinance, domain.ExchangeOKX}
+	cfg.C2CCron = "*/10 * * * *"
+	cfg.ScheduleTimezone = "UTC"
+	cfg.CollectionBlackouts = []config.BlackoutWindowConfig{{Exchange: domain.ExchangeOKX, Start: "08:00", End: "08:30"}}
+	scheduler := newC2CScheduler()
+	start := time.Date(2026, 10, 16, 7, 55, 0, 0, time.UTC)
+
+	due, _ := scheduler.due(cfg, start)
+	scheduler.finish(due, nil)
+
+	due, next := scheduler.due(cfg, start.Add(time.Minute))
+	if len(due) != 0 || !next.Equal(time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the next cron tick at 08:00, got %v (next %s)", due, next)
+	}
+
+	due, next = scheduler.due(cfg, time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC))
+	if len(due) != 1 || due[0].exchange != domain.ExchangeBinance {
+		t.Fatalf("expected okx to be held back by its maintenance window, got %v", due)
+	}
+	if want := time.Date(2026, 10, 16, 8, 30, 0, 0, time.UTC); !next.Equal(want) {
+		t.Fatalf("expected okx to run when the window ends at %s, got %s", want, next)
+	}
+}
+
+func TestC2CSchedulerPollsNearTargetsFaster(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{0}
+	cfg.AdaptivePolling = config.AdaptivePollingConfig{ProximityPercent: 1, IntervalSeconds: 30}
+	scheduler := newC2CScheduler()
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	target := c2cTarget{exchange: domain.ExchangeGate, market: testMarket, amount: 0}
+
+	due, _ := scheduler.due(cfg, start)
+	scheduler.finish(due, map[c2cTarget]bool{target: true})
+
+	due, _ = scheduler.due(cfg, start.Add(30*time.Second))
+	if len(due) != 1 {
+		t.Fatalf("expected a near target after the adaptive interval, got %v", due)
+	}
+	scheduler.finish(due, nil)
+
+	// Back to the 3-minute interval once the price moved away.
+	due, next := scheduler.due(cfg, start.Add(time.Minute))
+	if len(due) != 0 || next.Before(start.Add(30*time.Second+3*time.Minute)) {
+		t.Fatalf("expected the normal interval again, got %v (next %s)", due, next)
+	}
+}
+
+func TestCheckC2CTargetsReportsPricesNearBenchmark(t *testing.T) {
+	for _, tt := range []struct {
+		proximity float64
+		wantNear  int
+	}{
+		{proximity: 2, wantNear: 2}, // 7.3 is within 2% of the 7.2 benchmark
+		{proximity: 1, wantNear: 0},
+		{proximity: 0, wantNear: 0},
+	} {
+		cfg := testMonitorConfig()
+		cfg.AdaptivePolling.ProximityPercent = tt.proximity
+		svc := NewMonitorService(
+			cfg,
+			&stubRepository{},
+			map[string]domain.IExchange{domain.ExchangeGate: &marketRecordingExchange{}},
+			sourceAwareForex{rate: 7.2, source: "test"},
+			stubNotifier{},
+		)
+		svc.setLastForex(testForexPair, 7.2, time.Now())
+
+		if near := svc.checkC2CTargets(context.Background(), nil).near; len(near) != tt.wantNear {
+			t.Fatalf("proximity %.0f%%: expected %d near targets, got %v", tt.proximity, tt.wantNear, near)
+		}
+	}
+}
+
+func TestCheckC2CTargetsCollectsOnlyScheduledTiers(t *testing.T) {
+	cfg := testMonitorConfig()
+	exchange := &marketRecordingExchange{}
+	svc := NewMonitorService(
+		cfg,
+		&stubRepository{},
+		map[string]domain.IExchange{domain.ExchangeGate: exchange},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkC2CTargets(context.Background(), []c2cTarget{{exchange: domain.ExchangeGate, market: testMarket, amount: 30}})
+	svc.waitForDeliveries()
+
+	got := exchange.requestedTargets()
+	sort.Strings(got)
+	if !reflect.DeepEqual(got, []string{"USDT/CNY 30"}) {
+		t.Fatalf("expected only the scheduled tier, got %v", got)
+	}
+	if status := svc.GetServiceStatuses()[domain.ExchangeGate]; status == nil || status.Status != "OK" {
+		t.Fatalf("expected health from the scheduled tiers, got %#v", status)
+	}
+}
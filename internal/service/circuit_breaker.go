@@ -0,0 +1,179 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/domain"
+)
+
+// ErrCircuitOpen is returned instead of calling an exchange whose circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// circuitBreaker tracks the consecutive failures of one exchange. It is shared
+// by all tiers of the exchange, so a venue that rate-limits one request stops
+// being called for every market, side and amount.
+type circuitBreaker struct {
+	name          string
+	mu            sync.Mutex
+	state         string
+	failures      int
+	trips         int // Openings since the last success; drives the exponential cool-off
+	openUntil     time.Time
+	probeInFlight bool
+	lastError     string
+}
+
+func newCircuitBreaker(name string) *circuitBreaker {
+	return &circuitBreaker{name: name, state: domain.CircuitClosed}
+}
+
+// allow reports whether a request may be sent now. Once the cool-off has
+// elapsed, exactly one request is let through as the half-open probe.
+func (b *circuitBreaker) allow(now time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case domain.CircuitOpen:
+		if now.Before(b.openUntil) {
+			return fmt.Errorf("%w until %s", ErrCircuitOpen, b.openUntil.Format(time.RFC3339))
+		}
+		b.state = domain.CircuitHalfOpen
+		b.probeInFlight = true
+		return nil
+	case domain.CircuitHalfOpen:
+		if b.probeInFlight {
+			return fmt.Errorf("%w: waiting for the half-open probe", ErrCircuitOpen)
+		}
+		b.probeInFlight = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	recovered := b.state != domain.CircuitClosed
+	b.state = domain.CircuitClosed
+	b.failures = 0
+	b.trips = 0
+	b.openUntil = time.Time{}
+	b.probeInFlight = false
+	b.lastError = ""
+	b.mu.Unlock()
+
+	if recovered {
+		slog.Info("exchange circuit closed", "event", "exchange_circuit_closed", "exchange", b.name)
+	}
+}
+
+// recordFailure counts a failed request. The circuit opens when the threshold
+// is reached, when the half-open probe fails, or when the venue sent a
+// Retry-After hint, which sets a lower bound on the cool-off.
+func (b *circuitBreaker) recordFailure(err error, now time.Time, cfg config.CircuitBreakerConfig) {
+	var retryAfter time.Duration
+	var statusErr *domain.ExchangeStatusError
+	if errors.As(err, &statusErr) {
+		retryAfter = statusErr.RetryAfter
+	}
+
+	b.mu.Lock()
+	b.failures++
+	b.lastError = err.Error()
+
+	if b.state == domain.CircuitOpen {
+		// A request that started before the circuit opened.
+		if until := now.Add(retryAfter); until.After(b.openUntil) {
+			b.openUntil = until
+		}
+		b.mu.Unlock()
+		return
+	}
+
+	tripped := b.state == domain.CircuitHalfOpen || b.failures >= cfg.Threshold()
+	if !tripped && retryAfter <= 0 {
+		b.mu.Unlock()
+		return
+	}
+
+	var cooldown time.Duration
+	if tripped {
+		cooldown = cfg.Cooldown()
+		for i := 0; i < b.trips && cooldown < cfg.MaxCooldown(); i++ {
+			cooldown *= 2
+		}
+		if cooldown > cfg.MaxCooldown() {
+			cooldown = cfg.MaxCooldown()
+		}
+		b.trips++
+	}
+	if retryAfter > cooldown {
+		cooldown = retryAfter
+	}
+	b.state = domain.CircuitOpen
+	b.openUntil = now.Add(cooldown)
+	b.probeInFlight = false
+	failures, openUntil := b.failures, b.openUntil
+	b.mu.Unlock()
+
+	slog.Warn("exchange circuit opened",
+		"event", "exchange_circuit_opened",
+		"exchange", b.name,
+		"consecutive_failures", failures,
+		"retry_after", retryAfter.String(),
+		"open_until", openUntil,
+		"error", err,
+	)
+}
+
+// isOpen reports whether requests are currently rejected without a probe.
+func (b *circuitBreaker) isOpen(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state == domain.CircuitOpen && now.Before(b.openUntil)
+}
+
+// releaseProbe lets another probe through when the probe was cancelled
+// before the exchange answered.
+func (b *circuitBreaker) releaseProbe() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == domain.CircuitHalfOpen {
+		b.probeInFlight = false
+	}
+}
+
+func (b *circuitBreaker) status() *domain.CircuitStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := &domain.CircuitStatus{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		LastError:           b.lastError,
+	}
+	if b.state == domain.CircuitOpen {
+		openUntil := b.openUntil
+		status.OpenUntil = &openUntil
+	}
+	return status
+}
+
+func (s *MonitorService) circuitBreaker(exchangeName string) *circuitBreaker {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	breaker, ok := s.breakers[exchangeName]
+	if !ok {
+		breaker = newCircuitBreaker(exchangeName)
+		s.breakers[exchangeName] = breaker
+	}
+	return breaker
+}
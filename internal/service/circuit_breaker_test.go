@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/domain"
+)
+
+func TestCircuitBreakerOpensAndProbesWithExponentialCooloff(t *testing.T) {
+	cfg := config.CircuitBreakerConfig{FailureThreshold: 2, CooldownSeconds: 60, MaxCooldownSeconds: 100}
+	breaker := newCircuitBreaker(domain.ExchangeGate)
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	failure := errors.New("gate api returned status: 403")
+
+	breaker.recordFailure(failure, now, cfg)
+	if err := breaker.allow(now); err != nil {
+		t.Fatalf("expected circuit to stay closed below the threshold, got %v", err)
+	}
+	breaker.recordFailure(failure, now, cfg)
+	if err := breaker.allow(now.Add(59 * time.Second)); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected open circuit to reject requests, got %v", err)
+	}
+
+	probeAt := now.Add(61 * time.Second)
+	if err := breaker.allow(probeAt); err != nil {
+		t.Fatalf("expected a half-open probe after the cool-off, got %v", err)
+	}
+	if err := breaker.allow(probeAt); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected a single probe in flight, got %v", err)
+	}
+	if got := breaker.status().State; got != domain.CircuitHalfOpen {
+		t.Fatalf("expected half-open state, got %s", got)
+	}
+
+	breaker.recordFailure(failure, probeAt, cfg)
+	status := breaker.status()
+	if status.State != domain.CircuitOpen || status.OpenUntil == nil || !status.OpenUntil.Equal(probeAt.Add(100*time.Second)) {
+		t.Fatalf("expected doubled cool-off capped at the maximum, got %#v", status)
+	}
+
+	secondProbe := probeAt.Add(101 * time.Second)
+	if err := breaker.allow(secondProbe); err != nil {
+		t.Fatalf("expected second probe, got %v", err)
+	}
+	breaker.recordSuccess()
+	if status := breaker.status(); status.State != domain.CircuitClosed || status.ConsecutiveFailures != 0 || status.OpenUntil != nil {
+		t.Fatalf("expected successful probe to close the circuit, got %#v", status)
+	}
+}
+
+func TestCircuitBreakerHonorsRetryAfter(t *testing.T) {
+	breaker := newCircuitBreaker(domain.ExchangeGate)
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	breaker.recordFailure(&domain.ExchangeStatusError{Exchange: "gate", StatusCode: http.StatusTooManyRequests, RetryAfter: 10 * time.Minute}, now, config.CircuitBreakerConfig{})
+
+	if err := breaker.allow(now.Add(9 * time.Minute)); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected Retry-After to open the circuit below the threshold, got %v", err)
+	}
+	if err := breaker.allow(now.Add(10 * time.Minute)); err != nil {
+		t.Fatalf("expected a probe once Retry-After elapsed, got %v", err)
+	}
+}
+
+func TestCheckC2CStopsCallingRateLimitedExchange(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{30}
+	exchange := &rateLimitedTestExchange{}
+	svc := NewMonitorService(
+		cfg,
+		&stubRepository{},
+		map[string]domain.IExchange{domain.ExchangeGate: exchange},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	if got := exchange.calls.Load(); got != 1 {
+		t.Fatalf("expected the open circuit to skip retries and later rounds, got %d calls", got)
+	}
+	status := svc.GetServiceStatuses()[domain.ExchangeGate]
+	if status.Status != "Error" || status.Circuit == nil || status.Circuit.State != domain.CircuitOpen || status.Circuit.OpenUntil == nil {
+		t.Fatalf("expected open circuit in the service status, got %#v", status)
+	}
+}
+
+func TestUnsupportedTargetIsNotRetriedAndKeepsTheCircuitClosed(t *testing.T) {
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{30}
+	cfg.PayMethodFilters = []string{"QQ 钱包"}
+	cfg.CircuitBreaker.FailureThreshold = 1
+	exchange := &payMethodLimitedTestExchange{}
+	svc := NewMonitorService(
+		cfg,
+		&stubRepository{},
+		map[string]domain.IExchange{domain.ExchangeGate: exchange},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	if got := exchange.filtered.Load(); got != 2 {
+		t.Fatalf("expected one request per round for the unsupported tier, got %d", got)
+	}
+	if got := exchange.unfiltered.Load(); got != 2 {
+		t.Fatalf("expected the unfiltered tier to keep being collected, got %d requests", got)
+	}
+	if status := svc.GetServiceStatuses()[domain.ExchangeGate]; status.Circuit != nil && status.Circuit.State != domain.CircuitClosed {
+		t.Fatalf("expected the circuit to stay closed, got %#v", status.Circuit)
+	}
+}
+
+// payMethodLimitedTestExchange serves unfiltered tiers and rejects every
+// pay-method filter.
+type payMethodLimitedTestExchange struct {
+	filtered   atomic.Int32
+	unfiltered atomic.Int32
+}
+
+func (e *payMethodLimitedTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	if payMethod != "" {
+		e.filtered.Add(1)
+		return nil, &domain.UnsupportedQueryError{Exchange: "gate", Reason: "pay method filter " + payMethod}
+	}
+	e.unfiltered.Add(1)
+	return []domain.PricePoint{{Price: 7.1, Rank: 1}}, nil
+}
+
+type rateLimitedTestExchange struct {
+	calls atomic.Int32
+}
+
+func (e *rateLimitedTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	e.calls.Add(1)
+	return nil, &domain.ExchangeStatusError{Exchange: "gate", StatusCode: http.StatusTooManyRequests, RetryAfter: time.Hour}
+}
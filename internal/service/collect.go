@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+var ErrInvalidCollectScope = errors.New("invalid collect scope")
+
+// CollectRequest scopes a manual collection. Empty scope lists select every
+// configured exchange, market or amount tier.
+type CollectRequest struct {
+	C2C       bool
+	Forex     bool
+	Exchanges []string // Exchange names or aliases
+	Markets   []string // Market keys, e.g. "USDT/CNY"
+	Amounts   []float64
+}
+
+// CollectNow runs the requested collections immediately. Forex runs first so
+// C2C alerts use the fresh rate. C2C targets that are already being collected
+// by the schedule are skipped rather than fetched twice; every collected target
+// counts as a regular run for the schedule. Only the leader collects.
+func (s *MonitorService) CollectNow(ctx context.Context, req CollectRequest) (domain.CollectResult, error) {
+	if !s.IsLeader() {
+		return domain.CollectResult{}, ErrNotLeader
+	}
+	cfg := s.getConfigSnapshot()
+	if !req.C2C && !req.Forex {
+		return domain.CollectResult{}, fmt.Errorf("%w: nothing to collect", ErrInvalidCollectScope)
+	}
+
+	var targets []c2cTarget
+	if req.C2C {
+		scopedExchanges := make([]string, 0, len(req.Exchanges))
+		for _, raw := range req.Exchanges {
+			name := strings.TrimSpace(raw)
+			if normalized, err := domain.NormalizeExchangeName(name); err == nil {
+				name = normalized
+			}
+			index := slices.IndexFunc(cfg.Exchanges, func(configured string) bool { return strings.EqualFold(configured, name) })
+			if index < 0 {
+				return domain.CollectResult{}, fmt.Errorf("%w: exchange %q is not configured", ErrInvalidCollectScope, raw)
+			}
+			scopedExchanges = append(scopedExchanges, cfg.Exchanges[index])
+		}
+		markets := make(map[string]bool)
+		for _, market := range cfg.CollectedMarkets() {
+			markets[market.Key()] = true
+		}
+		for _, market := range req.Markets {
+			if !markets[market] {
+				return domain.CollectResult{}, fmt.Errorf("%w: market %q is not collected", ErrInvalidCollectScope, market)
+			}
+		}
+
+		all := c2cTargets(cfg)
+		amounts := make(map[float64]bool)
+		for _, target := range all {
+			amounts[target.amount] = true
+		}
+		for _, amount := range req.Amounts {
+			if !amounts[amount] {
+				return domain.CollectResult{}, fmt.Errorf("%w: amount %s is not a configured tier", ErrInvalidCollectScope, strconv.FormatFloat(amount, 'f', -1, 64))
+			}
+		}
+		for _, target := range all {
+			if inScope(scopedExchanges, target.exchange) && inScope(req.Markets, target.market) && inScope(req.Amounts, target.amount) {
+				targets = append(targets, target)
+			}
+		}
+		if len(targets) == 0 {
+			return domain.CollectResult{}, fmt.Errorf("%w: no configured target matches", ErrInvalidCollectScope)
+		}
+	}
+
+	var result domain.CollectResult
+	if req.Forex {
+		s.updateForex(ctx)
+		result.Forex = make(map[string]float64)
+		for _, pair := range cfg.ForexPairs() {
+			if rate, _ := s.getLastForex(pair); rate > 0 {
+				result.Forex[pair] = rate
+			}
+		}
+	}
+
+	if len(targets) > 0 {
+		claimed, busy := s.c2cScheduler.claim(cfg, targets, time.Now())
+		for _, target := range busy {
+			result.SkippedTargets = append(result.SkippedTargets, fmt.Sprintf("%s %s %s", target.exchange, target.market, strconv.FormatFloat(target.amount, 'f', -1, 64)))
+		}
+		if len(claimed) > 0 {
+			run := s.checkC2CTargets(ctx, claimed)
+			s.c2cScheduler.finish(claimed, run.near)
+			result.Prices = run.prices
+		}
+	}
+	sort.Slice(result.Prices, func(i, j int) bool {
+		a, b := result.Prices[i], result.Prices[j]
+		if a.Exchange != b.Exchange {
+			return a.Exchange < b.Exchange
+		}
+		if a.Symbol+a.Fiat != b.Symbol+b.Fiat {
+			return a.Symbol+a.Fiat < b.Symbol+b.Fiat
+		}
+		if a.Side != b.Side {
+			return a.Side < b.Side
+		}
+		if a.TargetAmount != b.TargetAmount {
+			return a.TargetAmount < b.TargetAmount
+		}
+		if a.PayMethodFilter != b.PayMethodFilter {
+			return a.PayMethodFilter < b.PayMethodFilter
+		}
+		return a.Rank < b.Rank
+	})
+	if result.Prices == nil {
+		result.Prices = []domain.PricePoint{}
+	}
+	result.Services = s.GetServiceStatuses()
+
+	slog.Info("manual collection finished", "event", "manual_collection_finished", "c2c", req.C2C, "forex", req.Forex, "prices", len(result.Prices), "skipped_targets", len(result.SkippedTargets))
+	return result, ctx.Err()
+}
+
+// inScope reports whether value is selected by scope; an empty scope selects
+// everything.
+func inScope[T comparable](scope []T, value T) bool {
+	return len(scope) == 0 || slices.Contains(scope, value)
+}
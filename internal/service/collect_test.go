@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"c2c_monitor/internal/domain"
+)
+
+func TestCollectNowSkipsTargetsAlreadyRunning(t *testing.T) {
+	cfg := testMonitorConfig()
+	exchange := &marketRecordingExchange{}
+	repo := &stubRepository{}
+	svc := NewMonitorService(cfg, repo, map[string]domain.IExchange{domain.ExchangeGate: exchange}, sourceAwareForex{rate: 7.2}, stubNotifier{})
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	running := c2cTarget{exchange: domain.ExchangeGate, market: testMarket, amount: 0}
+	if claimed, _ := svc.c2cScheduler.claim(cfg, []c2cTarget{running}, time.Now()); len(claimed) != 1 {
+		t.Fatalf("expected to claim the idle target, got %v", claimed)
+	}
+
+	result, err := svc.CollectNow(context.Background(), CollectRequest{C2C: true, Exchanges: []string{"GATE"}})
+	svc.waitForDeliveries()
+	if err != nil {
+		t.Fatalf("collect: %v", err)
+	}
+	if want := []string{"Gate USDT/CNY 0"}; !reflect.DeepEqual(result.SkippedTargets, want) {
+		t.Fatalf("expected the running tier to be skipped, got %v", result.SkippedTargets)
+	}
+	for _, request := range exchange.requestedTargets() {
+		if request != "USDT/CNY 30" {
+			t.Fatalf("expected only the idle tier to be fetched, got %v", exchange.requestedTargets())
+		}
+	}
+	if len(result.Prices) == 0 || len(result.Prices) != len(repo.savedPricePoints()) {
+		t.Fatalf("expected the saved prices in the result, got %d of %d", len(result.Prices), len(repo.savedPricePoints()))
+	}
+	for _, price := range result.Prices {
+		if price.TargetAmount != 30 {
+			t.Fatalf("expected prices of the collected tier only, got %#v", price)
+		}
+	}
+	if result.Forex != nil || result.Services[domain.ExchangeGate] == nil {
+		t.Fatalf("expected exchange statuses without a forex run, got %#v", result)
+	}
+
+	// The collected tier counts as a regular run; the claimed one stays busy.
+	if due, _ := svc.c2cScheduler.due(cfg, time.Now()); len(due) != 0 {
+		t.Fatalf("expected no target to be due right after the collection, got %v", due)
+	}
+}
+
+func TestCollectNowRejectsUnknownScope(t *testing.T) {
+	svc := NewMonitorService(testMonitorConfig(), &stubRepository{}, map[string]domain.IExchange{domain.ExchangeGate: &marketRecordingExchange{}}, sourceAwareForex{rate: 7.2}, stubNotifier{})
+
+	for _, req := range []CollectRequest{
+		{},
+		{C2C: true, Exchanges: []string{domain.ExchangeBinance}},
+		{C2C: true, Markets: []string{"USDC/CNY"}},
+		{C2C: true, Amounts: []float64{500}},
+	} {
+		if _, err := svc.CollectNow(context.Background(), req); !errors.Is(err, ErrInvalidCollectScope) {
+			t.Fatalf("expected %#v to be rejected, got %v", req, err)
+		}
+	}
+}
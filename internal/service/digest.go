@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"html"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/domain"
+	"c2c_monitor/internal/schedule"
+)
+
+// SetDigest configures the periodic market digest, which the collecting
+// replica sends when it is enabled. It must be called before Start.
+func (s *MonitorService) SetDigest(digest config.DigestConfig) {
+	s.digest = digest
+}
+
+// BuildDigest summarizes the digest period ending at now. Periods longer than
+// a week are read from the daily rollups, shorter ones from the hourly ones.
+func (s *MonitorService) BuildDigest(ctx context.Context, now time.Time) (domain.Digest, error) {
+	cfg := s.getConfigSnapshot()
+	period := s.digest.Period()
+	digest := domain.Digest{
+		GeneratedAt: now,
+		Start:       now.Add(-period),
+		End:         now,
+		Granularity: domain.HistoryGranularityHour,
+		Tiers:       []domain.DigestTier{},
+	}
+	bucket := time.Hour
+	if period > 7*24*time.Hour {
+		digest.Granularity = domain.HistoryGranularityDay
+		bucket = 24 * time.Hour
+	}
+
+	for _, market := range cfg.CollectedMarkets() {
+		points, err := s.repo.GetPriceHistoryByGranularity(ctx, domain.PriceQueryFilter{
+			Symbol:    market.Symbol,
+			Fiat:      market.Fiat,
+			Side:      domain.SideBuy,
+			Rank:      1,
+			StartTime: digest.Start,
+			EndTime:   now,
+		}, digest.Granularity)
+		if err != nil {
+			return domain.Digest{}, fmt.Errorf("load %s prices: %w", market.Key(), err)
+		}
+
+		var forex []*domain.ForexRate
+		var forexRate float64
+		if pair := market.ForexPair(); pair != "" {
+			// One bucket earlier so the first prices have a rate to compare with.
+			forex, err = s.repo.GetForexHistoryByGranularity(ctx, pair, digest.Start.Add(-bucket), now, digest.Granularity)
+			if err != nil {
+				return domain.Digest{}, fmt.Errorf("load %s forex: %w", pair, err)
+			}
+			sort.Slice(forex, func(i, j int) bool { return forex[i].CreatedAt.Before(forex[j].CreatedAt) })
+			if rate, err := s.usableForex(pair, now); err == nil {
+				forexRate = rate
+			}
+		}
+
+		byTier := make(map[c2cTarget][]*domain.PricePoint)
+		for _, point := range points {
+			target := c2cTarget{exchange: point.Exchange, market: market.Key(), amount: point.TargetAmount}
+			byTier[target] = append(byTier[target], point)
+		}
+		for _, exchange := range cfg.Exchanges {
+			for _, amount := range cfg.AmountsFor(market) {
+				tier := domain.DigestTier{Exchange: exchange, Market: market.Key(), TargetAmount: amount}
+				if forexRate > 0 {
+					tier.Benchmark = s.effectiveAlertBenchmark(ctx, market.Key(), forexRate, amount)
+				}
+				summarizeDigestTier(&tier, byTier[c2cTarget{exchange: exchange, market: market.Key(), amount: amount}], forex, bucket, s.digest.TopMerchants)
+				digest.Tiers = append(digest.Tiers, tier)
+			}
+		}
+	}
+	return digest, nil
+}
+
+func summarizeDigestTier(tier *domain.DigestTier, points []*domain.PricePoint, forex []*domain.ForexRate, bucket time.Duration, topMerchants int) {
+	merchants := make(map[string]int)
+	var sum float64
+	for _, point := range points {
+		if point.Price <= 0 {
+			continue
+		}
+		if tier.Samples == 0 || point.Price < tier.MinPrice {
+			tier.MinPrice = point.Price
+		}
+		if point.Price > tier.MaxPrice {
+			tier.MaxPrice = point.Price
+		}
+		sum += point.Price
+		tier.Samples++
+
+		if tier.Benchmark > 0 && point.Price < tier.Benchmark {
+			tier.HoursBelowBenchmark += bucket.Hours()
+		}
+		if rate := forexAt(forex, point.CreatedAt); rate > 0 {
+			spread := (rate - point.Price) / rate * 100
+			if tier.MaxSpreadPercent == nil || spread > *tier.MaxSpreadPercent {
+				tier.MaxSpreadPercent = &spread
+			}
+		}
+		if point.Merchant != "" {
+			merchants[point.Merchant]++
+		}
+	}
+	if tier.Samples > 0 {
+		tier.AvgPrice = sum / float64(tier.Samples)
+	}
+
+	tier.TopMerchants = []domain.DigestMerchant{}
+	for merchant, buckets := range merchants {
+		tier.TopMerchants = append(tier.TopMerchants, domain.DigestMerchant{Merchant: merchant, Buckets: buckets})
+	}
+	sort.Slice(tier.TopMerchants, func(i, j int) bool {
+		if tier.TopMerchants[i].Buckets != tier.TopMerchants[j].Buckets {
+			return tier.TopMerchants[i].Buckets > tier.TopMerchants[j].Buckets
+		}
+		return tier.TopMerchants[i].Merchant < tier.TopMerchants[j].Merchant
+	})
+	if len(tier.TopMerchants) > topMerchants {
+		tier.TopMerchants = tier.TopMerchants[:topMerchants]
+	}
+}
+
+// forexAt returns the last rate observed at or before t from rates sorted by
+// time, or 0 if there is none.
+func forexAt(rates []*domain.ForexRate, t time.Time) float64 {
+	index := sort.Search(len(rates), func(i int) bool { return rates[i].CreatedAt.After(t) })
+	if index == 0 {
+		return 0
+	}
+	return rates[index-1].Rate
+}
+
+// RenderDigest formats a digest as an email, with times in the schedule zone.
+func (s *MonitorService) RenderDigest(digest domain.Digest) (subject, body string) {
+	location := s.getConfigSnapshot().ScheduleLocation()
+	const layout = "2006-01-02 15:04"
+	start, end := digest.Start.In(location).Format(layout), digest.End.In(location).Format(layout)
+	subject = fmt.Sprintf("📊 [C2C Monitor] Market Digest %s – %s", start, end)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `
+		<h3>C2C Market Digest</h3>
+		<p><b>Period:</b> %s – %s (%s best BUY prices)</p>
+		<table border="1" cellpadding="4" cellspacing="0">
+			<tr><th>Exchange</th><th>Market</th><th>Amount</th><th>Min</th><th>Avg</th><th>Max</th><th>Benchmark</th><th>Hours Below</th><th>Max Spread</th><th>Top Merchants</th></tr>
+	`, html.EscapeString(start), html.EscapeString(end), digest.Granularity)
+	for _, tier := range digest.Tiers {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td>%s</td><td>%.0f</td>", html.EscapeString(tier.Exchange), html.EscapeString(tier.Market), tier.TargetAmount)
+		if tier.Samples == 0 {
+			b.WriteString(`<td colspan="7"><i>No data</i></td></tr>`)
+			continue
+		}
+		benchmark, below, spread := "-", "-", "-"
+		if tier.Benchmark > 0 {
+			benchmark = fmt.Sprintf("%.4f", tier.Benchmark)
+			below = fmt.Sprintf("%.0f h", tier.HoursBelowBenchmark)
+		}
+		if tier.MaxSpreadPercent != nil {
+			spread = fmt.Sprintf("%.2f%%", *tier.MaxSpreadPercent)
+		}
+		merchants := make([]string, len(tier.TopMerchants))
+		for i, merchant := range tier.TopMerchants {
+			merchants[i] = fmt.Sprintf("%s (%d)", html.EscapeString(merchant.Merchant), merchant.Buckets)
+		}
+		fmt.Fprintf(&b, "<td>%.4f</td><td>%.4f</td><td>%.4f</td><td>%s</td><td>%s</td><td>%s</td><td>%s</td></tr>",
+			tier.MinPrice, tier.AvgPrice, tier.MaxPrice, benchmark, below, spread, strings.Join(merchants, ", "))
+	}
+	fmt.Fprintf(&b, `
+		</table>
+		<p><i>Hours Below counts the hours (or days) whose best price was below the current alert benchmark; merchants are ranked by how often they offered the best price.</i></p>
+		<br/>
+		<p>Time: %s</p>
+	`, digest.GeneratedAt.Format(time.RFC3339))
+	return subject, b.String()
+}
+
+// runDigestLoop sends the digest on its cron schedule until ctx is done.
+func (s *MonitorService) runDigestLoop(ctx context.Context) {
+	cron, err := schedule.ParseCron(s.digest.Cron)
+	if err != nil {
+		slog.Error("invalid digest cron expression", "event", "digest_cron_invalid", "cron", s.digest.Cron, "error", err)
+		return
+	}
+	for ctx.Err() == nil {
+		configChanged := s.configChangeSignal()
+		next := cron.Next(time.Now().In(s.getConfigSnapshot().ScheduleLocation()))
+		if next.IsZero() {
+			slog.Error("digest cron expression never fires", "event", "digest_cron_invalid", "cron", s.digest.Cron)
+			return
+		}
+		timer := time.NewTimer(time.Until(next))
+
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			return
+		case <-configChanged:
+			// The schedule zone may have changed.
+			stopTimer(timer)
+			continue
+		case <-timer.C:
+			s.sendDigest(ctx)
+		}
+	}
+}
+
+func (s *MonitorService) sendDigest(ctx context.Context) {
+	if !s.notifierEnabled() {
+		return
+	}
+	digest, err := s.BuildDigest(ctx, time.Now())
+	if err != nil {
+		slog.Error("failed to build digest", "event", "digest_build_failed", "error", err)
+		return
+	}
+	subject, body := s.RenderDigest(digest)
+
+	type recipientNotifier interface {
+		SendTo(ctx context.Context, to []string, subject, body string) error
+	}
+	notifier, ok := s.notifier.(recipientNotifier)
+	if len(s.digest.Recipients) > 0 && ok {
+		err = notifier.SendTo(ctx, s.digest.Recipients, subject, body)
+	} else {
+		err = s.notifier.Send(ctx, subject, body)
+	}
+	if err != nil {
+		slog.Error("failed to send digest", "event", "digest_send_failed", "error", err)
+		return
+	}
+	slog.Info("sent digest", "event", "digest_sent", "tiers", len(digest.Tiers))
+}
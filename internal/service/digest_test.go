@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/domain"
+)
+
+func TestBuildDigestSummarizesEachTier(t *testing.T) {
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	hour := func(h int) time.Time { return now.Add(time.Duration(h-24) * time.Hour) }
+	best := func(h int, amount, price float64, merchant string) *domain.PricePoint {
+		return &domain.PricePoint{CreatedAt: hour(h), Exchange: domain.ExchangeGate, Symbol: "USDT", Fiat: "CNY", Side: domain.SideBuy, TargetAmount: amount, Rank: 1, Price: price, Merchant: merchant}
+	}
+	repo := &stubRepository{
+		aggregatedPrices: []*domain.PricePoint{
+			best(1, 0, 7.25, "alice"),
+			best(2, 0, 7.10, "bob"),
+			best(3, 0, 7.15, "alice"),
+			best(4, 0, 7.30, "carol"),
+		},
+		aggregatedForex: []*domain.ForexRate{
+			{CreatedAt: hour(0), Rate: 7.20},
+			{CreatedAt: hour(3), Rate: 7.18},
+		},
+	}
+	svc := NewMonitorService(testMonitorConfig(), repo, nil, sourceAwareForex{rate: 7.2}, stubNotifier{})
+	svc.SetDigest(config.DigestConfig{PeriodHours: 24, TopMerchants: 2})
+	svc.setLastForex(testForexPair, 7.2, now)
+
+	digest, err := svc.BuildDigest(context.Background(), now)
+	if err != nil {
+		t.Fatalf("BuildDigest: %v", err)
+	}
+	if digest.Granularity != domain.HistoryGranularityHour || repo.lastGranularity != domain.HistoryGranularityHour || len(digest.Tiers) != 2 {
+		t.Fatalf("expected hourly summaries of two tiers, got %#v", digest)
+	}
+
+	tier := digest.Tiers[0]
+	if tier.Samples != 4 || tier.MinPrice != 7.10 || tier.MaxPrice != 7.30 || tier.AvgPrice < 7.199 || tier.AvgPrice > 7.201 {
+		t.Fatalf("unexpected price range: %#v", tier)
+	}
+	// Benchmark 7.2: the 7.10 and 7.15 hours were below it.
+	if tier.Benchmark != 7.2 || tier.HoursBelowBenchmark != 2 {
+		t.Fatalf("expected 2 hours below the 7.2 benchmark, got %#v", tier)
+	}
+	// 7.10 against the 7.20 rate of hour 0 beats 7.15 against 7.18.
+	if tier.MaxSpreadPercent == nil || *tier.MaxSpreadPercent < 1.38 || *tier.MaxSpreadPercent > 1.39 {
+		t.Fatalf("unexpected max spread: %v", tier.MaxSpreadPercent)
+	}
+	if len(tier.TopMerchants) != 2 || tier.TopMerchants[0] != (domain.DigestMerchant{Merchant: "alice", Buckets: 2}) || tier.TopMerchants[1].Merchant != "bob" {
+		t.Fatalf("unexpected top merchants: %#v", tier.TopMerchants)
+	}
+	if empty := digest.Tiers[1]; empty.TargetAmount != 30 || empty.Samples != 0 || empty.MaxSpreadPercent != nil {
+		t.Fatalf("expected the 30 tier without data, got %#v", empty)
+	}
+
+	subject, body := svc.RenderDigest(digest)
+	if !strings.Contains(subject, "2026-10-15 09:00") || !strings.Contains(body, "alice (2), bob (1)") || !strings.Contains(body, "No data") {
+		t.Fatalf("unexpected digest email: %s\n%s", subject, body)
+	}
+}
+
+func TestBuildDigestUsesDailyRollupsForLongPeriods(t *testing.T) {
+	repo := &stubRepository{}
+	svc := NewMonitorService(testMonitorConfig(), repo, nil, sourceAwareForex{rate: 7.2}, stubNotifier{})
+	svc.SetDigest(config.DigestConfig{PeriodHours: 30 * 24})
+
+	digest, err := svc.BuildDigest(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("BuildDigest: %v", err)
+	}
+	if digest.Granularity != domain.HistoryGranularityDay || repo.lastGranularity != domain.HistoryGranularityDay {
+		t.Fatalf("expected daily rollups, got %s", digest.Granularity)
+	}
+}
+
+func TestSendDigestUsesDigestRecipients(t *testing.T) {
+	notifier := &recipientRecordingNotifier{}
+	svc := NewMonitorService(testMonitorConfig(), &stubRepository{}, nil, sourceAwareForex{rate: 7.2}, notifier)
+	svc.SetDigest(config.DigestConfig{Recipients: []string{"desk@example.com"}})
+
+	svc.sendDigest(context.Background())
+
+	if len(notifier.to) != 1 || notifier.to[0] != "desk@example.com" || !strings.Contains(notifier.subject, "Market Digest") {
+		t.Fatalf("expected the digest to go to the digest recipients, got %v %q", notifier.to, notifier.subject)
+	}
+}
+
+type recipientRecordingNotifier struct {
+	to      []string
+	subject string
+}
+
+func (n *recipientRecordingNotifier) Send(ctx context.Context, subject, body string) error {
+	n.subject = subject
+	return nil
+}
+
+func (n *recipientRecordingNotifier) SendTo(ctx context.Context, to []string, subject, body string) error {
+	n.to = to
+	n.subject = subject
+	return nil
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/domain"
+)
+
+var ErrNotLeader = errors.New("this replica is not the leader")
+
+const leaseReleaseTimeout = 5 * time.Second
+
+// SetLeaderLease makes Start collect only while this replica holds the lease
+// named by election; other replicas keep serving reads. It must be called
+// before Start.
+func (s *MonitorService) SetLeaderLease(lease domain.ILeaderLease, election config.LeaderElectionConfig) {
+	s.lease = lease
+	s.election = election
+}
+
+// IsLeader reports whether this replica collects prices and sends alerts.
+func (s *MonitorService) IsLeader() bool {
+	return s.lease == nil || s.leading.Load()
+}
+
+// runLeaderElection tries to take or renew the lease every renew interval and
+// runs the collection loops while it holds it. A leader that cannot reach the
+// database keeps collecting only while its last renewal is safely valid, so a
+// successor never starts before it has stopped.
+func (s *MonitorService) runLeaderElection(ctx context.Context) {
+	election := s.election
+	ttl := time.Duration(election.LeaseSeconds) * time.Second
+	renew := time.Duration(election.RenewSeconds) * time.Second
+	logger := slog.With("lease", election.LeaseName, "holder", election.Identity)
+
+	var stopLeading context.CancelFunc
+	var stopped chan struct{}
+	var validUntil time.Time
+	stepDown := func(reason string) {
+		stopLeading()
+		<-stopped
+		stopLeading = nil
+		s.leading.Store(false)
+		s.setNextRun(&s.nextC2CRun, time.Time{})
+		s.setNextRun(&s.nextForexRun, time.Time{})
+		logger.Warn("stopped collecting as leader", "event", "leader_lost", "reason", reason)
+	}
+
+	for {
+		now := time.Now()
+		acquired, err := s.lease.AcquireLease(ctx, election.LeaseName, election.Identity, now, ttl)
+		switch {
+		case ctx.Err() != nil:
+		case err != nil:
+			logger.Error("failed to acquire leader lease", "event", "leader_lease_failed", "error", err)
+			if stopLeading != nil && time.Until(validUntil) <= renew {
+				stepDown("lease could not be renewed")
+			}
+		case acquired:
+			validUntil = now.Add(ttl)
+			if stopLeading == nil {
+				var leaderCtx context.Context
+				leaderCtx, stopLeading = context.WithCancel(ctx)
+				stopped = make(chan struct{})
+				s.leading.Store(true)
+				logger.Info("acquired leader lease; collecting", "event", "leader_elected")
+				go func(stopped chan struct{}) {
+					defer close(stopped)
+					s.runCollection(leaderCtx)
+				}(stopped)
+			}
+		case stopLeading != nil:
+			stepDown("lease is held by another replica")
+		default:
+			// Followers serve reads with the rates the leader persists.
+			s.syncForexFromRepository(ctx)
+		}
+
+		timer := time.NewTimer(renew)
+		select {
+		case <-ctx.Done():
+			stopTimer(timer)
+			if stopLeading != nil {
+				stepDown("shutting down")
+				// Let a successor take over without waiting for the lease to expire.
+				releaseCtx, cancel := context.WithTimeout(context.Background(), leaseReleaseTimeout)
+				if err := s.lease.ReleaseLease(releaseCtx, election.LeaseName, election.Identity); err != nil {
+					logger.Error("failed to release leader lease", "event", "leader_lease_release_failed", "error", err)
+				}
+				cancel()
+			}
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// syncForexFromRepository loads the latest persisted rate of every configured
+// pair that is newer than the one in memory.
+func (s *MonitorService) syncForexFromRepository(ctx context.Context) {
+	for _, pair := range s.getConfigSnapshot().ForexPairs() {
+		latest, err := s.repo.GetLatestForexRate(ctx, pair)
+		if err != nil {
+			slog.Error("failed to load cached forex rate", "event", "forex_cache_load_failed", "pair", pair, "error", err)
+			continue
+		}
+		if latest == nil || latest.Rate <= 0 {
+			continue
+		}
+		if _, observedAt := s.getLastForex(pair); latest.CreatedAt.After(observedAt) {
+			s.setLastForex(pair, latest.Rate, latest.CreatedAt)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/domain"
+)
+
+func TestLeaderElectionCollectsOnlyWhileHoldingTheLease(t *testing.T) {
+	lease := &testLease{holder: "other", expiresAt: time.Now().Add(time.Hour)}
+	repo := &stubRepository{latestForex: &domain.ForexRate{Pair: testForexPair, Rate: 7.1, CreatedAt: time.Now()}}
+	exchange := &marketRecordingExchange{}
+	svc := NewMonitorService(testMonitorConfig(), repo, map[string]domain.IExchange{domain.ExchangeGate: exchange}, sourceAwareForex{rate: 7.2}, stubNotifier{})
+	svc.SetLeaderLease(lease, config.LeaderElectionConfig{LeaseName: "c2c-monitor", Identity: "replica-1", LeaseSeconds: 2, RenewSeconds: 1})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.Start(ctx)
+	}()
+
+	waitFor(t, "follower to load the persisted forex rate", func() bool {
+		rate, _ := svc.getLastForex(testForexPair)
+		return rate == 7.1
+	})
+	if svc.IsLeader() || svc.GetSchedule().Leader || len(exchange.requestedTargets()) != 0 {
+		t.Fatal("expected a follower not to collect")
+	}
+	if _, err := svc.CollectNow(ctx, CollectRequest{C2C: true}); !errors.Is(err, ErrNotLeader) {
+		t.Fatalf("expected manual collection to be refused on a follower, got %v", err)
+	}
+
+	lease.expire()
+	waitFor(t, "replica to take over the expired lease", func() bool {
+		return svc.IsLeader() && len(exchange.requestedTargets()) > 0
+	})
+
+	cancel()
+	<-done
+	if svc.IsLeader() {
+		t.Fatal("expected leadership to end on shutdown")
+	}
+	if holder, expiresAt := lease.state(); holder != "replica-1" || expiresAt.After(time.Now()) {
+		t.Fatalf("expected the lease to be released on shutdown, got %s until %s", holder, expiresAt)
+	}
+}
+
+func waitFor(t *testing.T, what string, condition func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !condition() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+type testLease struct {
+	mu        sync.Mutex
+	holder    string
+	expiresAt time.Time
+}
+
+func (l *testLease) AcquireLease(ctx context.Context, name, holder string, now time.Time, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder != holder && now.Before(l.expiresAt) {
+		return false, nil
+	}
+	l.holder, l.expiresAt = holder, now.Add(ttl)
+	return true, nil
+}
+
+func (l *testLease) ReleaseLease(ctx context.Context, name, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.holder == holder {
+		l.expiresAt = time.Now()
+	}
+	return nil
+}
+
+func (l *testLease) expire() {
+	l.mu.Lock()
+	l.expiresAt = time.Time{}
+	l.mu.Unlock()
+}
+
+func (l *testLease) state() (string, time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.holder, l.expiresAt
+}
@@ -4,50 +4,73 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"html"
 	"io"
 	"log/slog"
 	"math"
-	"math/rand"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"c2c_monitor/config"
+	"c2c_monitor/internal/alertrender"
 	"c2c_monitor/internal/domain"
 	"c2c_monitor/internal/logging"
 )
 
 type MonitorService struct {
-	cfg                 config.MonitorConfig
-	repo                domain.IRepository
-	exchanges           map[string]domain.IExchange
-	forex               domain.IForex
-	notifier            domain.INotifier
-	lastForex           float64
-	lastForexAt         time.Time
-	cfgMu               sync.RWMutex
-	forexMu             sync.RWMutex
-	benchmarkMu         sync.RWMutex
-	alertBenchmark      float64
-	alertBenchmarkDirty bool
-	benchmarkOverrides  map[float64]float64
-	scheduleMu          sync.Mutex
-	configChanged       chan struct{}
-	errorAlertCache     map[string]time.Time             // To prevent spamming error alerts
-	triggeredLowPrices  map[string]float64               // To store the lowest triggered price for dynamic threshold
-	serviceStatus       map[string]*domain.ServiceStatus // Track status of each service
-	downEventLogger     *slog.Logger
-	mu                  sync.RWMutex // Mutex for protecting maps
+	cfg                config.MonitorConfig
+	repo               domain.IRepository
+	exchanges          map[string]domain.IExchange
+	forex              domain.IForex
+	notifier           domain.INotifier
+	forexRates         map[string]forexSnapshot // Latest usable rate per Forex pair
+	cfgMu              sync.RWMutex
+	forexMu            sync.RWMutex
+	benchmarkMu        sync.RWMutex
+	alertBenchmarks    map[string]float64             // Default benchmark per market key
+	dirtyBenchmarks    map[string]bool                // Benchmarks whose persistence must be retried
+	benchmarkOverrides map[string]map[float64]float64 // Amount-tier benchmarks per market key
+	loadedBenchmarks   map[string]bool                // Markets whose persisted benchmarks were restored
+	scheduleMu         sync.Mutex
+	configChanged      chan struct{}
+	nextC2CRun         time.Time                        // Next planned C2C run; guarded by scheduleMu
+	nextForexRun       time.Time                        // Next planned Forex run; guarded by scheduleMu
+	errorAlertCache    map[string]time.Time             // To prevent spamming error alerts
+	forexStaleAlerted  bool                             // A forex stale alert was queued and the rates are still unusable
+	triggeredLowPrices map[string]float64               // To store the lowest triggered price for dynamic threshold
+	triggeredSpreads   map[string]float64               // Highest alerted round-trip spread per exchange pair and amount
+	latestPrices       map[string]domain.PricePoint     // Best ad of the last run per alert state key, unfiltered tiers only
+	serviceStatus      map[string]*domain.ServiceStatus // Track status of each service
+	breakers           map[string]*circuitBreaker       // Circuit breaker per exchange
+	limiters           map[string]*rateLimiter          // Request budget per exchange
+	fetchSem           chan struct{}                    // Bounds in-flight exchange requests across concurrent runs
+	c2cScheduler       *c2cScheduler                    // Shared by the C2C loop and manual collections
+	forexRunMu         sync.Mutex                       // Serialises Forex updates
+	lease              domain.ILeaderLease              // Nil when this instance always collects
+	election           config.LeaderElectionConfig
+	leading            atomic.Bool // Holds the lease and runs the collection loops
+	digest             config.DigestConfig
+	alertRenderer      *alertrender.Renderer
+	downEventLogger    *slog.Logger
+	deliveries         sync.WaitGroup // First alert deliveries still running
+	mu                 sync.RWMutex   // Mutex for protecting maps
 }
 
 const forexServiceName = "Forex (Reference Sources)"
-const alertBenchmarkPair = "USDCNY"
-const serviceDownLogPath = "logs/service_down.log"
+const maxConcurrentFetches = 6
+
+type forexSnapshot struct {
+	rate       float64
+	observedAt time.Time
+}
 
 var ErrInvalidAlertBenchmark = errors.New("invalid alert benchmark")
+var ErrInvalidMarket = errors.New("invalid market")
 
 func NewMonitorService(
 	cfg config.MonitorConfig,
@@ -66,12 +89,23 @@ func NewMonitorService(
 		configChanged:      make(chan struct{}),
 		errorAlertCache:    make(map[string]time.Time),
 		triggeredLowPrices: make(map[string]float64),
-		benchmarkOverrides: make(map[float64]float64),
+		triggeredSpreads:   make(map[string]float64),
+		latestPrices:       make(map[string]domain.PricePoint),
+		forexRates:         make(map[string]forexSnapshot),
+		alertBenchmarks:    make(map[string]float64),
+		dirtyBenchmarks:    make(map[string]bool),
+		benchmarkOverrides: make(map[string]map[float64]float64),
+		loadedBenchmarks:   make(map[string]bool),
 		serviceStatus:      make(map[string]*domain.ServiceStatus),
-		downEventLogger:    newServiceDownLogger(serviceDownLogPath),
+		breakers:           make(map[string]*circuitBreaker),
+		limiters:           make(map[string]*rateLimiter),
+		fetchSem:           make(chan struct{}, maxConcurrentFetches),
+		c2cScheduler:       newC2CScheduler(),
+		alertRenderer:      alertrender.Default(),
 	}
 
 	ms.syncConfiguredServiceStatuses(cfgCopy.Exchanges)
+	ms.warnUnsupportedPayMethods(cfgCopy)
 
 	return ms
 }
@@ -84,6 +118,55 @@ func cloneMonitorConfig(cfg config.MonitorConfig) config.MonitorConfig {
 	if cfg.Exchanges != nil {
 		copyCfg.Exchanges = append([]string(nil), cfg.Exchanges...)
 	}
+	if cfg.Sides != nil {
+		copyCfg.Sides = append([]string(nil), cfg.Sides...)
+	}
+	if cfg.PayMethodFilters != nil {
+		copyCfg.PayMethodFilters = append([]string(nil), cfg.PayMethodFilters...)
+	}
+	if cfg.Markets != nil {
+		copyCfg.Markets = make([]config.MarketConfig, len(cfg.Markets))
+		for i, market := range cfg.Markets {
+			copyCfg.Markets[i] = market
+			if market.TargetAmounts != nil {
+				copyCfg.Markets[i].TargetAmounts = append([]float64(nil), market.TargetAmounts...)
+			}
+		}
+	}
+	if cfg.PollSchedules != nil {
+		copyCfg.PollSchedules = make([]config.PollScheduleConfig, len(cfg.PollSchedules))
+		for i, schedule := range cfg.PollSchedules {
+			copyCfg.PollSchedules[i] = schedule
+			if schedule.Amounts != nil {
+				copyCfg.PollSchedules[i].Amounts = append([]float64(nil), schedule.Amounts...)
+			}
+		}
+	}
+	if cfg.CollectionBlackouts != nil {
+		copyCfg.CollectionBlackouts = make([]config.BlackoutWindowConfig, len(cfg.CollectionBlackouts))
+		for i, blackout := range cfg.CollectionBlackouts {
+			copyCfg.CollectionBlackouts[i] = blackout
+			if blackout.Weekdays != nil {
+				copyCfg.CollectionBlackouts[i].Weekdays = append([]string(nil), blackout.Weekdays...)
+			}
+		}
+	}
+	if cfg.CustomExchanges != nil {
+		// Custom exchanges are read-only after startup; copying the slice is enough.
+		copyCfg.CustomExchanges = append([]config.CustomExchangeConfig(nil), cfg.CustomExchanges...)
+	}
+	if cfg.ExchangeRateLimits != nil {
+		copyCfg.ExchangeRateLimits = make(map[string]config.RateLimitConfig, len(cfg.ExchangeRateLimits))
+		for name, limit := range cfg.ExchangeRateLimits {
+			copyCfg.ExchangeRateLimits[name] = limit
+		}
+	}
+	if cfg.ExchangeDepths != nil {
+		copyCfg.ExchangeDepths = make(map[string]int, len(cfg.ExchangeDepths))
+		for name, depth := range cfg.ExchangeDepths {
+			copyCfg.ExchangeDepths[name] = depth
+		}
+	}
 	return copyCfg
 }
 
@@ -93,23 +176,23 @@ func (s *MonitorService) getConfigSnapshot() config.MonitorConfig {
 	return cloneMonitorConfig(s.cfg)
 }
 
-func (s *MonitorService) setLastForex(rate float64, observedAt time.Time) {
+func (s *MonitorService) setLastForex(pair string, rate float64, observedAt time.Time) {
 	s.forexMu.Lock()
-	s.lastForex = rate
-	s.lastForexAt = observedAt
+	s.forexRates[pair] = forexSnapshot{rate: rate, observedAt: observedAt}
 	s.forexMu.Unlock()
 }
 
-func (s *MonitorService) getLastForex() (float64, time.Time) {
+func (s *MonitorService) getLastForex(pair string) (float64, time.Time) {
 	s.forexMu.RLock()
 	defer s.forexMu.RUnlock()
-	return s.lastForex, s.lastForexAt
+	snapshot := s.forexRates[pair]
+	return snapshot.rate, snapshot.observedAt
 }
 
-func (s *MonitorService) usableForex(now time.Time) (float64, error) {
-	rate, observedAt := s.getLastForex()
+func (s *MonitorService) usableForex(pair string, now time.Time) (float64, error) {
+	rate, observedAt := s.getLastForex(pair)
 	if math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 || observedAt.IsZero() {
-		return 0, fmt.Errorf("forex rate is unavailable")
+		return 0, fmt.Errorf("forex rate %s is unavailable", pair)
 	}
 
 	cfg := s.getConfigSnapshot()
@@ -118,12 +201,23 @@ func (s *MonitorService) usableForex(now time.Time) (float64, error) {
 		maxAge = 6 * time.Hour
 	}
 	if age := now.Sub(observedAt); age > maxAge {
-		return 0, fmt.Errorf("forex rate is stale: age %s exceeds %s", age.Round(time.Second), maxAge)
+		return 0, fmt.Errorf("forex rate %s is stale: age %s exceeds %s", pair, age.Round(time.Second), maxAge)
 	}
 
 	return rate, nil
 }
 
+// unusableForex reports every configured Forex pair that cannot back alerts.
+func (s *MonitorService) unusableForex(now time.Time) error {
+	var errs []error
+	for _, pair := range s.getConfigSnapshot().ForexPairs() {
+		if _, err := s.usableForex(pair, now); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
 func (s *MonitorService) configChangeSignal() <-chan struct{} {
 	s.scheduleMu.Lock()
 	defer s.scheduleMu.Unlock()
@@ -137,6 +231,29 @@ func (s *MonitorService) notifyConfigChanged() {
 	s.scheduleMu.Unlock()
 }
 
+func (s *MonitorService) setNextRun(field *time.Time, next time.Time) {
+	s.scheduleMu.Lock()
+	*field = next
+	s.scheduleMu.Unlock()
+}
+
+// GetSchedule reports when the collection loops next fire.
+func (s *MonitorService) GetSchedule() domain.ScheduleStatus {
+	s.scheduleMu.Lock()
+	defer s.scheduleMu.Unlock()
+
+	status := domain.ScheduleStatus{Leader: s.IsLeader()}
+	if !s.nextC2CRun.IsZero() {
+		next := s.nextC2CRun
+		status.NextC2CRun = &next
+	}
+	if !s.nextForexRun.IsZero() {
+		next := s.nextForexRun
+		status.NextForexRun = &next
+	}
+	return status
+}
+
 func (s *MonitorService) syncConfiguredServiceStatuses(exchangeNames []string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -198,7 +315,9 @@ func (s *MonitorService) updateServiceHealth(name, statusValue, message string)
 	status.Message = message
 
 	shouldSendErrorAlert := false
+	shouldSendRecoveryAlert := false
 	if statusValue != "Error" {
+		_, shouldSendRecoveryAlert = s.errorAlertCache[name]
 		delete(s.errorAlertCache, name)
 	} else if statusValue == "Error" && s.notifierEnabled() {
 		if _, exists := s.errorAlertCache[name]; !exists {
@@ -213,13 +332,27 @@ func (s *MonitorService) updateServiceHealth(name, statusValue, message string)
 		s.logServiceDown(name, err)
 	}
 	if shouldSendErrorAlert {
-		go s.sendErrorAlert(name, fmt.Errorf("%s", message))
+		s.sendErrorAlert(name, fmt.Errorf("%s", message))
+	}
+	if shouldSendRecoveryAlert {
+		s.sendRecoveryAlert(name, statusValue, message)
 	}
 	if statusValue == "OK" && previousStatus != "" && previousStatus != "Pending" && previousStatus != "OK" {
 		slog.Info("service recovered", "event", "service_recovered", "service", name)
 	}
 }
 
+// SetServiceDownLog appends service down events as JSON lines to the file at
+// path; an empty path, like not calling it, turns the file off. It must be
+// called before Start.
+func (s *MonitorService) SetServiceDownLog(path string) {
+	if path == "" {
+		s.downEventLogger = nil
+		return
+	}
+	s.downEventLogger = newServiceDownLogger(path)
+}
+
 func newServiceDownLogger(path string) *slog.Logger {
 	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
 		slog.Error("failed to create service down log directory", "event", "service_down_log_dir_failed", "path", path, "error", err)
@@ -246,20 +379,10 @@ func (s *MonitorService) sendErrorAlert(name string, err error) {
 	if !s.notifierEnabled() {
 		return
 	}
-	subject := fmt.Sprintf("⚠️ [C2C Monitor] Service Down: %s", name)
-	body := fmt.Sprintf(`
-		<h3>Service Status Change</h3>
-		<p><b>Service:</b> %s</p>
-		<p><b>Status:</b> <span style="color:red; font-weight:bold;">ERROR</span></p>
-		<p><b>Details:</b> %v</p>
-		<p><i>Alert sent once. Will not alert again until service recovers and fails again.</i></p>
-		<br/>
-		<p>Time: %s</p>
-	`, html.EscapeString(name), html.EscapeString(err.Error()), time.Now().Format(time.RFC3339))
-
-	slog.Warn("sending error alert", "event", "error_alert_sending", "service", name, "subject", subject)
-	if notifErr := s.notifier.Send(context.Background(), subject, body); notifErr != nil {
-		slog.Error("failed to send error alert email", "event", "error_alert_send_failed", "service", name, "error", notifErr)
+	event := domain.AlertEvent{Type: domain.AlertEventServiceDown, Time: time.Now(), Service: name, Details: err.Error()}
+	slog.Warn("sending error alert", "event", "error_alert_sending", "service", name)
+	if queueErr := s.enqueueAlert(context.Background(), event, name, nil); queueErr != nil {
+		slog.Error("failed to queue error alert", "event", "error_alert_enqueue_failed", "service", name, "error", queueErr)
 		s.mu.Lock()
 		delete(s.errorAlertCache, name)
 		s.mu.Unlock()
@@ -267,16 +390,37 @@ func (s *MonitorService) sendErrorAlert(name string, err error) {
 	}
 }
 
-func (s *MonitorService) getNextC2CDuration() time.Duration {
-	cfg := s.getConfigSnapshot()
-	baseMinutes := cfg.C2CIntervalMinutes
-	if baseMinutes <= 0 {
-		baseMinutes = 3
+// sendRecoveryAlert follows up a service-down alert once the service is no
+// longer in error.
+func (s *MonitorService) sendRecoveryAlert(name, statusValue, message string) {
+	if !s.notifierEnabled() {
+		return
+	}
+	event := domain.AlertEvent{Type: domain.AlertEventServiceRecovered, Time: time.Now(), Service: name, Status: statusValue, Details: message}
+	slog.Info("sending recovery alert", "event", "recovery_alert_sending", "service", name, "status", statusValue)
+	if err := s.enqueueAlert(context.Background(), event, name, nil); err != nil {
+		slog.Error("failed to queue recovery alert", "event", "recovery_alert_enqueue_failed", "service", name, "error", err)
+	}
+}
+
+// checkForexStale alerts once when opportunity alerts pause because no usable
+// forex rate is left, and re-arms when every rate is usable again.
+func (s *MonitorService) checkForexStale(ctx context.Context, unusable error) {
+	s.mu.Lock()
+	alerted := s.forexStaleAlerted
+	s.forexStaleAlerted = unusable != nil && (alerted || s.notifierEnabled())
+	s.mu.Unlock()
+	if unusable == nil || alerted || !s.notifierEnabled() {
+		return
+	}
+
+	event := domain.AlertEvent{Type: domain.AlertEventForexStale, Time: time.Now(), Service: forexServiceName, Details: unusable.Error()}
+	if err := s.enqueueAlert(ctx, event, "forex_stale", nil); err != nil {
+		slog.Error("failed to queue forex stale alert", "event", "forex_stale_alert_enqueue_failed", "error", err)
+		s.mu.Lock()
+		s.forexStaleAlerted = false
+		s.mu.Unlock()
 	}
-	base := time.Duration(baseMinutes) * time.Minute
-	// Add random jitter: 0 to 60 seconds
-	jitter := time.Duration(rand.Intn(61)) * time.Second
-	return base + jitter
 }
 
 func (s *MonitorService) loadPersistedAlertStates(ctx context.Context) {
@@ -290,43 +434,60 @@ func (s *MonitorService) loadPersistedAlertStates(ctx context.Context) {
 	defer s.mu.Unlock()
 
 	for _, state := range states {
-		key := domain.AlertStateKey(state.Exchange, state.Side, state.TargetAmount)
+		key := domain.AlertStateKey(state.Exchange, domain.MarketKey(state.Symbol, state.Fiat), state.Side, state.TargetAmount, state.PayMethodFilter)
 		s.triggeredLowPrices[key] = state.TriggerPrice
 	}
 
 	slog.Info("loaded persisted alert states", "event", "alert_states_loaded", "count", len(states))
 }
 
-func (s *MonitorService) loadPersistedAlertBenchmark(ctx context.Context) {
-	benchmark, err := s.repo.GetAlertBenchmark(ctx, alertBenchmarkPair)
-	if err != nil {
-		slog.Error("failed to load persisted alert benchmark", "event", "alert_benchmark_load_failed", "error", err)
+// ensureMarketBenchmarksLoaded restores a market's persisted benchmarks the
+// first time the market is used, so markets added at runtime never overwrite a
+// lower persisted value. Failed loads are retried on the next use.
+func (s *MonitorService) ensureMarketBenchmarksLoaded(ctx context.Context, market string) {
+	s.benchmarkMu.RLock()
+	loaded := s.loadedBenchmarks[market]
+	s.benchmarkMu.RUnlock()
+	if loaded {
 		return
 	}
-	if benchmark == nil {
+
+	benchmark, err := s.repo.GetAlertBenchmark(ctx, market)
+	if err != nil {
+		slog.Error("failed to load persisted alert benchmark", "event", "alert_benchmark_load_failed", "market", market, "error", err)
 		return
 	}
-	if math.IsNaN(benchmark.Price) || math.IsInf(benchmark.Price, 0) || benchmark.Price <= 0 {
-		slog.Error("persisted alert benchmark is invalid", "event", "alert_benchmark_invalid", "price", benchmark.Price)
+	overrides, err := s.repo.GetAlertBenchmarkOverrides(ctx, market)
+	if err != nil {
+		slog.Error("failed to load persisted amount benchmarks", "event", "alert_benchmark_overrides_load_failed", "market", market, "error", err)
 		return
 	}
 
 	s.benchmarkMu.Lock()
-	s.alertBenchmark = benchmark.Price
-	s.alertBenchmarkDirty = false
-	s.benchmarkMu.Unlock()
-	slog.Info("loaded persisted alert benchmark", "event", "alert_benchmark_loaded", "price", benchmark.Price)
-}
-
-func (s *MonitorService) loadPersistedAlertBenchmarkOverrides(ctx context.Context) {
-	overrides, err := s.repo.GetAlertBenchmarkOverrides(ctx, alertBenchmarkPair)
-	if err != nil {
-		slog.Error("failed to load persisted amount benchmarks", "event", "alert_benchmark_overrides_load_failed", "error", err)
+	defer s.benchmarkMu.Unlock()
+	if s.loadedBenchmarks[market] {
 		return
 	}
+	s.loadedBenchmarks[market] = true
+
+	if benchmark != nil {
+		if math.IsNaN(benchmark.Price) || math.IsInf(benchmark.Price, 0) || benchmark.Price <= 0 {
+			slog.Error("persisted alert benchmark is invalid", "event", "alert_benchmark_invalid", "market", market, "price", benchmark.Price)
+		} else if current, exists := s.alertBenchmarks[market]; !exists || benchmark.Price <= current {
+			s.alertBenchmarks[market] = benchmark.Price
+			s.dirtyBenchmarks[market] = false
+			slog.Info("loaded persisted alert benchmark", "event", "alert_benchmark_loaded", "market", market, "price", benchmark.Price)
+		} else {
+			// The in-memory benchmark moved lower while the database was unreachable.
+			s.dirtyBenchmarks[market] = true
+		}
+	}
 
-	s.benchmarkMu.Lock()
-	defer s.benchmarkMu.Unlock()
+	marketOverrides := s.benchmarkOverrides[market]
+	if marketOverrides == nil {
+		marketOverrides = make(map[float64]float64)
+		s.benchmarkOverrides[market] = marketOverrides
+	}
 	for _, override := range overrides {
 		if math.IsNaN(override.TargetAmount) || math.IsInf(override.TargetAmount, 0) || override.TargetAmount < 0 {
 			continue
@@ -334,59 +495,64 @@ func (s *MonitorService) loadPersistedAlertBenchmarkOverrides(ctx context.Contex
 		if math.IsNaN(override.Price) || math.IsInf(override.Price, 0) || override.Price <= 0 {
 			continue
 		}
-		s.benchmarkOverrides[override.TargetAmount] = override.Price
+		if current, exists := marketOverrides[override.TargetAmount]; !exists || override.Price < current {
+			marketOverrides[override.TargetAmount] = override.Price
+		}
 	}
-	slog.Info("loaded persisted amount benchmarks", "event", "alert_benchmark_overrides_loaded", "count", len(s.benchmarkOverrides))
+	slog.Info("loaded persisted amount benchmarks", "event", "alert_benchmark_overrides_loaded", "market", market, "count", len(marketOverrides))
 }
 
 // Start begins the monitoring loops
 func (s *MonitorService) Start(ctx context.Context) {
 	slog.Info("monitor service started", "event", "monitor_service_started")
+	if s.lease != nil {
+		s.runLeaderElection(ctx)
+	} else {
+		s.runCollection(ctx)
+	}
+	slog.Info("monitor service stopping", "event", "monitor_service_stopping")
+}
 
+// runCollection runs the collection loops, which raise every alert, the alert
+// outbox worker and, when enabled, the digest loop until ctx is done.
+func (s *MonitorService) runCollection(ctx context.Context) {
 	// Recover persisted dynamic thresholds and cooldown timestamps.
 	s.loadPersistedAlertStates(ctx)
-	s.loadPersistedAlertBenchmark(ctx)
-	s.loadPersistedAlertBenchmarkOverrides(ctx)
+	for _, market := range s.getConfigSnapshot().CollectedMarkets() {
+		s.ensureMarketBenchmarksLoaded(ctx, market.Key())
+	}
 
 	// Initial Forex fetch
 	s.updateForex(ctx)
 
-	go s.runC2CLoop(ctx)
-	s.runForexLoop(ctx)
-	slog.Info("monitor service stopping", "event", "monitor_service_stopping")
-}
-
-func (s *MonitorService) runC2CLoop(ctx context.Context) {
-	s.checkC2C(ctx)
-
-	for ctx.Err() == nil {
-		configChanged := s.configChangeSignal()
-		nextInterval := s.getNextC2CDuration()
-		timer := time.NewTimer(nextInterval)
-		slog.Info("scheduled next c2c check", "event", "c2c_check_scheduled", "delay", nextInterval.String())
-
-		select {
-		case <-ctx.Done():
-			stopTimer(timer)
-			return
-		case <-configChanged:
-			stopTimer(timer)
-			continue
-		case <-timer.C:
-			s.checkC2C(ctx)
-		}
+	var loops sync.WaitGroup
+	loops.Add(2)
+	go func() {
+		defer loops.Done()
+		s.runC2CLoop(ctx)
+	}()
+	go func() {
+		defer loops.Done()
+		s.runAlertOutbox(ctx)
+	}()
+	if s.digest.Enabled {
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			s.runDigestLoop(ctx)
+		}()
 	}
+	s.runForexLoop(ctx)
+	loops.Wait()
+	s.waitForDeliveries()
 }
 
 func (s *MonitorService) runForexLoop(ctx context.Context) {
 	for ctx.Err() == nil {
 		configChanged := s.configChangeSignal()
-		cfg := s.getConfigSnapshot()
-		interval := time.Duration(cfg.ForexIntervalHours) * time.Hour
-		if interval <= 0 {
-			interval = time.Hour
-		}
-		timer := time.NewTimer(interval)
+		next := nextForexRun(s.getConfigSnapshot(), time.Now())
+		s.setNextRun(&s.nextForexRun, next)
+		timer := time.NewTimer(time.Until(next))
 
 		select {
 		case <-ctx.Done():
@@ -411,61 +577,100 @@ func stopTimer(timer *time.Timer) {
 }
 
 func (s *MonitorService) updateForex(ctx context.Context) {
-	rate, err := s.forex.GetRate(ctx, "USD", "CNY")
-	if err == nil && (math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0) {
-		err = fmt.Errorf("forex source returned invalid rate")
+	s.forexRunMu.Lock()
+	defer s.forexRunMu.Unlock()
+	cfg := s.getConfigSnapshot()
+
+	var failures []string
+	attempted := 0
+	seen := make(map[string]struct{})
+	for _, market := range cfg.CollectedMarkets() {
+		pair := market.ForexPair()
+		if pair == "" {
+			continue
+		}
+		if _, exists := seen[pair]; exists {
+			continue
+		}
+		seen[pair] = struct{}{}
+		attempted++
+		if err := s.updateForexPair(ctx, market.ForexBase, market.Fiat); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", pair, err))
+		}
 	}
 
-	// Update Status
-	if err != nil {
-		s.updateServiceStatus(forexServiceName, err)
-	} else {
-		s.updateServiceStatus(forexServiceName, nil)
+	switch {
+	case len(failures) == 0:
+		s.updateServiceHealth(forexServiceName, "OK", "")
+	case len(failures) < attempted:
+		s.updateServiceHealth(forexServiceName, "Degraded", strings.Join(failures, "; "))
+	default:
+		s.updateServiceHealth(forexServiceName, "Error", strings.Join(failures, "; "))
+	}
+}
+
+// updateForexPair refreshes one Forex pair, falling back to the latest
+// persisted rate while it is still fresh. The returned error is the upstream
+// failure, even when the cached rate was usable.
+func (s *MonitorService) updateForexPair(ctx context.Context, from, to string) error {
+	pair := from + to
+	rate, err := s.forex.GetRate(ctx, from, to)
+	if err == nil && (math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0) {
+		err = fmt.Errorf("forex source returned invalid rate")
 	}
 
 	if err != nil {
-		slog.Error("failed to fetch forex rate", "event", "forex_fetch_failed", "pair", "USDCNY", "error", err)
+		slog.Error("failed to fetch forex rate", "event", "forex_fetch_failed", "pair", pair, "error", err)
 		// Try to load latest from DB if fetch fails
-		latest, dbErr := s.repo.GetLatestForexRate(ctx, "USDCNY")
+		latest, dbErr := s.repo.GetLatestForexRate(ctx, pair)
 		if dbErr != nil {
-			slog.Error("failed to load cached forex rate", "event", "forex_cache_load_failed", "pair", "USDCNY", "error", dbErr)
-			return
+			slog.Error("failed to load cached forex rate", "event", "forex_cache_load_failed", "pair", pair, "error", dbErr)
+			return err
 		}
 		if latest != nil {
 			if math.IsNaN(latest.Rate) || math.IsInf(latest.Rate, 0) || latest.Rate <= 0 {
-				slog.Error("cached forex rate is invalid", "event", "forex_cache_invalid", "pair", "USDCNY", "rate", latest.Rate)
-				return
+				slog.Error("cached forex rate is invalid", "event", "forex_cache_invalid", "pair", pair, "rate", latest.Rate)
+				return err
 			}
 			maxAge := time.Duration(s.getConfigSnapshot().ForexMaxAgeHours) * time.Hour
 			if maxAge <= 0 {
 				maxAge = 6 * time.Hour
 			}
 			if latest.CreatedAt.IsZero() || time.Since(latest.CreatedAt) > maxAge {
-				slog.Error("cached forex rate is stale", "event", "forex_cache_stale", "pair", "USDCNY", "observed_at", latest.CreatedAt, "max_age", maxAge.String())
-				return
+				slog.Error("cached forex rate is stale", "event", "forex_cache_stale", "pair", pair, "observed_at", latest.CreatedAt, "max_age", maxAge.String())
+				return err
 			}
-			s.setLastForex(latest.Rate, latest.CreatedAt)
-			s.reconcileAlertBenchmark(ctx, latest.Rate)
-			slog.Warn("using cached forex rate from database", "event", "forex_cache_used", "pair", "USDCNY", "rate", latest.Rate, "source", latest.Source, "observed_at", latest.CreatedAt)
+			s.setLastForex(pair, latest.Rate, latest.CreatedAt)
+			s.reconcileForexMarkets(ctx, pair, latest.Rate)
+			slog.Warn("using cached forex rate from database", "event", "forex_cache_used", "pair", pair, "rate", latest.Rate, "source", latest.Source, "observed_at", latest.CreatedAt)
 		}
-		return
+		return err
 	}
 
 	sourceName := s.forexSourceName()
 	now := time.Now()
-	s.setLastForex(rate, now)
-	s.reconcileAlertBenchmark(ctx, rate)
-	slog.Info("updated forex rate", "event", "forex_updated", "pair", "USDCNY", "rate", rate, "source", sourceName)
+	s.setLastForex(pair, rate, now)
+	s.reconcileForexMarkets(ctx, pair, rate)
+	slog.Info("updated forex rate", "event", "forex_updated", "pair", pair, "rate", rate, "source", sourceName)
 
 	// Save to DB
-	err = s.repo.SaveForexRate(ctx, &domain.ForexRate{
+	if err := s.repo.SaveForexRate(ctx, &domain.ForexRate{
 		CreatedAt: now,
 		Source:    sourceName,
-		Pair:      "USDCNY",
+		Pair:      pair,
 		Rate:      rate,
-	})
-	if err != nil {
-		slog.Error("failed to save forex rate", "event", "forex_save_failed", "pair", "USDCNY", "source", sourceName, "error", err)
+	}); err != nil {
+		slog.Error("failed to save forex rate", "event", "forex_save_failed", "pair", pair, "source", sourceName, "error", err)
+	}
+	return nil
+}
+
+// reconcileForexMarkets lowers the benchmark of every market referencing pair.
+func (s *MonitorService) reconcileForexMarkets(ctx context.Context, pair string, rate float64) {
+	for _, market := range s.getConfigSnapshot().CollectedMarkets() {
+		if market.ForexPair() == pair {
+			s.reconcileAlertBenchmark(ctx, market.Key(), rate)
+		}
 	}
 }
 
@@ -492,18 +697,40 @@ func (s *MonitorService) forexSourceName() string {
 	return "External FX API"
 }
 
+// checkC2C collects every configured target.
 func (s *MonitorService) checkC2C(ctx context.Context) {
-	if _, err := s.usableForex(time.Now()); err != nil {
-		s.updateServiceStatus(forexServiceName, err)
-		slog.Warn("collecting c2c prices without opportunity alerts because forex rate is unusable", "event", "c2c_alerts_paused_forex", "error", err)
+	s.checkC2CTargets(ctx, nil)
+}
+
+// c2cRunResult is what one C2C run collected.
+type c2cRunResult struct {
+	near   map[c2cTarget]bool  // Targets whose best BUY price is close to the alert benchmark
+	prices []domain.PricePoint // Ranked ads that were saved
+}
+
+// checkC2CTargets collects the given targets, or every configured target when
+// targets is nil. Exchanges without a target in the run keep their health.
+func (s *MonitorService) checkC2CTargets(ctx context.Context, targets []c2cTarget) c2cRunResult {
+	unusable := s.unusableForex(time.Now())
+	if unusable != nil {
+		s.updateServiceStatus(forexServiceName, unusable)
+		slog.Warn("collecting c2c prices without opportunity alerts because forex rate is unusable", "event", "c2c_alerts_paused_forex", "error", unusable)
 	}
+	s.checkForexStale(ctx, unusable)
 
 	cfg := s.getConfigSnapshot()
 
+	// A job is one request: a single amount tier, or for order-book
+	// exchanges every amount tier of a market, side and pay method.
 	type c2cJob struct {
-		name     string
-		exchange domain.IExchange
-		amount   float64
+		name      string
+		exchange  domain.IExchange
+		orderBook domain.IOrderBookExchange
+		market    config.MarketConfig
+		side      string
+		amounts   []float64
+		payMethod string
+		depth     int
 	}
 
 	var jobs []c2cJob
@@ -515,82 +742,176 @@ func (s *MonitorService) checkC2C(ctx context.Context) {
 		errors    []string
 	}
 	results := make(map[string]*exchangeResult, len(cfg.Exchanges))
+	sides := cfg.CollectedSides()
+	markets := cfg.CollectedMarkets()
+	payMethods := cfg.CollectedPayMethods()
+	var scheduled map[c2cTarget]bool
+	if targets != nil {
+		scheduled = make(map[c2cTarget]bool, len(targets))
+		for _, target := range targets {
+			scheduled[target] = true
+		}
+	}
+	dueAmounts := func(name string, market config.MarketConfig) []float64 {
+		if scheduled == nil {
+			return cfg.AmountsFor(market)
+		}
+		var amounts []float64
+		for _, amount := range cfg.AmountsFor(market) {
+			if scheduled[c2cTarget{exchange: name, market: market.Key(), amount: amount}] {
+				amounts = append(amounts, amount)
+			}
+		}
+		return amounts
+	}
 
 	for _, name := range cfg.Exchanges {
-		result := &exchangeResult{attempted: len(cfg.TargetAmounts)}
-		results[name] = result
 		exchange, ok := s.exchanges[name]
+		exchangePayMethods := payMethods
+		if ok {
+			exchangePayMethods = collectablePayMethods(exchange, payMethods)
+		}
+		tiers := 0
+		for _, market := range markets {
+			tiers += len(sides) * len(dueAmounts(name, market)) * len(exchangePayMethods)
+		}
+		if tiers == 0 {
+			continue
+		}
+		result := &exchangeResult{attempted: tiers}
+		results[name] = result
 		if !ok {
 			result.failed = result.attempted
 			result.errors = append(result.errors, "adapter is not configured")
 			continue
 		}
-		for _, amount := range cfg.TargetAmounts {
-			jobs = append(jobs, c2cJob{
-				name:     name,
-				exchange: exchange,
-				amount:   amount,
-			})
+		depth := cfg.DepthFor(name)
+		orderBook, _ := exchange.(domain.IOrderBookExchange)
+		for _, market := range markets {
+			amounts := dueAmounts(name, market)
+			if len(amounts) == 0 {
+				continue
+			}
+			for _, side := range sides {
+				for _, payMethod := range exchangePayMethods {
+					job := c2cJob{
+						name:      name,
+						exchange:  exchange,
+						orderBook: orderBook,
+						market:    market,
+						side:      side,
+						payMethod: payMethod,
+						depth:     depth,
+					}
+					if orderBook != nil {
+						job.amounts = amounts
+						jobs = append(jobs, job)
+						continue
+					}
+					for _, amount := range amounts {
+						job.amounts = []float64{amount}
+						jobs = append(jobs, job)
+					}
+				}
+			}
 		}
 	}
 
-	const maxConcurrentFetches = 6
-	sem := make(chan struct{}, maxConcurrentFetches)
+	sem := s.fetchSem
 	var wg sync.WaitGroup
 
 	var resultMu sync.Mutex
+	var bestPrices []domain.PricePoint
+	run := c2cRunResult{near: make(map[c2cTarget]bool)}
+	collectTier := func(job c2cJob, amount float64, prices []domain.PricePoint) {
+		prices = payMethodTierPrices(prices, job.payMethod)
+		prices = topRankedPrices(merchantFilteredPrices(prices, cfg.MerchantFilter), job.depth)
+
+		resultMu.Lock()
+		if len(prices) == 0 {
+			results[job.name].empty++
+		} else {
+			results[job.name].succeeded++
+		}
+		resultMu.Unlock()
+
+		if len(prices) == 0 {
+			return
+		}
+		if !s.collectionTargetConfigured(job.name, job.market.Key(), job.side, amount, job.payMethod) {
+			return
+		}
+
+		resultMu.Lock()
+		run.prices = append(run.prices, prices...)
+		// Round trips compare the unfiltered tiers only.
+		if job.payMethod == "" {
+			bestPrices = append(bestPrices, prices[0])
+		}
+		resultMu.Unlock()
+		if job.payMethod == "" {
+			s.setLatestPrice(prices[0])
+		}
+
+		s.persistPricesAndMerchants(ctx, prices)
+		benchmark := s.checkAlert(ctx, prices[0])
+		if cfg.AdaptivePolling.Near(prices[0].Price, benchmark) {
+			resultMu.Lock()
+			run.near[c2cTarget{exchange: job.name, market: job.market.Key(), amount: amount}] = true
+			resultMu.Unlock()
+		}
+	}
+
 	for _, j := range jobs {
 		job := j
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
 
-			select {
-			case sem <- struct{}{}:
-			case <-ctx.Done():
-				return
+			var prices []domain.PricePoint
+			var err error
+			tier := fmt.Sprintf("%s %s", job.market.Key(), job.side)
+			if job.orderBook != nil {
+				tier += " order book"
+				prices, err = s.fetchOrderBookWithRetry(ctx, sem, job.name, job.orderBook, job.market, job.side, job.payMethod)
+			} else {
+				tier += fmt.Sprintf(" %.4g", job.amounts[0])
+				prices, err = s.fetchTopPricesWithRetry(ctx, sem, job.name, job.exchange, job.market, job.side, job.amounts[0], job.payMethod)
 			}
-			defer func() { <-sem }()
-
-			prices, err := s.fetchTopPricesWithRetry(ctx, job.name, job.exchange, job.amount)
 			if err != nil {
+				if job.payMethod != "" {
+					tier += " " + job.payMethod
+				}
 				resultMu.Lock()
-				results[job.name].failed++
-				results[job.name].errors = append(results[job.name].errors, fmt.Sprintf("%.4g: %v", job.amount, err))
+				results[job.name].failed += len(job.amounts)
+				results[job.name].errors = append(results[job.name].errors, fmt.Sprintf("%s: %v", tier, err))
 				resultMu.Unlock()
 				return
 			}
 
-			resultMu.Lock()
-			if len(prices) == 0 {
-				results[job.name].empty++
-			} else {
-				results[job.name].succeeded++
-			}
-			resultMu.Unlock()
-
-			if len(prices) == 0 {
+			if job.orderBook == nil {
+				collectTier(job, job.amounts[0], prices)
 				return
 			}
-			if !s.collectionTargetConfigured(job.name, job.amount) {
-				return
+			// Every tier is cut from the same book, so they share one snapshot.
+			for _, amount := range job.amounts {
+				collectTier(job, amount, domain.TierPrices(prices, amount))
 			}
-
-			s.persistPricesAndMerchants(ctx, prices)
-			s.checkAlert(ctx, prices[0])
 		}()
 	}
 
 	wg.Wait()
 
 	if ctx.Err() != nil {
-		return
+		return run
 	}
+	s.recordRoundTripSpreads(ctx, cfg, bestPrices)
+
 	s.cfgMu.RLock()
 	defer s.cfgMu.RUnlock()
 	if !sameCollectionScope(cfg, s.cfg) {
 		slog.Info("discarding c2c service status from superseded config", "event", "c2c_status_discarded_config_changed")
-		return
+		return run
 	}
 
 	for exchangeName, result := range results {
@@ -612,10 +933,53 @@ func (s *MonitorService) checkC2C(ctx context.Context) {
 			continue
 		}
 	}
+	return run
+}
+
+// collectablePayMethods returns the pay-method filters of payMethods that
+// exchange can apply. Exchanges that do not report their filters get all of
+// them.
+func collectablePayMethods(exchange domain.IExchange, payMethods []string) []string {
+	filterer, ok := exchange.(domain.IPayMethodFilterer)
+	if !ok {
+		return payMethods
+	}
+	collectable := make([]string, 0, len(payMethods))
+	for _, payMethod := range payMethods {
+		if filterer.SupportsPayMethod(payMethod) {
+			collectable = append(collectable, payMethod)
+		}
+	}
+	return collectable
+}
+
+// warnUnsupportedPayMethods logs each configured pay-method filter that an
+// exchange cannot apply; those tiers are skipped rather than failed.
+func (s *MonitorService) warnUnsupportedPayMethods(cfg config.MonitorConfig) {
+	payMethods := cfg.CollectedPayMethods()
+	for _, name := range cfg.Exchanges {
+		exchange, ok := s.exchanges[name]
+		if !ok {
+			continue
+		}
+		collectable := collectablePayMethods(exchange, payMethods)
+		for _, payMethod := range payMethods {
+			if !slices.Contains(collectable, payMethod) {
+				slog.Warn("exchange cannot filter on pay method; skipping its tiers", "event", "pay_method_filter_unsupported", "exchange", name, "pay_method", payMethod)
+			}
+		}
+	}
 }
 
-func (s *MonitorService) collectionTargetConfigured(exchangeName string, amount float64) bool {
+func (s *MonitorService) collectionTargetConfigured(exchangeName, marketKey, side string, amount float64, payMethod string) bool {
 	cfg := s.getConfigSnapshot()
+	if !cfg.HasSide(side) || !cfg.HasPayMethod(payMethod) {
+		return false
+	}
+	market, ok := cfg.Market(marketKey)
+	if !ok {
+		return false
+	}
 	exchangeConfigured := false
 	for _, configuredExchange := range cfg.Exchanges {
 		if configuredExchange == exchangeName {
@@ -626,7 +990,7 @@ func (s *MonitorService) collectionTargetConfigured(exchangeName string, amount
 	if !exchangeConfigured {
 		return false
 	}
-	for _, configuredAmount := range cfg.TargetAmounts {
+	for _, configuredAmount := range cfg.AmountsFor(market) {
 		if configuredAmount == amount {
 			return true
 		}
@@ -635,9 +999,19 @@ func (s *MonitorService) collectionTargetConfigured(exchangeName string, amount
 }
 
 func sameCollectionScope(left, right config.MonitorConfig) bool {
-	if len(left.Exchanges) != len(right.Exchanges) || len(left.TargetAmounts) != len(right.TargetAmounts) {
+	if len(left.Exchanges) != len(right.Exchanges) || len(left.TargetAmounts) != len(right.TargetAmounts) || len(left.Sides) != len(right.Sides) || len(left.PayMethodFilters) != len(right.PayMethodFilters) {
 		return false
 	}
+	for index := range left.Sides {
+		if left.Sides[index] != right.Sides[index] {
+			return false
+		}
+	}
+	for index := range left.PayMethodFilters {
+		if left.PayMethodFilters[index] != right.PayMethodFilters[index] {
+			return false
+		}
+	}
 	for index := range left.Exchanges {
 		if left.Exchanges[index] != right.Exchanges[index] {
 			return false
@@ -648,28 +1022,115 @@ func sameCollectionScope(left, right config.MonitorConfig) bool {
 			return false
 		}
 	}
+	leftMarkets, rightMarkets := left.CollectedMarkets(), right.CollectedMarkets()
+	if len(leftMarkets) != len(rightMarkets) {
+		return false
+	}
+	for index := range leftMarkets {
+		if leftMarkets[index].Key() != rightMarkets[index].Key() {
+			return false
+		}
+		leftAmounts, rightAmounts := left.AmountsFor(leftMarkets[index]), right.AmountsFor(rightMarkets[index])
+		if len(leftAmounts) != len(rightAmounts) {
+			return false
+		}
+		for amountIndex := range leftAmounts {
+			if leftAmounts[amountIndex] != rightAmounts[amountIndex] {
+				return false
+			}
+		}
+	}
 	return true
 }
 
-func (s *MonitorService) fetchTopPricesWithRetry(ctx context.Context, exchangeName string, exchange domain.IExchange, amount float64) ([]domain.PricePoint, error) {
+func (s *MonitorService) fetchTopPricesWithRetry(ctx context.Context, sem chan struct{}, exchangeName string, exchange domain.IExchange, market config.MarketConfig, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	target := fmt.Sprintf("%s %s prices for amount %.4g", market.Key(), side, amount)
+	attrs := []any{"exchange", exchangeName, "market", market.Key(), "side", side, "amount", amount, "pay_method", payMethod}
+	return s.fetchWithRetry(ctx, sem, exchangeName, target, attrs, func(ctx context.Context) ([]domain.PricePoint, error) {
+		return exchange.GetTopPrices(ctx, market.Symbol, market.Fiat, side, amount, payMethod)
+	})
+}
+
+func (s *MonitorService) fetchOrderBookWithRetry(ctx context.Context, sem chan struct{}, exchangeName string, exchange domain.IOrderBookExchange, market config.MarketConfig, side string, payMethod string) ([]domain.PricePoint, error) {
+	target := fmt.Sprintf("%s %s order book", market.Key(), side)
+	attrs := []any{"exchange", exchangeName, "market", market.Key(), "side", side, "pay_method", payMethod}
+	return s.fetchWithRetry(ctx, sem, exchangeName, target, attrs, func(ctx context.Context) ([]domain.PricePoint, error) {
+		return exchange.GetOrderBook(ctx, market.Symbol, market.Fiat, side, payMethod)
+	})
+}
+
+// fetchWithRetry runs fetch through the exchange's circuit breaker and rate
+// limiter, retrying transient failures. It holds a sem slot only while a
+// request is in flight, so requests waiting for their exchange's rate limiter
+// or retry backoff do not block other exchanges. target and attrs describe the
+// request in errors and logs.
+func (s *MonitorService) fetchWithRetry(ctx context.Context, sem chan struct{}, exchangeName, target string, attrs []any, fetch func(context.Context) ([]domain.PricePoint, error)) ([]domain.PricePoint, error) {
 	const maxAttempts = 3
 	var lastErr error
+	logger := slog.With(attrs...)
+	breaker := s.circuitBreaker(exchangeName)
+	limiter := s.rateLimiter(exchangeName)
+	cfg := s.getConfigSnapshot()
+	breakerCfg := cfg.CircuitBreaker
+	rateLimit := cfg.RateLimitFor(exchangeName)
 
+	attempts := 0
 	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err := breaker.allow(time.Now()); err != nil {
+			if lastErr == nil {
+				// Skipped without a request; the open circuit was already logged.
+				return nil, err
+			}
+			break
+		}
+
+		waited, err := limiter.wait(ctx, rateLimit, time.Now())
+		if err != nil {
+			breaker.releaseProbe()
+			return nil, err
+		}
+		if waited > 0 {
+			level := slog.LevelDebug
+			if waited >= rateLimitWaitLogThreshold {
+				level = slog.LevelInfo
+			}
+			logger.Log(ctx, level, "waited for exchange rate limit", "event", "exchange_rate_limit_wait", "wait", waited.String())
+		}
+
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			breaker.releaseProbe()
+			return nil, ctx.Err()
+		}
+		attempts = attempt
 		attemptCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
-		prices, err := exchange.GetTopPrices(attemptCtx, "USDT", "CNY", "BUY", amount)
+		prices, err := fetch(attemptCtx)
 		cancel()
+		<-sem
 		if err == nil {
+			breaker.recordSuccess()
 			return prices, nil
 		}
+		if ctx.Err() != nil {
+			breaker.releaseProbe()
+			return nil, ctx.Err()
+		}
+		var unsupported *domain.UnsupportedQueryError
+		if errors.As(err, &unsupported) {
+			// A configuration error; the venue itself is fine.
+			breaker.releaseProbe()
+			finalErr := fmt.Errorf("failed to fetch %s: %w", target, err)
+			logger.Error("exchange cannot serve the configured target", "event", "exchange_target_unsupported", "error", finalErr)
+			return nil, finalErr
+		}
+		breaker.recordFailure(err, time.Now(), breakerCfg)
 		lastErr = err
 
-		if attempt < maxAttempts {
+		if attempt < maxAttempts && !breaker.isOpen(time.Now()) {
 			retryInterval := time.Duration(1<<(attempt-1)) * 2 * time.Second
-			slog.Warn("failed to fetch prices; retrying",
+			logger.Warn("failed to fetch prices; retrying",
 				"event", "exchange_fetch_retry",
-				"exchange", exchangeName,
-				"amount", amount,
 				"attempt", attempt,
 				"max_attempts", maxAttempts,
 				"retry_in", retryInterval.String(),
@@ -683,11 +1144,56 @@ func (s *MonitorService) fetchTopPricesWithRetry(ctx context.Context, exchangeNa
 		}
 	}
 
-	finalErr := fmt.Errorf("failed to fetch prices for amount %.4g after %d attempts: %w", amount, maxAttempts, lastErr)
-	slog.Error("exchange fetch failed after retries", "event", "exchange_fetch_failed", "exchange", exchangeName, "amount", amount, "error", finalErr)
+	finalErr := fmt.Errorf("failed to fetch %s after %d attempts: %w", target, attempts, lastErr)
+	logger.Error("exchange fetch failed after retries", "event", "exchange_fetch_failed", "error", finalErr)
 	return nil, finalErr
 }
 
+// payMethodTierPrices tags ads with the tier's pay-method filter. Ads that list
+// their methods without the filtered one are dropped in case a venue ignores
+// the filter; ads with hidden methods are kept.
+func payMethodTierPrices(prices []domain.PricePoint, payMethod string) []domain.PricePoint {
+	if payMethod == "" {
+		return prices
+	}
+	filtered := prices[:0]
+	for _, price := range prices {
+		if price.PayMethods != "" && !domain.PayMethodsInclude(price.PayMethods, payMethod) {
+			continue
+		}
+		price.PayMethodFilter = payMethod
+		filtered = append(filtered, price)
+	}
+	return filtered
+}
+
+// merchantFilteredPrices drops ads from advertisers below the configured track
+// record, so that rank 1 is the best ad the user would actually trade with.
+func merchantFilteredPrices(prices []domain.PricePoint, filter config.MerchantFilterConfig) []domain.PricePoint {
+	filtered := prices[:0]
+	for _, price := range prices {
+		if filter.Allows(price.Reputation) {
+			filtered = append(filtered, price)
+		}
+	}
+	return filtered
+}
+
+// topRankedPrices keeps the best depth ads and makes sure ranks are contiguous
+// from 1, whatever order the adapter returned them in.
+func topRankedPrices(prices []domain.PricePoint, depth int) []domain.PricePoint {
+	if depth <= 0 {
+		depth = 1
+	}
+	if len(prices) > depth {
+		prices = prices[:depth]
+	}
+	for i := range prices {
+		prices[i].Rank = i + 1
+	}
+	return prices
+}
+
 func (s *MonitorService) persistPricesAndMerchants(ctx context.Context, prices []domain.PricePoint) {
 	var ptrs []*domain.PricePoint
 	for i := range prices {
@@ -699,6 +1205,7 @@ func (s *MonitorService) persistPricesAndMerchants(ctx context.Context, prices [
 				Exchange:   p.Exchange,
 				MerchantID: p.MerchantID,
 				NickName:   p.Merchant,
+				Reputation: p.Reputation,
 				CreatedAt:  time.Now(),
 				UpdatedAt:  time.Now(),
 			}
@@ -721,29 +1228,46 @@ func (s *MonitorService) GetServiceStatuses() map[string]*domain.ServiceStatus {
 	// Deep copy to return
 	result := make(map[string]*domain.ServiceStatus)
 	for k, v := range s.serviceStatus {
-		result[k] = &domain.ServiceStatus{
+		status := &domain.ServiceStatus{
 			Name:      v.Name,
 			Status:    v.Status,
 			Message:   v.Message,
 			LastCheck: v.LastCheck,
 		}
+		if breaker, ok := s.breakers[k]; ok {
+			status.Circuit = breaker.status()
+		} else if _, ok := s.exchanges[k]; ok {
+			status.Circuit = &domain.CircuitStatus{State: domain.CircuitClosed}
+		}
+		if limiter, ok := s.limiters[k]; ok {
+			status.RateLimit = limiter.status()
+		}
+		result[k] = status
 	}
 	return result
 }
 
-func (s *MonitorService) checkAlert(ctx context.Context, p domain.PricePoint) {
-	if p.Price <= 0 {
-		return
+// checkAlert notifies when a BUY price beats the alert benchmark and returns
+// the tier's effective benchmark, or 0 when the tier has none.
+func (s *MonitorService) checkAlert(ctx context.Context, p domain.PricePoint) float64 {
+	// The forex benchmark only describes cheap buys; SELL prices feed round-trip alerts.
+	if p.Price <= 0 || p.Side != domain.SideBuy {
+		return 0
 	}
 
-	forexRate, err := s.usableForex(time.Now())
+	marketKey := domain.MarketKey(p.Symbol, p.Fiat)
+	market, ok := s.getConfigSnapshot().Market(marketKey)
+	if !ok || market.ForexPair() == "" {
+		return 0
+	}
+	forexRate, err := s.usableForex(market.ForexPair(), time.Now())
 	if err != nil {
-		return
+		return 0
 	}
-	benchmarkPrice := s.effectiveAlertBenchmark(ctx, forexRate, p.TargetAmount)
+	benchmarkPrice := s.effectiveAlertBenchmark(ctx, marketKey, forexRate, p.TargetAmount)
 
 	spread := (forexRate - p.Price) / forexRate * 100
-	alertKey := domain.AlertStateKey(p.Exchange, p.Side, p.TargetAmount)
+	alertKey := domain.AlertStateKey(p.Exchange, marketKey, p.Side, p.TargetAmount, p.PayMethodFilter)
 
 	s.mu.RLock()
 	triggeredPrice, isTriggered := s.triggeredLowPrices[alertKey]
@@ -759,54 +1283,185 @@ func (s *MonitorService) checkAlert(ctx context.Context, p domain.PricePoint) {
 	}
 
 	if p.Price >= effectiveBenchmark || !s.notifierEnabled() {
-		return
+		return benchmarkPrice
 	}
 
 	now := time.Now()
-
-	var subject string
+	eventType := domain.AlertEventPriceOpportunity
 	if alertType == "Lower" {
-		subject = fmt.Sprintf("📉 New Low! %s %s USDT Price: %.4f (Benchmark: %.4f)", p.Exchange, p.Merchant, p.Price, effectiveBenchmark)
-	} else {
-		subject = fmt.Sprintf("🚨 Opportunity! %s %s USDT Price: %.4f (Benchmark: %.4f)", p.Exchange, p.Merchant, p.Price, effectiveBenchmark)
-	}
-
-	body := fmt.Sprintf(`
-			<h3>C2C Arbitrage Opportunity</h3>
-			<p><b>Exchange:</b> %s</p>
-			<p><b>Merchant:</b> %s</p>
-			<p><b>Side:</b> User %s</p>
-			<p><b>Min Amount:</b> %.0f CNY</p>
-			<p><b>Max Amount:</b> %.0f CNY</p>
-			<p><b>Pay Methods:</b> %s</p>
-			<p><b>Current Price:</b> %.4f CNY</p>
-			<p><b>Alert Benchmark:</b> %.4f CNY</p>
-			<p><b>Forex Rate:</b> %.4f CNY</p>
-			<p><b>Spread:</b> <span style="color:green; font-weight:bold;">%.2f%%</span></p>
-			<p><i>Threshold Mode: %s</i></p>
-			<br/>
-			<p>Time: %s</p>
-		`, html.EscapeString(p.Exchange), html.EscapeString(p.Merchant), html.EscapeString(p.Side), p.MinAmount, p.MaxAmount, html.EscapeString(p.PayMethods), p.Price, effectiveBenchmark, forexRate, spread, alertType, now.Format(time.RFC3339))
-
-	slog.Warn("triggering price alert", "event", "price_alert_triggered", "alert_type", alertType, "exchange", p.Exchange, "merchant", p.Merchant, "price", p.Price, "benchmark", effectiveBenchmark, "forex_rate", forexRate, "spread", spread)
-
-	if err := s.notifier.Send(ctx, subject, body); err != nil {
-		slog.Error("failed to send alert email", "event", "price_alert_send_failed", "exchange", p.Exchange, "merchant", p.Merchant, "error", err)
+		eventType = domain.AlertEventNewLow
+	}
+
+	slog.Warn("triggering price alert", "event", "price_alert_triggered", "alert_type", alertType, "exchange", p.Exchange, "market", marketKey, "pay_method", p.PayMethodFilter, "merchant", p.Merchant, "price", p.Price, "benchmark", effectiveBenchmark, "forex_rate", forexRate, "spread", spread)
+
+	// The new low is recorded together with the queued alert; if either cannot
+	// be stored, the next round alerts again.
+	event := domain.AlertEvent{
+		Type:            eventType,
+		AlertType:       alertType,
+		Time:            now,
+		Exchange:        p.Exchange,
+		Symbol:          p.Symbol,
+		Fiat:            p.Fiat,
+		Side:            p.Side,
+		TargetAmount:    p.TargetAmount,
+		PayMethodFilter: p.PayMethodFilter,
+		PayMethods:      p.PayMethods,
+		Merchant:        p.Merchant,
+		MinAmount:       p.MinAmount,
+		MaxAmount:       p.MaxAmount,
+		Price:           p.Price,
+		Benchmark:       effectiveBenchmark,
+		ForexPair:       market.ForexPair(),
+		ForexRate:       forexRate,
+		SpreadPercent:   spread,
+	}
+	if err := s.enqueueAlert(ctx, event, alertKey, &domain.AlertState{
+		Exchange:        p.Exchange,
+		Symbol:          p.Symbol,
+		Fiat:            p.Fiat,
+		Side:            p.Side,
+		TargetAmount:    p.TargetAmount,
+		PayMethodFilter: p.PayMethodFilter,
+		TriggerPrice:    p.Price,
+		LastAlertAt:     now,
+	}); err != nil {
+		slog.Error("failed to queue alert", "event", "price_alert_enqueue_failed", "key", alertKey, "exchange", p.Exchange, "merchant", p.Merchant, "error", err)
+		return benchmarkPrice
+	}
+
+	s.mu.Lock()
+	s.triggeredLowPrices[alertKey] = p.Price
+	s.mu.Unlock()
+	return benchmarkPrice
+}
+
+// roundTripSpreads pairs the best SELL ad on each exchange with the best BUY ad
+// on every other exchange for the same amount tier.
+func roundTripSpreads(best []domain.PricePoint, now time.Time) []*domain.RoundTripSpread {
+	var spreads []*domain.RoundTripSpread
+	for _, sell := range best {
+		if sell.Side != domain.SideSell || sell.Price <= 0 {
+			continue
+		}
+		for _, buy := range best {
+			if buy.Side != domain.SideBuy || buy.Price <= 0 || buy.Exchange == sell.Exchange {
+				continue
+			}
+			if buy.TargetAmount != sell.TargetAmount || buy.Symbol != sell.Symbol || buy.Fiat != sell.Fiat {
+				continue
+			}
+			spread := sell.Price - buy.Price
+			spreads = append(spreads, &domain.RoundTripSpread{
+				CreatedAt:     now,
+				Symbol:        sell.Symbol,
+				Fiat:          sell.Fiat,
+				TargetAmount:  sell.TargetAmount,
+				BuyExchange:   buy.Exchange,
+				SellExchange:  sell.Exchange,
+				BuyPrice:      buy.Price,
+				SellPrice:     sell.Price,
+				Spread:        spread,
+				SpreadPercent: spread / buy.Price * 100,
+			})
+		}
+	}
+
+	sort.Slice(spreads, func(i, j int) bool {
+		if spreads[i].TargetAmount != spreads[j].TargetAmount {
+			return spreads[i].TargetAmount < spreads[j].TargetAmount
+		}
+		if spreads[i].SellExchange != spreads[j].SellExchange {
+			return spreads[i].SellExchange < spreads[j].SellExchange
+		}
+		return spreads[i].BuyExchange < spreads[j].BuyExchange
+	})
+	return spreads
+}
+
+// recordRoundTripSpreads saves and alerts on the spreads that involve a best ad
+// of this run. The other side of each pair is the latest best ad of any run
+// within RoundTripMaxPriceAge, so exchanges and tiers polled on different
+// schedules still pair up.
+func (s *MonitorService) recordRoundTripSpreads(ctx context.Context, cfg config.MonitorConfig, best []domain.PricePoint) {
+	if len(best) == 0 {
+		return
+	}
+	now := time.Now()
+	fresh := make(map[string]bool, len(best))
+	for _, p := range best {
+		fresh[latestPriceKey(p)] = true
+	}
+	var spreads []*domain.RoundTripSpread
+	for _, spread := range roundTripSpreads(s.latestPricesSince(now.Add(-cfg.RoundTripMaxPriceAge()), best), now) {
+		market := domain.MarketKey(spread.Symbol, spread.Fiat)
+		if fresh[domain.AlertStateKey(spread.SellExchange, market, domain.SideSell, spread.TargetAmount, "")] ||
+			fresh[domain.AlertStateKey(spread.BuyExchange, market, domain.SideBuy, spread.TargetAmount, "")] {
+			spreads = append(spreads, spread)
+		}
+	}
+	if len(spreads) == 0 {
 		return
 	}
 
-	if err := s.repo.UpsertAlertState(ctx, &domain.AlertState{
-		Exchange:     p.Exchange,
-		Side:         p.Side,
-		TargetAmount: p.TargetAmount,
-		TriggerPrice: p.Price,
-		LastAlertAt:  now,
-	}); err != nil {
-		slog.Error("failed to persist alert state", "event", "alert_state_persist_failed", "key", alertKey, "error", err)
+	if err := s.repo.SaveRoundTripSpreads(ctx, spreads); err != nil {
+		slog.Error("failed to save round-trip spreads", "event", "round_trip_spreads_save_failed", "count", len(spreads), "error", err)
+	}
+	for _, spread := range spreads {
+		s.checkRoundTripAlert(ctx, spread, cfg.RoundTripAlertSpread)
+	}
+}
+
+// checkRoundTripAlert fires when a spread reaches the threshold and again each
+// time it widens; dropping back below the threshold re-arms the pair.
+func (s *MonitorService) checkRoundTripAlert(ctx context.Context, spread *domain.RoundTripSpread, threshold float64) {
+	if threshold <= 0 {
+		return
+	}
+
+	alertKey := domain.RoundTripKey(domain.MarketKey(spread.Symbol, spread.Fiat), spread.SellExchange, spread.BuyExchange, spread.TargetAmount)
+	if spread.Spread < threshold {
+		s.mu.Lock()
+		delete(s.triggeredSpreads, alertKey)
+		s.mu.Unlock()
+		return
+	}
+
+	s.mu.RLock()
+	triggeredSpread, isTriggered := s.triggeredSpreads[alertKey]
+	s.mu.RUnlock()
+	if (isTriggered && spread.Spread <= triggeredSpread) || !s.notifierEnabled() {
+		return
+	}
+
+	alertType := "Initial"
+	if isTriggered {
+		alertType = "Wider"
+	}
+	slog.Warn("triggering round-trip alert", "event", "round_trip_alert_triggered", "alert_type", alertType, "market", domain.MarketKey(spread.Symbol, spread.Fiat), "sell_exchange", spread.SellExchange, "buy_exchange", spread.BuyExchange, "amount", spread.TargetAmount, "spread", spread.Spread, "threshold", threshold)
+
+	event := domain.AlertEvent{
+		Type:          domain.AlertEventRoundTrip,
+		AlertType:     alertType,
+		Time:          spread.CreatedAt,
+		Exchange:      spread.BuyExchange,
+		SellExchange:  spread.SellExchange,
+		Symbol:        spread.Symbol,
+		Fiat:          spread.Fiat,
+		TargetAmount:  spread.TargetAmount,
+		Price:         spread.BuyPrice,
+		SellPrice:     spread.SellPrice,
+		Benchmark:     threshold,
+		Spread:        spread.Spread,
+		SpreadPercent: spread.SpreadPercent,
+	}
+	if err := s.enqueueAlert(ctx, event, alertKey, nil); err != nil {
+		slog.Error("failed to queue round-trip alert", "event", "round_trip_alert_enqueue_failed", "sell_exchange", spread.SellExchange, "buy_exchange", spread.BuyExchange, "error", err)
+		return
 	}
 
 	s.mu.Lock()
-	s.triggeredLowPrices[alertKey] = p.Price
+	s.triggeredSpreads[alertKey] = spread.Spread
 	s.mu.Unlock()
 }
 
@@ -820,10 +1475,26 @@ func (s *MonitorService) notifierEnabled() bool {
 	return s.notifier != nil
 }
 
-// ResetAlertState resets the dynamic threshold for a specific market
-func (s *MonitorService) ResetAlertState(ctx context.Context, exchange, side string, amount float64) error {
-	key := domain.AlertStateKey(exchange, side, amount)
-	if err := s.repo.DeleteAlertState(ctx, exchange, side, amount); err != nil {
+// notifierChannel names the channel of a single notifier, e.g. "email".
+func (s *MonitorService) notifierChannel() string {
+	type channelNotifier interface {
+		Channel() string
+	}
+	if notifier, ok := s.notifier.(channelNotifier); ok {
+		return notifier.Channel()
+	}
+	return "notifier"
+}
+
+// ResetAlertState resets the dynamic threshold for a specific market tier; an
+// empty payMethod selects the unfiltered tier.
+func (s *MonitorService) ResetAlertState(ctx context.Context, exchange, market, side string, amount float64, payMethod string) error {
+	symbol, fiat, ok := domain.ParseMarketKey(market)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrInvalidMarket, market)
+	}
+	key := domain.AlertStateKey(exchange, market, side, amount, payMethod)
+	if err := s.repo.DeleteAlertState(ctx, exchange, symbol, fiat, side, amount, payMethod); err != nil {
 		return err
 	}
 
@@ -849,6 +1520,69 @@ func (s *MonitorService) GetAlertStates() map[string]float64 {
 
 // --- API Support Methods ---
 
+func (s *MonitorService) setLatestPrice(p domain.PricePoint) {
+	s.mu.Lock()
+	s.latestPrices[latestPriceKey(p)] = p
+	s.mu.Unlock()
+}
+
+func latestPriceKey(p domain.PricePoint) string {
+	return domain.AlertStateKey(p.Exchange, domain.MarketKey(p.Symbol, p.Fiat), p.Side, p.TargetAmount, "")
+}
+
+// latestPricesSince returns the latest best ads observed at or after cutoff,
+// with best taking precedence over the stored ads of the same tier.
+func (s *MonitorService) latestPricesSince(cutoff time.Time, best []domain.PricePoint) []domain.PricePoint {
+	prices := make(map[string]domain.PricePoint, len(best))
+	s.mu.RLock()
+	for key, p := range s.latestPrices {
+		if !p.CreatedAt.Before(cutoff) {
+			prices[key] = p
+		}
+	}
+	s.mu.RUnlock()
+	for _, p := range best {
+		prices[latestPriceKey(p)] = p
+	}
+
+	pool := make([]domain.PricePoint, 0, len(prices))
+	for _, p := range prices {
+		pool = append(pool, p)
+	}
+	return pool
+}
+
+// LatestPrices returns the best ad per exchange and side from the most recent
+// run of an amount tier, ignoring pay method tiers, best first within each
+// side. An empty marketKey selects the primary market.
+func (s *MonitorService) LatestPrices(marketKey string, amount float64) ([]domain.PricePoint, error) {
+	market, ok := s.getConfigSnapshot().Market(marketKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: market %s is not configured", ErrInvalidMarket, marketKey)
+	}
+
+	s.mu.RLock()
+	var prices []domain.PricePoint
+	for _, p := range s.latestPrices {
+		if p.Symbol == market.Symbol && p.Fiat == market.Fiat && p.TargetAmount == amount {
+			prices = append(prices, p)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(prices, func(i, j int) bool {
+		if prices[i].Side != prices[j].Side {
+			return prices[i].Side < prices[j].Side
+		}
+		if prices[i].Price != prices[j].Price {
+			// Buyers want the lowest price, sellers the highest.
+			return (prices[i].Price < prices[j].Price) == (prices[i].Side == domain.SideBuy)
+		}
+		return prices[i].Exchange < prices[j].Exchange
+	})
+	return prices, nil
+}
+
 func (s *MonitorService) GetPriceHistory(ctx context.Context, filter domain.PriceQueryFilter) ([]*domain.PricePoint, error) {
 	return s.repo.GetPriceHistory(ctx, filter)
 }
@@ -857,6 +1591,10 @@ func (s *MonitorService) GetPriceHistoryByGranularity(ctx context.Context, filte
 	return s.repo.GetPriceHistoryByGranularity(ctx, filter, granularity)
 }
 
+func (s *MonitorService) GetRoundTripSpreadHistory(ctx context.Context, filter domain.SpreadQueryFilter) ([]*domain.RoundTripSpread, error) {
+	return s.repo.GetRoundTripSpreadHistory(ctx, filter)
+}
+
 func (s *MonitorService) GetForexHistory(ctx context.Context, pair string, start, end time.Time) ([]*domain.ForexRate, error) {
 	return s.repo.GetForexHistory(ctx, pair, start, end)
 }
@@ -869,29 +1607,22 @@ func (s *MonitorService) GetConfig() config.MonitorConfig {
 	return s.getConfigSnapshot()
 }
 
-func (s *MonitorService) GetAlertBenchmark(ctx context.Context, targetAmount *float64) (domain.AlertBenchmarkStatus, error) {
-	if err := s.validateBenchmarkTargetAmount(targetAmount); err != nil {
-		return domain.AlertBenchmarkStatus{}, err
-	}
-
-	forexRate, err := s.usableForex(time.Now())
+func (s *MonitorService) GetAlertBenchmark(ctx context.Context, marketKey string, targetAmount *float64) (domain.AlertBenchmarkStatus, error) {
+	market, forexRate, err := s.benchmarkMarket(marketKey, targetAmount)
 	if err != nil {
 		return domain.AlertBenchmarkStatus{}, err
 	}
 
-	globalBenchmark := s.reconcileAlertBenchmark(ctx, forexRate)
-	return s.buildAlertBenchmarkStatus(globalBenchmark, forexRate, targetAmount), nil
+	globalBenchmark := s.reconcileAlertBenchmark(ctx, market.Key(), forexRate)
+	return s.buildAlertBenchmarkStatus(market.Key(), globalBenchmark, forexRate, targetAmount), nil
 }
 
-func (s *MonitorService) UpdateAlertBenchmark(ctx context.Context, requestedPrice float64, targetAmount *float64) (domain.AlertBenchmarkStatus, error) {
-	if err := s.validateBenchmarkTargetAmount(targetAmount); err != nil {
-		return domain.AlertBenchmarkStatus{}, err
-	}
-
-	forexRate, err := s.usableForex(time.Now())
+func (s *MonitorService) UpdateAlertBenchmark(ctx context.Context, marketKey string, requestedPrice float64, targetAmount *float64) (domain.AlertBenchmarkStatus, error) {
+	market, forexRate, err := s.benchmarkMarket(marketKey, targetAmount)
 	if err != nil {
 		return domain.AlertBenchmarkStatus{}, err
 	}
+	key := market.Key()
 
 	if math.IsNaN(requestedPrice) || math.IsInf(requestedPrice, 0) || requestedPrice <= 0 {
 		return domain.AlertBenchmarkStatus{}, fmt.Errorf("%w: benchmark_price must be greater than 0", ErrInvalidAlertBenchmark)
@@ -900,13 +1631,13 @@ func (s *MonitorService) UpdateAlertBenchmark(ctx context.Context, requestedPric
 		return domain.AlertBenchmarkStatus{}, fmt.Errorf("%w: benchmark_price must be lower than the current Forex rate %.4f", ErrInvalidAlertBenchmark, forexRate)
 	}
 
-	globalBenchmark := s.reconcileAlertBenchmark(ctx, forexRate)
+	globalBenchmark := s.reconcileAlertBenchmark(ctx, key, forexRate)
 	s.benchmarkMu.Lock()
 	defer s.benchmarkMu.Unlock()
 
 	currentPrice := globalBenchmark
 	if targetAmount != nil {
-		if override, exists := s.benchmarkOverrides[*targetAmount]; exists && override < currentPrice {
+		if override, exists := s.benchmarkOverrides[key][*targetAmount]; exists && override < currentPrice {
 			currentPrice = override
 		}
 	}
@@ -916,17 +1647,21 @@ func (s *MonitorService) UpdateAlertBenchmark(ctx context.Context, requestedPric
 
 	if targetAmount != nil {
 		if err := s.repo.UpsertAlertBenchmarkOverride(ctx, &domain.AlertBenchmarkOverride{
-			Pair:         alertBenchmarkPair,
+			Pair:         key,
 			TargetAmount: *targetAmount,
 			Price:        requestedPrice,
 		}); err != nil {
 			return domain.AlertBenchmarkStatus{}, fmt.Errorf("persist amount alert benchmark: %w", err)
 		}
-		s.benchmarkOverrides[*targetAmount] = requestedPrice
+		if s.benchmarkOverrides[key] == nil {
+			s.benchmarkOverrides[key] = make(map[float64]float64)
+		}
+		s.benchmarkOverrides[key][*targetAmount] = requestedPrice
 		overridePrice := requestedPrice
 		targetCopy := *targetAmount
-		slog.Info("updated amount alert benchmark", "event", "alert_benchmark_override_updated", "target_amount", targetCopy, "price", requestedPrice, "forex_rate", forexRate)
+		slog.Info("updated amount alert benchmark", "event", "alert_benchmark_override_updated", "market", key, "target_amount", targetCopy, "price", requestedPrice, "forex_rate", forexRate)
 		return domain.AlertBenchmarkStatus{
+			Market:               key,
 			BenchmarkPrice:       requestedPrice,
 			GlobalBenchmarkPrice: globalBenchmark,
 			ForexRate:            forexRate,
@@ -936,16 +1671,17 @@ func (s *MonitorService) UpdateAlertBenchmark(ctx context.Context, requestedPric
 	}
 
 	if err := s.repo.UpsertAlertBenchmark(ctx, &domain.AlertBenchmark{
-		Pair:  alertBenchmarkPair,
+		Pair:  key,
 		Price: requestedPrice,
 	}); err != nil {
 		return domain.AlertBenchmarkStatus{}, fmt.Errorf("persist alert benchmark: %w", err)
 	}
-	s.alertBenchmark = requestedPrice
-	s.alertBenchmarkDirty = false
+	s.alertBenchmarks[key] = requestedPrice
+	s.dirtyBenchmarks[key] = false
 
-	slog.Info("updated alert benchmark", "event", "alert_benchmark_updated", "price", requestedPrice, "forex_rate", forexRate)
+	slog.Info("updated alert benchmark", "event", "alert_benchmark_updated", "market", key, "price", requestedPrice, "forex_rate", forexRate)
 	return domain.AlertBenchmarkStatus{
+		Market:               key,
 		BenchmarkPrice:       requestedPrice,
 		GlobalBenchmarkPrice: requestedPrice,
 		ForexRate:            forexRate,
@@ -963,54 +1699,80 @@ func (s *MonitorService) UpdateConfig(newCfg config.MonitorConfig) error {
 	s.cfgMu.Unlock()
 
 	s.syncConfiguredServiceStatuses(normalizedCfg.Exchanges)
+	s.warnUnsupportedPayMethods(normalizedCfg)
 	s.notifyConfigChanged()
 	return nil
 }
 
 func (s *MonitorService) ReadinessError() error {
-	_, err := s.usableForex(time.Now())
-	return err
+	return s.unusableForex(time.Now())
 }
 
-func (s *MonitorService) reconcileAlertBenchmark(ctx context.Context, forexRate float64) float64 {
+// benchmarkMarket resolves a market key (empty means the first configured
+// market) and its usable Forex rate for benchmark reads and writes.
+func (s *MonitorService) benchmarkMarket(marketKey string, targetAmount *float64) (config.MarketConfig, float64, error) {
+	cfg := s.getConfigSnapshot()
+	market, ok := cfg.Market(marketKey)
+	if !ok {
+		return config.MarketConfig{}, 0, fmt.Errorf("%w: market %s is not configured", ErrInvalidMarket, marketKey)
+	}
+	if market.ForexPair() == "" {
+		return config.MarketConfig{}, 0, fmt.Errorf("%w: market %s has no Forex reference", ErrInvalidMarket, market.Key())
+	}
+	if err := validateBenchmarkTargetAmount(cfg, market, targetAmount); err != nil {
+		return config.MarketConfig{}, 0, err
+	}
+
+	forexRate, err := s.usableForex(market.ForexPair(), time.Now())
+	if err != nil {
+		return config.MarketConfig{}, 0, err
+	}
+	return market, forexRate, nil
+}
+
+func (s *MonitorService) reconcileAlertBenchmark(ctx context.Context, market string, forexRate float64) float64 {
+	s.ensureMarketBenchmarksLoaded(ctx, market)
+
 	s.benchmarkMu.Lock()
 	defer s.benchmarkMu.Unlock()
 
+	current, exists := s.alertBenchmarks[market]
 	nextPrice := forexRate
-	if s.alertBenchmark > 0 && s.alertBenchmark < nextPrice {
-		nextPrice = s.alertBenchmark
+	if exists && current > 0 && current < nextPrice {
+		nextPrice = current
 	}
-	if s.alertBenchmark == nextPrice && !s.alertBenchmarkDirty {
+	if exists && current == nextPrice && !s.dirtyBenchmarks[market] {
 		return nextPrice
 	}
 
-	s.alertBenchmark = nextPrice
+	s.alertBenchmarks[market] = nextPrice
 	if err := s.repo.UpsertAlertBenchmark(ctx, &domain.AlertBenchmark{
-		Pair:  alertBenchmarkPair,
+		Pair:  market,
 		Price: nextPrice,
 	}); err != nil {
-		s.alertBenchmarkDirty = true
-		slog.Error("failed to persist reconciled alert benchmark", "event", "alert_benchmark_persist_failed", "price", nextPrice, "forex_rate", forexRate, "error", err)
+		s.dirtyBenchmarks[market] = true
+		slog.Error("failed to persist reconciled alert benchmark", "event", "alert_benchmark_persist_failed", "market", market, "price", nextPrice, "forex_rate", forexRate, "error", err)
 		return nextPrice
 	}
-	s.alertBenchmarkDirty = false
+	s.dirtyBenchmarks[market] = false
 
 	return nextPrice
 }
 
-func (s *MonitorService) effectiveAlertBenchmark(ctx context.Context, forexRate, targetAmount float64) float64 {
-	globalBenchmark := s.reconcileAlertBenchmark(ctx, forexRate)
+func (s *MonitorService) effectiveAlertBenchmark(ctx context.Context, market string, forexRate, targetAmount float64) float64 {
+	globalBenchmark := s.reconcileAlertBenchmark(ctx, market, forexRate)
 
 	s.benchmarkMu.RLock()
 	defer s.benchmarkMu.RUnlock()
-	if override, exists := s.benchmarkOverrides[targetAmount]; exists && override < globalBenchmark {
+	if override, exists := s.benchmarkOverrides[market][targetAmount]; exists && override < globalBenchmark {
 		return override
 	}
 	return globalBenchmark
 }
 
-func (s *MonitorService) buildAlertBenchmarkStatus(globalBenchmark, forexRate float64, targetAmount *float64) domain.AlertBenchmarkStatus {
+func (s *MonitorService) buildAlertBenchmarkStatus(market string, globalBenchmark, forexRate float64, targetAmount *float64) domain.AlertBenchmarkStatus {
 	status := domain.AlertBenchmarkStatus{
+		Market:               market,
 		BenchmarkPrice:       globalBenchmark,
 		GlobalBenchmarkPrice: globalBenchmark,
 		ForexRate:            forexRate,
@@ -1024,7 +1786,7 @@ func (s *MonitorService) buildAlertBenchmarkStatus(globalBenchmark, forexRate fl
 
 	s.benchmarkMu.RLock()
 	defer s.benchmarkMu.RUnlock()
-	if override, exists := s.benchmarkOverrides[targetCopy]; exists {
+	if override, exists := s.benchmarkOverrides[market][targetCopy]; exists {
 		overrideCopy := override
 		status.OverridePrice = &overrideCopy
 		if override < status.BenchmarkPrice {
@@ -1034,7 +1796,7 @@ func (s *MonitorService) buildAlertBenchmarkStatus(globalBenchmark, forexRate fl
 	return status
 }
 
-func (s *MonitorService) validateBenchmarkTargetAmount(targetAmount *float64) error {
+func validateBenchmarkTargetAmount(cfg config.MonitorConfig, market config.MarketConfig, targetAmount *float64) error {
 	if targetAmount == nil {
 		return nil
 	}
@@ -1042,11 +1804,10 @@ func (s *MonitorService) validateBenchmarkTargetAmount(targetAmount *float64) er
 		return fmt.Errorf("%w: target_amount must be >= 0", ErrInvalidAlertBenchmark)
 	}
 
-	cfg := s.getConfigSnapshot()
-	for _, configuredAmount := range cfg.TargetAmounts {
+	for _, configuredAmount := range cfg.AmountsFor(market) {
 		if configuredAmount == *targetAmount {
 			return nil
 		}
 	}
-	return fmt.Errorf("%w: target_amount %.4f is not configured", ErrInvalidAlertBenchmark, *targetAmount)
+	return fmt.Errorf("%w: target_amount %.4f is not configured for %s", ErrInvalidAlertBenchmark, *targetAmount, market.Key())
 }
@@ -4,8 +4,15 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -36,6 +43,22 @@ func TestLogServiceDown(t *testing.T) {
 	}
 }
 
+func TestSetServiceDownLogWritesToFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logs", "service_down.log")
+	svc := NewMonitorService(testMonitorConfig(), &stubRepository{}, nil, sourceAwareForex{rate: 7.2, source: "test"}, stubNotifier{})
+	svc.SetServiceDownLog(path)
+
+	svc.logServiceDown("Gate", errors.New("timeout"))
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read service down log: %v", err)
+	}
+	if !strings.Contains(string(data), `"service":"Gate"`) {
+		t.Fatalf("expected the event in the log file, got %q", data)
+	}
+}
+
 func TestUpdateForexFallsBackToCachedDatabaseRate(t *testing.T) {
 	repo := &stubRepository{
 		latestForex: &domain.ForexRate{
@@ -55,8 +78,9 @@ func TestUpdateForexFallsBackToCachedDatabaseRate(t *testing.T) {
 	)
 
 	svc.updateForex(context.Background())
+	svc.waitForDeliveries()
 
-	if got, _ := svc.getLastForex(); got != 7.2145 {
+	if got, _ := svc.getLastForex(testForexPair); got != 7.2145 {
 		t.Fatalf("expected cached forex rate 7.2145, got %f", got)
 	}
 
@@ -88,6 +112,7 @@ func TestUpdateForexPersistsSuccessfulSourceName(t *testing.T) {
 	)
 
 	svc.updateForex(context.Background())
+	svc.waitForDeliveries()
 
 	if repo.savedForex == nil {
 		t.Fatal("expected successful forex update to be saved")
@@ -119,8 +144,9 @@ func TestUpdateForexRejectsStaleCachedRate(t *testing.T) {
 	)
 
 	svc.updateForex(context.Background())
+	svc.waitForDeliveries()
 
-	if got, observedAt := svc.getLastForex(); got != 0 || !observedAt.IsZero() {
+	if got, observedAt := svc.getLastForex(testForexPair); got != 0 || !observedAt.IsZero() {
 		t.Fatalf("expected stale cached forex to be rejected, got rate=%f observed_at=%v", got, observedAt)
 	}
 	if err := svc.ReadinessError(); err == nil || !strings.Contains(err.Error(), "unavailable") {
@@ -138,6 +164,7 @@ func TestUpdateForexRejectsInvalidSuccessfulRate(t *testing.T) {
 	)
 
 	svc.updateForex(context.Background())
+	svc.waitForDeliveries()
 
 	if err := svc.ReadinessError(); err == nil {
 		t.Fatal("expected invalid Forex rate to leave service unready")
@@ -148,24 +175,26 @@ func TestUpdateForexRejectsInvalidSuccessfulRate(t *testing.T) {
 	}
 }
 
-func TestCheckAlertDoesNotAdvanceStateWhenNotificationFails(t *testing.T) {
-	repo := &stubRepository{}
+func TestCheckAlertDoesNotAdvanceStateWhenAlertCannotBeQueued(t *testing.T) {
+	repo := &stubRepository{enqueueErr: errors.New("database unavailable")}
+	notifier := &recordingNotifier{}
 	svc := NewMonitorService(
 		testMonitorConfig(),
 		repo,
 		nil,
 		sourceAwareForex{rate: 7.2, source: "test"},
-		failingNotifier{err: errors.New("SMTP timeout")},
+		notifier,
 	)
-	svc.setLastForex(7.2, time.Now())
+	svc.setLastForex(testForexPair, 7.2, time.Now())
 
 	svc.checkAlert(context.Background(), testPricePoint(7.0, 0))
+	svc.waitForDeliveries()
 
 	if len(svc.GetAlertStates()) != 0 {
-		t.Fatalf("expected failed notification not to advance alert state, got %v", svc.GetAlertStates())
+		t.Fatalf("expected an unqueued alert not to advance alert state, got %v", svc.GetAlertStates())
 	}
-	if repo.savedAlert != nil {
-		t.Fatal("expected failed notification not to persist alert state")
+	if repo.savedAlert != nil || notifier.calls != 0 {
+		t.Fatalf("expected nothing to be persisted or sent, got state %#v and %d sends", repo.savedAlert, notifier.calls)
 	}
 }
 
@@ -179,11 +208,12 @@ func TestCheckAlertPersistsStateAfterNotificationSucceeds(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		notifier,
 	)
-	svc.setLastForex(7.2, time.Now())
+	svc.setLastForex(testForexPair, 7.2, time.Now())
 
 	svc.checkAlert(context.Background(), testPricePoint(7.0, 0))
+	svc.waitForDeliveries()
 
-	key := domain.AlertStateKey(domain.ExchangeGate, "BUY", 0)
+	key := domain.AlertStateKey(domain.ExchangeGate, testMarket, "BUY", 0, "")
 	if got := svc.GetAlertStates()[key]; got != 7.0 {
 		t.Fatalf("expected alert state 7.0, got %v", svc.GetAlertStates())
 	}
@@ -205,8 +235,8 @@ func TestAlertBenchmarkDefaultsToForexAndOnlyMovesLower(t *testing.T) {
 		stubNotifier{},
 	)
 
-	svc.setLastForex(7.2, time.Now())
-	status, err := svc.GetAlertBenchmark(context.Background(), nil)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+	status, err := svc.GetAlertBenchmark(context.Background(), testMarket, nil)
 	if err != nil {
 		t.Fatalf("GetAlertBenchmark returned error: %v", err)
 	}
@@ -214,8 +244,8 @@ func TestAlertBenchmarkDefaultsToForexAndOnlyMovesLower(t *testing.T) {
 		t.Fatalf("expected initial benchmark and Forex rate 7.2, got %#v", status)
 	}
 
-	svc.setLastForex(7.3, time.Now())
-	status, err = svc.GetAlertBenchmark(context.Background(), nil)
+	svc.setLastForex(testForexPair, 7.3, time.Now())
+	status, err = svc.GetAlertBenchmark(context.Background(), testMarket, nil)
 	if err != nil {
 		t.Fatalf("GetAlertBenchmark after Forex increase returned error: %v", err)
 	}
@@ -223,8 +253,8 @@ func TestAlertBenchmarkDefaultsToForexAndOnlyMovesLower(t *testing.T) {
 		t.Fatalf("expected benchmark to stay 7.2 when Forex rises to 7.3, got %#v", status)
 	}
 
-	svc.setLastForex(7.05, time.Now())
-	status, err = svc.GetAlertBenchmark(context.Background(), nil)
+	svc.setLastForex(testForexPair, 7.05, time.Now())
+	status, err = svc.GetAlertBenchmark(context.Background(), testMarket, nil)
 	if err != nil {
 		t.Fatalf("GetAlertBenchmark after Forex decrease returned error: %v", err)
 	}
@@ -245,12 +275,12 @@ func TestUpdateAlertBenchmarkRejectsAnyIncrease(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		stubNotifier{},
 	)
-	svc.setLastForex(7.2, time.Now())
+	svc.setLastForex(testForexPair, 7.2, time.Now())
 
-	if _, err := svc.GetAlertBenchmark(context.Background(), nil); err != nil {
+	if _, err := svc.GetAlertBenchmark(context.Background(), testMarket, nil); err != nil {
 		t.Fatalf("initialize benchmark: %v", err)
 	}
-	status, err := svc.UpdateAlertBenchmark(context.Background(), 7.1, nil)
+	status, err := svc.UpdateAlertBenchmark(context.Background(), testMarket, 7.1, nil)
 	if err != nil {
 		t.Fatalf("expected lower benchmark to be accepted: %v", err)
 	}
@@ -258,10 +288,10 @@ func TestUpdateAlertBenchmarkRejectsAnyIncrease(t *testing.T) {
 		t.Fatalf("expected benchmark 7.1, got %#v", status)
 	}
 
-	if _, err := svc.UpdateAlertBenchmark(context.Background(), 7.15, nil); err == nil {
+	if _, err := svc.UpdateAlertBenchmark(context.Background(), testMarket, 7.15, nil); err == nil {
 		t.Fatal("expected benchmark increase to be rejected")
 	}
-	status, err = svc.GetAlertBenchmark(context.Background(), nil)
+	status, err = svc.GetAlertBenchmark(context.Background(), testMarket, nil)
 	if err != nil {
 		t.Fatalf("read benchmark after rejected increase: %v", err)
 	}
@@ -279,9 +309,9 @@ func TestAlertBenchmarkRetriesPersistenceAfterFailure(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		stubNotifier{},
 	)
-	svc.setLastForex(7.2, time.Now())
+	svc.setLastForex(testForexPair, 7.2, time.Now())
 
-	status, err := svc.GetAlertBenchmark(context.Background(), nil)
+	status, err := svc.GetAlertBenchmark(context.Background(), testMarket, nil)
 	if err != nil {
 		t.Fatalf("GetAlertBenchmark returned error: %v", err)
 	}
@@ -293,7 +323,7 @@ func TestAlertBenchmarkRetriesPersistenceAfterFailure(t *testing.T) {
 	}
 
 	repo.alertBenchmarkErr = nil
-	status, err = svc.GetAlertBenchmark(context.Background(), nil)
+	status, err = svc.GetAlertBenchmark(context.Background(), testMarket, nil)
 	if err != nil {
 		t.Fatalf("GetAlertBenchmark retry returned error: %v", err)
 	}
@@ -314,10 +344,10 @@ func TestAmountBenchmarkAppliesOnlyToConfiguredTier(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		notifier,
 	)
-	svc.setLastForex(7.2, time.Now())
+	svc.setLastForex(testForexPair, 7.2, time.Now())
 
 	amount1000 := 1000.0
-	status, err := svc.UpdateAlertBenchmark(context.Background(), 6.70, &amount1000)
+	status, err := svc.UpdateAlertBenchmark(context.Background(), testMarket, 6.70, &amount1000)
 	if err != nil {
 		t.Fatalf("UpdateAlertBenchmark for 1000 tier returned error: %v", err)
 	}
@@ -327,7 +357,7 @@ func TestAmountBenchmarkAppliesOnlyToConfiguredTier(t *testing.T) {
 	}
 
 	amount500 := 500.0
-	status, err = svc.GetAlertBenchmark(context.Background(), &amount500)
+	status, err = svc.GetAlertBenchmark(context.Background(), testMarket, &amount500)
 	if err != nil {
 		t.Fatalf("GetAlertBenchmark for 500 tier returned error: %v", err)
 	}
@@ -336,20 +366,23 @@ func TestAmountBenchmarkAppliesOnlyToConfiguredTier(t *testing.T) {
 	}
 
 	svc.checkAlert(context.Background(), testPricePoint(6.71, amount1000))
+	svc.waitForDeliveries()
 	if notifier.calls != 0 {
 		t.Fatalf("expected 1000 tier price above 6.70 not to alert, got %d calls", notifier.calls)
 	}
 	svc.checkAlert(context.Background(), testPricePoint(6.69, amount1000))
+	svc.waitForDeliveries()
 	if notifier.calls != 1 {
 		t.Fatalf("expected 1000 tier price below 6.70 to alert, got %d calls", notifier.calls)
 	}
 	svc.checkAlert(context.Background(), testPricePoint(6.71, amount500))
+	svc.waitForDeliveries()
 	if notifier.calls != 2 {
 		t.Fatalf("expected 500 tier to retain its independent global benchmark, got %d calls", notifier.calls)
 	}
 
 	unknownAmount := 999.0
-	if _, err := svc.UpdateAlertBenchmark(context.Background(), 6.60, &unknownAmount); err == nil {
+	if _, err := svc.UpdateAlertBenchmark(context.Background(), testMarket, 6.60, &unknownAmount); err == nil {
 		t.Fatal("expected unconfigured amount tier to be rejected")
 	}
 }
@@ -364,32 +397,36 @@ func TestCheckAlertUsesBenchmarkThenTracksSuccessfulNewLows(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		notifier,
 	)
-	svc.setLastForex(7.2, time.Now())
-	if _, err := svc.UpdateAlertBenchmark(context.Background(), 7.1, nil); err != nil {
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+	if _, err := svc.UpdateAlertBenchmark(context.Background(), testMarket, 7.1, nil); err != nil {
 		t.Fatalf("UpdateAlertBenchmark returned error: %v", err)
 	}
 
 	svc.checkAlert(context.Background(), testPricePoint(7.15, 30))
+	svc.waitForDeliveries()
 	if notifier.calls != 0 {
 		t.Fatalf("expected price above benchmark not to alert, got %d calls", notifier.calls)
 	}
 
 	svc.checkAlert(context.Background(), testPricePoint(7.09, 30))
+	svc.waitForDeliveries()
 	if notifier.calls != 1 {
 		t.Fatalf("expected first price below benchmark to alert once, got %d calls", notifier.calls)
 	}
 
 	svc.checkAlert(context.Background(), testPricePoint(7.095, 30))
+	svc.waitForDeliveries()
 	if notifier.calls != 1 {
 		t.Fatalf("expected price above the last successful alert not to alert again, got %d calls", notifier.calls)
 	}
 
 	svc.checkAlert(context.Background(), testPricePoint(7.08, 30))
+	svc.waitForDeliveries()
 	if notifier.calls != 2 {
 		t.Fatalf("expected a new low to alert again, got %d calls", notifier.calls)
 	}
 
-	key := domain.AlertStateKey(domain.ExchangeGate, "BUY", 30)
+	key := domain.AlertStateKey(domain.ExchangeGate, testMarket, "BUY", 30, "")
 	if got := svc.GetAlertStates()[key]; got != 7.08 {
 		t.Fatalf("expected market threshold to advance to 7.08, got %f", got)
 	}
@@ -405,9 +442,10 @@ func TestCheckAlertRejectsStaleForex(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		notifier,
 	)
-	svc.setLastForex(7.2, time.Now().Add(-7*time.Hour))
+	svc.setLastForex(testForexPair, 7.2, time.Now().Add(-7*time.Hour))
 
 	svc.checkAlert(context.Background(), testPricePoint(7.0, 30))
+	svc.waitForDeliveries()
 
 	if notifier.calls != 0 {
 		t.Fatalf("expected stale forex not to send alert, got %d calls", notifier.calls)
@@ -424,9 +462,10 @@ func TestCheckAlertSkipsDisabledNotifier(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		notifier,
 	)
-	svc.setLastForex(7.2, time.Now())
+	svc.setLastForex(testForexPair, 7.2, time.Now())
 
 	svc.checkAlert(context.Background(), testPricePoint(7.0, 30))
+	svc.waitForDeliveries()
 
 	if notifier.calls != 0 {
 		t.Fatalf("expected disabled notifier not to be called, got %d calls", notifier.calls)
@@ -447,9 +486,10 @@ func TestCheckC2CStillCollectsWhenForexIsStale(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		&recordingNotifier{},
 	)
-	svc.setLastForex(7.2, time.Now().Add(-7*time.Hour))
+	svc.setLastForex(testForexPair, 7.2, time.Now().Add(-7*time.Hour))
 
 	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
 
 	if got := atomic.LoadInt64(&repo.savedPriceBatches); got != 2 {
 		t.Fatalf("expected both configured amount tiers to be collected, got %d batches", got)
@@ -459,6 +499,360 @@ func TestCheckC2CStillCollectsWhenForexIsStale(t *testing.T) {
 	}
 }
 
+func TestCheckC2CKeepsConfiguredDepthPerExchange(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{0}
+	cfg.Depth = 2
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		map[string]domain.IExchange{
+			domain.ExchangeGate: deepTestExchange{},
+		},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	saved := repo.savedPricePoints()
+	if len(saved) != 2 {
+		t.Fatalf("expected depth 2 to persist two ranks, got %d", len(saved))
+	}
+	for index, point := range saved {
+		if point.Rank != index+1 {
+			t.Fatalf("expected contiguous ranks, got %#v", saved)
+		}
+	}
+
+	cfg.ExchangeDepths = map[string]int{domain.ExchangeGate: 3}
+	if err := svc.UpdateConfig(cfg); err != nil {
+		t.Fatalf("UpdateConfig returned error: %v", err)
+	}
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+	if saved := repo.savedPricePoints(); len(saved) != 5 {
+		t.Fatalf("expected exchange depth override to persist three more ranks, got %d total", len(saved))
+	}
+}
+
+func TestCheckC2CRecordsRoundTripSpreadsAndAlertsOnce(t *testing.T) {
+	repo := &stubRepository{}
+	notifier := &recordingNotifier{}
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{0}
+	cfg.Exchanges = []string{domain.ExchangeGate, domain.ExchangeOKX}
+	cfg.Sides = []string{domain.SideBuy, domain.SideSell}
+	cfg.RoundTripAlertSpread = 0.05
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		map[string]domain.IExchange{
+			domain.ExchangeGate: sidedTestExchange{name: domain.ExchangeGate, buy: 7.00, sell: 7.05},
+			domain.ExchangeOKX:  sidedTestExchange{name: domain.ExchangeOKX, buy: 7.02, sell: 7.10},
+		},
+		sourceAwareForex{rate: 6.9, source: "test"},
+		notifier,
+	)
+	svc.setLastForex(testForexPair, 6.9, time.Now())
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	repo.pricesMu.Lock()
+	spreads := append([]*domain.RoundTripSpread(nil), repo.savedSpreads...)
+	repo.pricesMu.Unlock()
+	if len(spreads) != 2 {
+		t.Fatalf("expected one spread per ordered exchange pair, got %d", len(spreads))
+	}
+	if spreads[0].SellExchange != domain.ExchangeGate || spreads[0].BuyExchange != domain.ExchangeOKX ||
+		math.Abs(spreads[0].Spread-0.03) > 1e-9 {
+		t.Fatalf("unexpected Gate->OKX spread: %#v", spreads[0])
+	}
+	if spreads[1].SellExchange != domain.ExchangeOKX || math.Abs(spreads[1].Spread-0.10) > 1e-9 {
+		t.Fatalf("unexpected OKX->Gate spread: %#v", spreads[1])
+	}
+	if notifier.calls != 1 {
+		t.Fatalf("expected one round-trip alert above threshold, got %d", notifier.calls)
+	}
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+	if notifier.calls != 1 {
+		t.Fatalf("expected unchanged spread not to alert again, got %d", notifier.calls)
+	}
+}
+
+func TestRoundTripSpreadsPairFreshPricesFromEarlierRuns(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{0}
+	cfg.Exchanges = []string{domain.ExchangeGate, domain.ExchangeOKX}
+	cfg.Sides = []string{domain.SideBuy, domain.SideSell}
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		map[string]domain.IExchange{
+			domain.ExchangeGate: sidedTestExchange{name: domain.ExchangeGate, buy: 7.00, sell: 7.05},
+			domain.ExchangeOKX:  sidedTestExchange{name: domain.ExchangeOKX, buy: 7.02, sell: 7.10},
+		},
+		sourceAwareForex{rate: 6.9, source: "test"},
+		&recordingNotifier{},
+	)
+	svc.setLastForex(testForexPair, 6.9, time.Now())
+
+	svc.checkC2CTargets(context.Background(), []c2cTarget{{exchange: domain.ExchangeGate, market: testMarket, amount: 0}})
+	svc.waitForDeliveries()
+	repo.pricesMu.Lock()
+	spreads := len(repo.savedSpreads)
+	repo.pricesMu.Unlock()
+	if spreads != 0 {
+		t.Fatalf("expected no spread from a single exchange, got %d", spreads)
+	}
+
+	svc.checkC2CTargets(context.Background(), []c2cTarget{{exchange: domain.ExchangeOKX, market: testMarket, amount: 0}})
+	svc.waitForDeliveries()
+	repo.pricesMu.Lock()
+	saved := append([]*domain.RoundTripSpread(nil), repo.savedSpreads...)
+	repo.pricesMu.Unlock()
+	if len(saved) != 2 {
+		t.Fatalf("expected OKX prices to pair with the earlier Gate prices, got %d spreads", len(saved))
+	}
+	if saved[0].SellExchange != domain.ExchangeGate || saved[0].BuyExchange != domain.ExchangeOKX ||
+		math.Abs(saved[0].Spread-0.03) > 1e-9 {
+		t.Fatalf("unexpected Gate->OKX spread: %#v", saved[0])
+	}
+
+	svc.mu.Lock()
+	for key, p := range svc.latestPrices {
+		p.CreatedAt = time.Now().Add(-cfg.RoundTripMaxPriceAge() - time.Minute)
+		svc.latestPrices[key] = p
+	}
+	svc.mu.Unlock()
+	svc.checkC2CTargets(context.Background(), []c2cTarget{{exchange: domain.ExchangeOKX, market: testMarket, amount: 0}})
+	svc.waitForDeliveries()
+	repo.pricesMu.Lock()
+	total := len(repo.savedSpreads)
+	repo.pricesMu.Unlock()
+	if total != 2 {
+		t.Fatalf("expected stale Gate prices not to be paired, got %d spreads", total)
+	}
+}
+
+func TestCheckC2CCollectsEveryConfiguredMarket(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testMonitorConfig()
+	cfg.Markets = []config.MarketConfig{
+		{Symbol: "USDT", Fiat: "CNY", ForexBase: "USD"},
+		{Symbol: "BTC", Fiat: "CNY", TargetAmounts: []float64{1000}},
+	}
+	exchange := &marketRecordingExchange{}
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		map[string]domain.IExchange{domain.ExchangeGate: exchange},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	got := exchange.requestedTargets()
+	sort.Strings(got)
+	want := []string{"BTC/CNY 1000", "USDT/CNY 0", "USDT/CNY 30"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected requests %v, got %v", want, got)
+	}
+	if status := svc.GetServiceStatuses()[domain.ExchangeGate]; status == nil || status.Status != "OK" {
+		t.Fatalf("expected every market tier to count towards health, got %#v", status)
+	}
+}
+
+func TestCheckC2CDerivesAmountTiersFromOrderBook(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{0, 30, 5000}
+	exchange := &orderBookTestExchange{}
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		map[string]domain.IExchange{domain.ExchangeGate: exchange},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	if got := exchange.books.Load(); got != 1 {
+		t.Fatalf("expected one order book request for every tier, got %d", got)
+	}
+	if got := exchange.tiers.Load(); got != 0 {
+		t.Fatalf("expected no per-tier requests, got %d", got)
+	}
+	best := make(map[float64]string)
+	var snapshot time.Time
+	for _, point := range repo.savedPricePoints() {
+		if point.Rank == 1 {
+			best[point.TargetAmount] = point.Merchant
+		}
+		if !snapshot.IsZero() && !point.CreatedAt.Equal(snapshot) {
+			t.Fatalf("expected every tier to share the book's snapshot time, got %s and %s", snapshot, point.CreatedAt)
+		}
+		snapshot = point.CreatedAt
+	}
+	want := map[float64]string{0: "small", 30: "small", 5000: "large"}
+	if !reflect.DeepEqual(best, want) {
+		t.Fatalf("expected best ads by tier %v, got %v", want, best)
+	}
+}
+
+func TestCheckC2CTracksPayMethodTiersSeparately(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{30}
+	cfg.PayMethodFilters = []string{"银行卡"}
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		map[string]domain.IExchange{domain.ExchangeGate: payMethodTestExchange{}},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	best := make(map[string]*domain.PricePoint)
+	for _, point := range repo.savedPricePoints() {
+		if point.Rank == 1 {
+			best[point.PayMethodFilter] = point
+		}
+	}
+	if got := best[""]; got == nil || got.Price != 7.0 {
+		t.Fatalf("expected the any-method tier to keep the cheapest ad, got %#v", got)
+	}
+	if got := best["银行卡"]; got == nil || got.Price != 7.1 || got.PayMethods != "银行卡" {
+		t.Fatalf("expected the bank-card tier to skip non-bank ads, got %#v", got)
+	}
+
+	states := svc.GetAlertStates()
+	if got := states[domain.AlertStateKey(domain.ExchangeGate, testMarket, "BUY", 30, "")]; got != 7.0 {
+		t.Fatalf("expected any-method alert state at 7.0, got %f", got)
+	}
+	if got := states[domain.AlertStateKey(domain.ExchangeGate, testMarket, "BUY", 30, "银行卡")]; got != 7.1 {
+		t.Fatalf("expected bank-card alert state at 7.1, got %f", got)
+	}
+}
+
+func TestCheckC2CSkipsPayMethodFiltersTheExchangeCannotApply(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{30}
+	cfg.PayMethodFilters = []string{"银行卡", "QQ 钱包"}
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		map[string]domain.IExchange{domain.ExchangeGate: payMethodFiltererTestExchange{supported: "银行卡"}},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	filters := make(map[string]bool)
+	for _, point := range repo.savedPricePoints() {
+		filters[point.PayMethodFilter] = true
+	}
+	if !filters[""] || !filters["银行卡"] || filters["QQ 钱包"] {
+		t.Fatalf("expected only the supported tiers to be collected, got %v", filters)
+	}
+	if status := svc.GetServiceStatuses()[domain.ExchangeGate]; status.Status != "OK" {
+		t.Fatalf("expected the skipped tier not to degrade the exchange, got %#v", status)
+	}
+}
+
+func TestCheckC2CSkipsAdsBelowMerchantFilter(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testMonitorConfig()
+	cfg.TargetAmounts = []float64{30}
+	cfg.Depth = 5
+	cfg.MerchantFilter = config.MerchantFilterConfig{MinCompletionRate: 95, MinMonthOrders: 100}
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		map[string]domain.IExchange{domain.ExchangeGate: merchantFilterTestExchange{}},
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+
+	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
+
+	saved := repo.savedPricePoints()
+	if len(saved) != 2 {
+		t.Fatalf("expected the new merchant to be excluded, got %d points", len(saved))
+	}
+	ranked := make(map[int]float64)
+	for _, point := range saved {
+		ranked[point.Rank] = point.Price
+	}
+	if ranked[1] != 7.0 || ranked[2] != 7.05 {
+		t.Fatalf("expected established and unreported merchants ranked 1 and 2, got %v", ranked)
+	}
+	if got := svc.GetAlertStates()[domain.AlertStateKey(domain.ExchangeGate, testMarket, "BUY", 30, "")]; got != 7.0 {
+		t.Fatalf("expected alert state from the established merchant, got %f", got)
+	}
+}
+
+func TestAlertBenchmarksAreScopedPerMarket(t *testing.T) {
+	repo := &stubRepository{}
+	cfg := testMonitorConfig()
+	cfg.Markets = []config.MarketConfig{
+		{Symbol: "USDT", Fiat: "CNY", ForexBase: "USD"},
+		{Symbol: "USDT", Fiat: "HKD", ForexBase: "USD"},
+	}
+	svc := NewMonitorService(
+		cfg,
+		repo,
+		nil,
+		sourceAwareForex{rate: 7.2, source: "test"},
+		stubNotifier{},
+	)
+	svc.setLastForex(testForexPair, 7.2, time.Now())
+	svc.setLastForex("USDHKD", 7.8, time.Now())
+
+	status, err := svc.UpdateAlertBenchmark(context.Background(), "USDT/HKD", 7.7, nil)
+	if err != nil {
+		t.Fatalf("UpdateAlertBenchmark for USDT/HKD returned error: %v", err)
+	}
+	if status.Market != "USDT/HKD" || status.BenchmarkPrice != 7.7 || repo.alertBenchmark.Pair != "USDT/HKD" {
+		t.Fatalf("unexpected USDT/HKD benchmark: status=%#v persisted=%#v", status, repo.alertBenchmark)
+	}
+
+	status, err = svc.GetAlertBenchmark(context.Background(), "", nil)
+	if err != nil {
+		t.Fatalf("GetAlertBenchmark for default market returned error: %v", err)
+	}
+	if status.Market != testMarket || status.BenchmarkPrice != 7.2 {
+		t.Fatalf("expected USDT/CNY benchmark to stay on its own Forex rate, got %#v", status)
+	}
+
+	if _, err := svc.GetAlertBenchmark(context.Background(), "USDC/CNY", nil); !errors.Is(err, ErrInvalidMarket) {
+		t.Fatalf("expected unconfigured market to be rejected, got %v", err)
+	}
+}
+
 func TestCheckC2CMarksPartialAmountCoverageDegraded(t *testing.T) {
 	svc := NewMonitorService(
 		testMonitorConfig(),
@@ -469,9 +863,10 @@ func TestCheckC2CMarksPartialAmountCoverageDegraded(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		stubNotifier{},
 	)
-	svc.setLastForex(7.2, time.Now())
+	svc.setLastForex(testForexPair, 7.2, time.Now())
 
 	svc.checkC2C(context.Background())
+	svc.waitForDeliveries()
 
 	status := svc.GetServiceStatuses()[domain.ExchangeGate]
 	if status == nil || status.Status != "Degraded" {
@@ -498,11 +893,12 @@ func TestCheckC2CDoesNotRestoreRemovedExchangeStatus(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		stubNotifier{},
 	)
-	svc.setLastForex(7.2, time.Now())
+	svc.setLastForex(testForexPair, 7.2, time.Now())
 
 	done := make(chan struct{})
 	go func() {
 		svc.checkC2C(context.Background())
+		svc.waitForDeliveries()
 		close(done)
 	}()
 
@@ -543,10 +939,10 @@ func TestResetAlertStateLeavesMemoryIntactWhenDeleteFails(t *testing.T) {
 		sourceAwareForex{rate: 7.2, source: "test"},
 		stubNotifier{},
 	)
-	key := domain.AlertStateKey(domain.ExchangeGate, "BUY", 0)
+	key := domain.AlertStateKey(domain.ExchangeGate, testMarket, "BUY", 0, "")
 	svc.triggeredLowPrices[key] = 7.0
 
-	err := svc.ResetAlertState(context.Background(), domain.ExchangeGate, "BUY", 0)
+	err := svc.ResetAlertState(context.Background(), domain.ExchangeGate, testMarket, "BUY", 0, "")
 	if err == nil {
 		t.Fatal("expected reset error")
 	}
@@ -578,6 +974,11 @@ func TestUpdateConfigNotifiesSchedulers(t *testing.T) {
 	}
 }
 
+const (
+	testMarket    = "USDT/CNY"
+	testForexPair = "USDCNY"
+)
+
 func testMonitorConfig() config.MonitorConfig {
 	return config.MonitorConfig{
 		C2CIntervalMinutes: 3,
@@ -588,6 +989,39 @@ func testMonitorConfig() config.MonitorConfig {
 	}
 }
 
+func TestLatestPricesSortsBestFirstPerSide(t *testing.T) {
+	svc := NewMonitorService(testMonitorConfig(), &stubRepository{}, map[string]domain.IExchange{}, sourceAwareForex{rate: 7.2, source: "test"}, stubNotifier{})
+	for _, p := range []domain.PricePoint{
+		{Exchange: domain.ExchangeGate, Side: domain.SideBuy, Price: 7.01},
+		{Exchange: domain.ExchangeBinance, Side: domain.SideBuy, Price: 6.98},
+		{Exchange: domain.ExchangeGate, Side: domain.SideSell, Price: 7.05},
+		{Exchange: domain.ExchangeBinance, Side: domain.SideSell, Price: 7.10},
+		{Exchange: domain.ExchangeOKX, Side: domain.SideBuy, Price: 6.90, TargetAmount: 30},
+	} {
+		p.Symbol, p.Fiat = "USDT", "CNY"
+		if p.TargetAmount == 0 {
+			p.TargetAmount = 1000
+		}
+		svc.setLatestPrice(p)
+	}
+
+	prices, err := svc.LatestPrices("", 1000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range prices {
+		got = append(got, fmt.Sprintf("%s %s %.2f", p.Side, p.Exchange, p.Price))
+	}
+	want := "BUY Binance 6.98,BUY Gate 7.01,SELL Binance 7.10,SELL Gate 7.05"
+	if strings.Join(got, ",") != want {
+		t.Fatalf("expected %s, got %s", want, strings.Join(got, ","))
+	}
+	if _, err := svc.LatestPrices("BTC/USD", 1000); !errors.Is(err, ErrInvalidMarket) {
+		t.Fatalf("expected unknown markets to be rejected, got %v", err)
+	}
+}
+
 func testPricePoint(price, amount float64) domain.PricePoint {
 	return domain.PricePoint{
 		Exchange:     domain.ExchangeGate,
@@ -636,11 +1070,15 @@ func (n failingNotifier) Send(ctx context.Context, subject, body string) error {
 	return n.err
 }
 
+// recordingNotifier counts sends; read calls after waitForDeliveries.
 type recordingNotifier struct {
+	mu    sync.Mutex
 	calls int
 }
 
 func (n *recordingNotifier) Send(ctx context.Context, subject, body string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
 	n.calls++
 	return nil
 }
@@ -658,15 +1096,126 @@ func (n *disabledTestNotifier) Send(ctx context.Context, subject, body string) e
 	return nil
 }
 
+// payMethodFiltererTestExchange can filter on one pay method only and fails
+// every other filter.
+type payMethodFiltererTestExchange struct {
+	supported string
+}
+
+func (e payMethodFiltererTestExchange) SupportsPayMethod(payMethod string) bool {
+	return payMethod == "" || payMethod == e.supported
+}
+
+func (e payMethodFiltererTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	if !e.SupportsPayMethod(payMethod) {
+		return nil, &domain.UnsupportedQueryError{Exchange: "gate", Reason: "pay method filter " + payMethod}
+	}
+	return []domain.PricePoint{{Price: 7.1, Rank: 1, PayMethods: "银行卡"}}, nil
+}
+
 type staticTestExchange struct{}
 
-func (staticTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64) ([]domain.PricePoint, error) {
+func (staticTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
 	return []domain.PricePoint{testPricePoint(7.0, amount)}, nil
 }
 
+type deepTestExchange struct{}
+
+func (deepTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	points := make([]domain.PricePoint, 0, 4)
+	for index, price := range []float64{7.0, 7.01, 7.02, 7.03} {
+		point := testPricePoint(price, amount)
+		point.Rank = index + 1
+		points = append(points, point)
+	}
+	return points, nil
+}
+
+type sidedTestExchange struct {
+	name      string
+	buy, sell float64
+}
+
+func (e sidedTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	point := testPricePoint(e.buy, amount)
+	point.Exchange = e.name
+	point.Side = side
+	point.CreatedAt = time.Now()
+	if side == domain.SideSell {
+		point.Price = e.sell
+	}
+	return []domain.PricePoint{point}, nil
+}
+
+type marketRecordingExchange struct {
+	mu       sync.Mutex
+	requests []string
+}
+
+func (e *marketRecordingExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	e.mu.Lock()
+	e.requests = append(e.requests, fmt.Sprintf("%s %.0f", domain.MarketKey(symbol, fiat), amount))
+	e.mu.Unlock()
+
+	point := testPricePoint(7.3, amount)
+	point.Symbol = symbol
+	point.Fiat = fiat
+	return []domain.PricePoint{point}, nil
+}
+
+type orderBookTestExchange struct {
+	books atomic.Int32
+	tiers atomic.Int32
+}
+
+func (e *orderBookTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	e.tiers.Add(1)
+	return nil, errors.New("unexpected per-tier request")
+}
+
+func (e *orderBookTestExchange) GetOrderBook(ctx context.Context, symbol, fiat, side, payMethod string) ([]domain.PricePoint, error) {
+	e.books.Add(1)
+	createdAt := time.Now()
+	small := testPricePoint(7.0, 0)
+	small.Merchant, small.Rank, small.MaxAmount, small.CreatedAt = "small", 1, 1000, createdAt
+	large := testPricePoint(7.1, 0)
+	large.Merchant, large.Rank, large.MinAmount, large.MaxAmount, large.CreatedAt = "large", 2, 1000, 50000, createdAt
+	return []domain.PricePoint{small, large}, nil
+}
+
+func (e *marketRecordingExchange) requestedTargets() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return append([]string(nil), e.requests...)
+}
+
+// payMethodTestExchange ignores the filter on purpose, like a venue that
+// silently drops unknown parameters.
+type payMethodTestExchange struct{}
+
+func (payMethodTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	alipay := testPricePoint(7.0, amount)
+	alipay.PayMethods = "支付宝"
+	bank := testPricePoint(7.1, amount)
+	return []domain.PricePoint{alipay, bank}, nil
+}
+
+type merchantFilterTestExchange struct{}
+
+func (merchantFilterTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
+	rate, orders := 100.0, 3
+	newMerchant := testPricePoint(6.9, amount)
+	newMerchant.Reputation = domain.MerchantReputation{CompletionRate: &rate, MonthOrders: &orders}
+	establishedRate, establishedOrders := 98.7, 812
+	established := testPricePoint(7.0, amount)
+	established.Reputation = domain.MerchantReputation{CompletionRate: &establishedRate, MonthOrders: &establishedOrders}
+	unreported := testPricePoint(7.05, amount)
+	return []domain.PricePoint{newMerchant, established, unreported}, nil
+}
+
 type partialTestExchange struct{}
 
-func (partialTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64) ([]domain.PricePoint, error) {
+func (partialTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
 	if amount == 0 {
 		return []domain.PricePoint{testPricePoint(7.0, amount)}, nil
 	}
@@ -678,7 +1227,7 @@ type blockingTestExchange struct {
 	release chan struct{}
 }
 
-func (e *blockingTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64) ([]domain.PricePoint, error) {
+func (e *blockingTestExchange) GetTopPrices(ctx context.Context, symbol, fiat, side string, amount float64, payMethod string) ([]domain.PricePoint, error) {
 	select {
 	case e.started <- struct{}{}:
 	default:
@@ -701,18 +1250,49 @@ type stubRepository struct {
 	deleteAlertErr       error
 	savedPriceBatches    int64
 	benchmarkSaveCounter int64
+	pricesMu             sync.Mutex
+	savedPrices          []*domain.PricePoint
+	savedSpreads         []*domain.RoundTripSpread
+	enqueueErr           error
+	outboxMu             sync.Mutex
+	outbox               []domain.OutboxAlert
+	alertRecords         []domain.AlertRecord
+	aggregatedPrices     []*domain.PricePoint
+	aggregatedForex      []*domain.ForexRate
+	lastGranularity      domain.HistoryGranularity
 }
 
 func (r *stubRepository) SavePricePoints(ctx context.Context, points []*domain.PricePoint) error {
 	atomic.AddInt64(&r.savedPriceBatches, 1)
+	r.pricesMu.Lock()
+	r.savedPrices = append(r.savedPrices, points...)
+	r.pricesMu.Unlock()
 	return nil
 }
 
+func (r *stubRepository) savedPricePoints() []*domain.PricePoint {
+	r.pricesMu.Lock()
+	defer r.pricesMu.Unlock()
+	return append([]*domain.PricePoint(nil), r.savedPrices...)
+}
+
 func (r *stubRepository) GetPriceHistory(ctx context.Context, filter domain.PriceQueryFilter) ([]*domain.PricePoint, error) {
 	return nil, nil
 }
 
 func (r *stubRepository) GetPriceHistoryByGranularity(ctx context.Context, filter domain.PriceQueryFilter, granularity domain.HistoryGranularity) ([]*domain.PricePoint, error) {
+	r.lastGranularity = granularity
+	return r.aggregatedPrices, nil
+}
+
+func (r *stubRepository) SaveRoundTripSpreads(ctx context.Context, spreads []*domain.RoundTripSpread) error {
+	r.pricesMu.Lock()
+	r.savedSpreads = append(r.savedSpreads, spreads...)
+	r.pricesMu.Unlock()
+	return nil
+}
+
+func (r *stubRepository) GetRoundTripSpreadHistory(ctx context.Context, filter domain.SpreadQueryFilter) ([]*domain.RoundTripSpread, error) {
 	return nil, nil
 }
 
@@ -739,7 +1319,7 @@ func (r *stubRepository) GetForexHistory(ctx context.Context, pair string, start
 }
 
 func (r *stubRepository) GetForexHistoryByGranularity(ctx context.Context, pair string, start, end time.Time, granularity domain.HistoryGranularity) ([]*domain.ForexRate, error) {
-	return nil, nil
+	return r.aggregatedForex, nil
 }
 
 func (r *stubRepository) UpsertAlertState(ctx context.Context, state *domain.AlertState) error {
@@ -748,7 +1328,7 @@ func (r *stubRepository) UpsertAlertState(ctx context.Context, state *domain.Ale
 	return nil
 }
 
-func (r *stubRepository) DeleteAlertState(ctx context.Context, exchange, side string, amount float64) error {
+func (r *stubRepository) DeleteAlertState(ctx context.Context, exchange, symbol, fiat, side string, amount float64, payMethod string) error {
 	return r.deleteAlertErr
 }
 
@@ -797,3 +1377,98 @@ func (r *stubRepository) GetAlertBenchmarkOverrides(ctx context.Context, pair st
 	}
 	return results, nil
 }
+
+func (r *stubRepository) EnqueueAlert(ctx context.Context, alert *domain.OutboxAlert, record *domain.AlertRecord, state *domain.AlertState) error {
+	if r.enqueueErr != nil {
+		return r.enqueueErr
+	}
+	r.outboxMu.Lock()
+	defer r.outboxMu.Unlock()
+	if state != nil {
+		copyState := *state
+		r.savedAlert = &copyState
+	}
+	alert.ID = int64(len(r.outbox) + 1)
+	r.outbox = append(r.outbox, *alert)
+	if record != nil {
+		record.OutboxID = alert.ID
+		record.ID = int64(len(r.alertRecords) + 1)
+		r.alertRecords = append(r.alertRecords, *record)
+	}
+	return nil
+}
+
+func (r *stubRepository) GetDueAlerts(ctx context.Context, now time.Time, limit int) ([]*domain.OutboxAlert, error) {
+	r.outboxMu.Lock()
+	defer r.outboxMu.Unlock()
+	var due []*domain.OutboxAlert
+	for _, alert := range r.outbox {
+		if alert.Status == domain.AlertPending && !alert.NextAttemptAt.After(now) && len(due) < limit {
+			copyAlert := alert
+			due = append(due, &copyAlert)
+		}
+	}
+	return due, nil
+}
+
+func (r *stubRepository) ClaimAlert(ctx context.Context, id int64, now, until time.Time) (bool, error) {
+	r.outboxMu.Lock()
+	defer r.outboxMu.Unlock()
+	alert := &r.outbox[id-1]
+	if alert.Status != domain.AlertPending || alert.NextAttemptAt.After(now) {
+		return false, nil
+	}
+	alert.NextAttemptAt = until
+	return true, nil
+}
+
+func (r *stubRepository) UpdateAlertDelivery(ctx context.Context, alert *domain.OutboxAlert) error {
+	r.outboxMu.Lock()
+	defer r.outboxMu.Unlock()
+	r.outbox[alert.ID-1] = *alert
+	return nil
+}
+
+func (r *stubRepository) GetAlertOutbox(ctx context.Context, filter domain.AlertOutboxFilter) ([]*domain.OutboxAlert, error) {
+	r.outboxMu.Lock()
+	defer r.outboxMu.Unlock()
+	var alerts []*domain.OutboxAlert
+	for i := len(r.outbox) - 1; i >= 0; i-- {
+		if filter.Status == "" || r.outbox[i].Status == filter.Status {
+			copyAlert := r.outbox[i]
+			alerts = append(alerts, &copyAlert)
+		}
+	}
+	return alerts, nil
+}
+
+func (r *stubRepository) RequeueAlert(ctx context.Context, id int64, now time.Time) (bool, error) {
+	r.outboxMu.Lock()
+	defer r.outboxMu.Unlock()
+	if id < 1 || int(id) > len(r.outbox) || r.outbox[id-1].Status != domain.AlertDead {
+		return false, nil
+	}
+	r.outbox[id-1].Status = domain.AlertPending
+	r.outbox[id-1].Attempts = 0
+	r.outbox[id-1].NextAttemptAt = now
+	return true, nil
+}
+
+func (r *stubRepository) GetAlertHistory(ctx context.Context, filter domain.AlertHistoryFilter) ([]*domain.AlertRecord, int64, error) {
+	r.outboxMu.Lock()
+	defer r.outboxMu.Unlock()
+	var records []*domain.AlertRecord
+	for i := len(r.alertRecords) - 1; i >= 0; i-- {
+		if filter.Kind == "" || r.alertRecords[i].Kind == filter.Kind {
+			copyRecord := r.alertRecords[i]
+			records = append(records, &copyRecord)
+		}
+	}
+	return records, int64(len(records)), nil
+}
+
+func (r *stubRepository) outboxAlerts() []domain.OutboxAlert {
+	r.outboxMu.Lock()
+	defer r.outboxMu.Unlock()
+	return append([]domain.OutboxAlert(nil), r.outbox...)
+}
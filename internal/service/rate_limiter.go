@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"c2c_monitor/config"
+	"c2c_monitor/internal/domain"
+)
+
+// rateLimitWaitLogThreshold is the wait from which exchange_rate_limit_wait is
+// logged at Info; shorter waits are routine pacing and logged at Debug.
+const rateLimitWaitLogThreshold = time.Second
+
+// rateLimiter is a token bucket shared by every request to one exchange.
+// Callers reserve a token up front, so concurrent tiers queue behind each other
+// and a round's requests are spread out at the configured rate instead of
+// bursting at the start of the interval.
+type rateLimiter struct {
+	mu        sync.Mutex
+	limit     config.RateLimitConfig
+	tokens    float64
+	last      time.Time
+	requests  int64
+	waited    int64 // Requests that had to wait for a token
+	totalWait time.Duration
+	lastWait  time.Duration
+	maxWait   time.Duration
+}
+
+func newRateLimiter() *rateLimiter {
+	return &rateLimiter{}
+}
+
+// wait blocks until the next request may be sent under limit and returns how
+// long it waited. A zero rate never waits. The limit is read per call so that
+// config updates apply to the next request.
+func (l *rateLimiter) wait(ctx context.Context, limit config.RateLimitConfig, now time.Time) (time.Duration, error) {
+	delay := l.reserve(limit, now)
+	if delay <= 0 {
+		return 0, nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.cancel(limit)
+		return 0, ctx.Err()
+	case <-timer.C:
+		return delay, nil
+	}
+}
+
+// reserve takes a token, letting the bucket go negative; the deficit is the
+// delay before the reserved request may be sent.
+func (l *rateLimiter) reserve(limit config.RateLimitConfig, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.requests++
+	if limit.RequestsPerSecond <= 0 {
+		l.limit = limit
+		l.lastWait = 0
+		return 0
+	}
+
+	burst := float64(limit.BurstSize())
+	if l.last.IsZero() {
+		l.tokens = burst
+		l.last = now
+	}
+	l.limit = limit
+	if elapsed := now.Sub(l.last); elapsed > 0 {
+		l.tokens += elapsed.Seconds() * limit.RequestsPerSecond
+		l.last = now
+	}
+	if l.tokens > burst {
+		l.tokens = burst
+	}
+
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / limit.RequestsPerSecond * float64(time.Second))
+		l.waited++
+		l.totalWait += delay
+		if delay > l.maxWait {
+			l.maxWait = delay
+		}
+	}
+	l.lastWait = delay
+	return delay
+}
+
+// cancel returns the token of a request abandoned while waiting.
+func (l *rateLimiter) cancel(limit config.RateLimitConfig) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit == limit {
+		l.tokens++
+	}
+}
+
+func (l *rateLimiter) status() *domain.RateLimitStatus {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	status := &domain.RateLimitStatus{
+		RequestsPerSecond: l.limit.RequestsPerSecond,
+		Requests:          l.requests,
+		WaitedRequests:    l.waited,
+		LastWaitSeconds:   l.lastWait.Seconds(),
+		MaxWaitSeconds:    l.maxWait.Seconds(),
+		TotalWaitSeconds:  l.totalWait.Seconds(),
+	}
+	if l.limit.RequestsPerSecond > 0 {
+		status.Burst = l.limit.BurstSize()
+	}
+	return status
+}
+
+func (s *MonitorService) rateLimiter(exchangeName string) *rateLimiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limiter, ok := s.limiters[exchangeName]
+	if !ok {
+		limiter = newRateLimiter()
+		s.limiters[exchangeName] = limiter
+	}
+	return limiter
+}
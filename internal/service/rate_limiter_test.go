@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"c2c_monitor/config"
+)
+
+func TestRateLimiterSpreadsReservations(t *testing.T) {
+	limiter := newRateLimiter()
+	limit := config.RateLimitConfig{RequestsPerSecond: 2, Burst: 1}
+	now := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	var got []time.Duration
+	for i := 0; i < 3; i++ {
+		got = append(got, limiter.reserve(limit, now))
+	}
+	want := []time.Duration{0, 500 * time.Millisecond, time.Second}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("reservation %d: expected delay %s, got %s", i, want[i], got[i])
+		}
+	}
+
+	// Two seconds later the queued requests are paid off and one token is back.
+	if delay := limiter.reserve(limit, now.Add(2*time.Second)); delay != 0 {
+		t.Fatalf("expected refilled bucket, got delay %s", delay)
+	}
+
+	status := limiter.status()
+	if status.Requests != 4 || status.WaitedRequests != 2 || status.MaxWaitSeconds != 1 || status.TotalWaitSeconds != 1.5 || status.Burst != 1 {
+		t.Fatalf("unexpected limiter status: %#v", status)
+	}
+}
+
+func TestRateLimiterDisabledAndCancelled(t *testing.T) {
+	limiter := newRateLimiter()
+	now := time.Now()
+	if waited, err := limiter.wait(context.Background(), config.RateLimitConfig{}, now); err != nil || waited != 0 {
+		t.Fatalf("expected zero rate not to wait, got %s, %v", waited, err)
+	}
+
+	limit := config.RateLimitConfig{RequestsPerSecond: 0.001}
+	if _, err := limiter.wait(context.Background(), limit, now); err != nil {
+		t.Fatalf("expected the burst token, got %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := limiter.wait(ctx, limit, now); err == nil {
+		t.Fatal("expected cancelled wait to return the context error")
+	}
+	if delay := limiter.reserve(limit, now); delay <= 0 || delay > 1001*time.Second {
+		t.Fatalf("expected the cancelled token to be returned, got delay %s", delay)
+	}
+}